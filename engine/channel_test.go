@@ -0,0 +1,33 @@
+package engine
+
+import "testing"
+
+func TestChannelErrorsMatchAcrossEngines(t *testing.T) {
+	tests := []struct {
+		source  string
+		message string
+	}{
+		{`recv(1);`, "Expected a channel, got 'INTEGER'"},
+		{`select();`, "Expected at least 1 channel"},
+		{`select(1);`, "Expected a channel, got 'INTEGER'"},
+		{`channel(-1);`, "Channel capacity must be a non-negative whole number, got -1"},
+		{`var c = channel(); close(c); send(c, 1);`, "Cannot send to closed channel #1"},
+		{`var c = channel(); close(c); close(c);`, "Channel #1 is already closed"},
+		{`var c = channel(); recv(c);`, "Deadlock, every task is blocked: Task main (receiving from channel #1)"},
+	}
+
+	for backendName, backend := range backends {
+		for _, tt := range tests {
+			t.Run(backendName+"/"+tt.source, func(t *testing.T) {
+				err := runError(t, backend, tt.source)
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+
+				if err.Message != tt.message {
+					t.Errorf("wrong message\nexpected: %q\ngot:      %q", tt.message, err.Message)
+				}
+			})
+		}
+	}
+}
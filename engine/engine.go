@@ -0,0 +1,396 @@
+// Package engine is the embedding API for running Monkey scripts from Go.
+//
+//	eng := engine.New()
+//	script, err := eng.Compile(`fn greet(name) { return "hi " + name; }`)
+//	_, err = eng.Run(script)
+//	greeting, err := eng.Call("greet", &object.String{Value: "bob"})
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/evaluation"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
+	"github.com/caelondev/monkey-compiler-go/src/vm"
+)
+
+type Backend int
+
+const (
+	// Tree-walking evaluator, supports the whole language ---
+	EvaluatorBackend Backend = iota
+	// Bytecode compiler + VM ---
+	VMBackend
+)
+
+// Engine owns the host-side state shared by every run:
+// globals set from Go and the default stdout/stdin.
+// An Engine is not safe for concurrent use.
+type Engine struct {
+	Stdout io.Writer
+	Stdin  io.Reader
+
+	// Backend must be chosen before compiling scripts.
+	Backend Backend
+
+	hostGlobals map[string]object.Object
+	hostOrder   []string
+	registry    *object.Registry
+
+	evaluator evaluation.Evaluator
+	env       *object.Environment // Globals of the most recent run ---
+
+	machine  *vm.VM  // VM of the most recent run ---
+	vmScript *Script // Script that machine ran ---
+}
+
+// Script is a parsed (and for the VM backend, compiled) program.
+// It is never mutated, so it can be run any number of times.
+type Script struct {
+	Name    string
+	Source  string
+	program *ast.Program
+
+	// VMBackend only ---
+	bytecode  *compiler.Bytecode
+	symbols   *compiler.SymbolTable
+	hostSlots []string // Host globals/functions, in global slot order ---
+}
+
+func New() *Engine {
+	eng := &Engine{
+		Stdout:      os.Stdout,
+		Stdin:       os.Stdin,
+		hostGlobals: make(map[string]object.Object),
+		hostOrder:   make([]string, 0),
+		registry:    object.NewRegistry(),
+		evaluator:   evaluation.New(),
+	}
+
+	eng.evaluator.Registry = eng.registry
+	eng.registry.Invoker = eng.CallValue
+
+	eng.env = eng.newGlobalEnvironment()
+	return eng
+}
+
+// Compile parses source once; the returned Script can be passed to Run repeatedly.
+func (e *Engine) Compile(source string) (*Script, error) {
+	return e.CompileNamed("<script>", source)
+}
+
+func (e *Engine) CompileNamed(name, source string) (*Script, error) {
+	l := lexer.New(source)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		return nil, newParseError(p.Errors()[0])
+	}
+
+	program, expandErr := evaluation.Expand(program)
+	if expandErr != nil {
+		return nil, newRuntimeError(expandErr)
+	}
+
+	script := &Script{Name: name, Source: source, program: program}
+
+	if e.Backend == VMBackend {
+		err := e.compileBytecode(script)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return script, nil
+}
+
+// Host globals and registered functions are compiled as the first ---
+// global slots, so a script can only read names known at compile time ---
+func (e *Engine) compileBytecode(script *Script) error {
+	symbols := compiler.NewSymbolTable()
+	hostSlots := make([]string, 0, len(e.hostOrder))
+
+	for _, name := range e.hostNames() {
+		if _, exists := symbols.Define(name); !exists {
+			hostSlots = append(hostSlots, name)
+		}
+	}
+
+	comp := compiler.NewWithState(symbols, make([]object.Object, 0))
+	comp.File = script.Name
+	err := comp.Compile(script.program)
+	if err != nil {
+		return newCompileError(err)
+	}
+
+	script.bytecode = comp.Bytecode()
+	script.symbols = symbols
+	script.hostSlots = hostSlots
+	return nil
+}
+
+func (e *Engine) CompileFile(path string) (*Script, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.CompileNamed(path, string(bytes))
+}
+
+// Run executes the script in a fresh global scope seeded with the host globals,
+// writing to e.Stdout and reading from e.Stdin.
+func (e *Engine) Run(script *Script) (object.Object, error) {
+	return e.RunWithIO(script, e.Stdin, e.Stdout)
+}
+
+// RunWithIO is Run with stdout/stdin redirected for this run only.
+func (e *Engine) RunWithIO(script *Script, stdin io.Reader, stdout io.Writer) (object.Object, error) {
+	if e.Backend == VMBackend {
+		return e.runBytecode(script, stdin, stdout)
+	}
+
+	e.env = e.newGlobalEnvironment()
+	e.evaluator.File = script.Name
+
+	return e.withIO(stdin, stdout, func() object.Object {
+		return e.evaluator.Evaluate(script.program, e.env)
+	})
+}
+
+// Eval compiles and runs source in one step.
+func (e *Engine) Eval(source string) (object.Object, error) {
+	script, err := e.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Run(script)
+}
+
+func (e *Engine) runBytecode(script *Script, stdin io.Reader, stdout io.Writer) (result object.Object, err error) {
+	defer recoverVM(&result, &err)
+
+	if script.bytecode == nil {
+		return nil, &Error{Kind: HostError, Message: "script was not compiled for the VM backend"}
+	}
+
+	script, err = e.recompile(script)
+	if err != nil {
+		return nil, err
+	}
+
+	globals := make([]object.Object, vm.GLOBAL_SIZE)
+	for i, name := range script.hostSlots {
+		globals[i] = e.hostValue(name)
+	}
+
+	machine := vm.NewWithGlobalStore(script.bytecode, globals)
+	machine.Stdout, machine.Stdin = stdout, stdin
+	e.machine, e.vmScript = machine, script
+
+	err = machine.Run()
+	if err != nil {
+		return nil, newVMError(err)
+	}
+
+	result = machine.LastPoppedElement()
+	if result == nil {
+		return object.NIL, nil
+	}
+
+	return result, nil
+}
+
+// Names set or registered after Compile have no slot in the script, ---
+// it is compiled again for this run so Global and Call see them too ---
+func (e *Engine) recompile(script *Script) (*Script, error) {
+	if len(script.hostSlots) == len(e.hostNames()) {
+		return script, nil
+	}
+
+	fresh := &Script{Name: script.Name, Source: script.Source, program: script.program}
+	err := e.compileBytecode(fresh)
+	if err != nil {
+		return nil, err
+	}
+
+	return fresh, nil
+}
+
+// Host globals then registered functions, without duplicates ---
+func (e *Engine) hostNames() []string {
+	seen := make(map[string]bool, len(e.hostOrder))
+	names := make([]string, 0, len(e.hostOrder))
+
+	for _, name := range append(append([]string{}, e.hostOrder...), e.registry.Names()...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func (e *Engine) hostValue(name string) object.Object {
+	if value, ok := e.hostGlobals[name]; ok {
+		return value
+	}
+
+	if fn, ok := e.registry.Lookup(name); ok {
+		return fn
+	}
+
+	return object.NIL
+}
+
+// SetGlobal defines name for every following run
+// and for the globals of the most recent one.
+//
+// With VMBackend a script can only read names set before it was
+// compiled, Compile reports any other name as unresolved. A name set
+// later is still defined for the following runs, so Global and Call
+// see it, but the most recent run only gets it if it already had it.
+func (e *Engine) SetGlobal(name string, value object.Object) {
+	if _, exists := e.hostGlobals[name]; !exists {
+		e.hostOrder = append(e.hostOrder, name)
+	}
+
+	e.hostGlobals[name] = value
+
+	if e.Backend == VMBackend {
+		if symbol, ok := e.globalSymbol(name); ok {
+			e.machine.SetGlobal(symbol.Index, value)
+		}
+		return
+	}
+
+	e.env.Set(name, value)
+}
+
+// Global reads a global from the most recent run.
+func (e *Engine) Global(name string) (object.Object, bool) {
+	if e.Backend == VMBackend {
+		symbol, ok := e.globalSymbol(name)
+		if !ok {
+			return nil, false
+		}
+
+		value := e.machine.GetGlobal(symbol.Index)
+		if value == nil {
+			return object.NIL, true
+		}
+		return value, true
+	}
+
+	return e.env.Get(name)
+}
+
+func (e *Engine) globalSymbol(name string) (compiler.Symbol, bool) {
+	if e.machine == nil {
+		return compiler.Symbol{}, false
+	}
+
+	symbol, ok := e.vmScript.symbols.Resolve(name)
+	if !ok || symbol.Scope != compiler.GlobalScope {
+		return compiler.Symbol{}, false
+	}
+
+	return symbol, true
+}
+
+// Call invokes the global function name, as left by the most recent run.
+func (e *Engine) Call(name string, args ...object.Object) (object.Object, error) {
+	fn, ok := e.Global(name)
+	if !ok {
+		return nil, &Error{Kind: HostError, Message: fmt.Sprintf("Cannot call function '%s', as it is undefined", name)}
+	}
+
+	return e.CallValue(fn, args...)
+}
+
+// CallValue invokes any function object (e.g. one returned by a script,
+// or a callback passed into a registered Go function). It is safe to
+// call from inside a registered function while a script is running.
+func (e *Engine) CallValue(fn object.Object, args ...object.Object) (object.Object, error) {
+	if fn.Type() != object.FUNCTION_OBJECT {
+		return nil, &Error{Kind: HostError, Message: fmt.Sprintf("Attempted to call %s", fn.Type())}
+	}
+
+	if e.Backend == VMBackend {
+		if e.machine == nil {
+			return nil, &Error{Kind: HostError, Message: "no script has been run yet"}
+		}
+
+		return e.callBytecode(fn, args)
+	}
+
+	return e.withIO(e.Stdin, e.Stdout, func() object.Object {
+		return e.evaluator.CallFunction(fn, args)
+	})
+}
+
+func (e *Engine) callBytecode(fn object.Object, args []object.Object) (result object.Object, err error) {
+	defer recoverVM(&result, &err)
+
+	result, err = e.machine.CallFunction(fn, args...)
+	if err != nil {
+		return nil, newVMError(err)
+	}
+	return result, nil
+}
+
+// Like withIO, a VM panic (e.g. an operand the VM doesn't ---
+// expect) is turned into a RuntimeError ---
+func recoverVM(result *object.Object, err *error) {
+	if r := recover(); r != nil {
+		*result = nil
+		*err = &Error{Kind: RuntimeError, Message: fmt.Sprint(r)}
+	}
+}
+
+func (e *Engine) withIO(stdin io.Reader, stdout io.Writer, run func() object.Object) (result object.Object, err error) {
+	prevIn, prevOut := e.evaluator.Stdin, e.evaluator.Stdout
+	e.evaluator.Stdin, e.evaluator.Stdout = stdin, stdout
+
+	defer func() {
+		e.evaluator.Stdin, e.evaluator.Stdout = prevIn, prevOut
+
+		// The evaluator still panics on a few malformed operands,
+		// a host shouldn't go down with it ---
+		if r := recover(); r != nil {
+			result = nil
+			err = &Error{Kind: RuntimeError, Message: fmt.Sprint(r)}
+		}
+	}()
+
+	result = run()
+	if result == nil {
+		return object.NIL, nil
+	}
+
+	if errObj, ok := result.(*object.Error); ok {
+		return nil, newRuntimeError(errObj)
+	}
+
+	return result, nil
+}
+
+func (e *Engine) newGlobalEnvironment() *object.Environment {
+	env := object.NewEnvironment(nil)
+	e.evaluator.InitializeNativeFunctions(env)
+
+	for _, name := range e.hostOrder {
+		env.Declare(name, e.hostGlobals[name])
+	}
+
+	return env
+}
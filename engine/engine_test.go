@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+func TestNamesSetAfterCompile(t *testing.T) {
+	for backendName, backend := range backends {
+		t.Run(backendName, func(t *testing.T) {
+			eng := New()
+			eng.Backend = backend
+			eng.SetGlobal("early", &object.Integer{Value: 1})
+
+			script, err := eng.Compile(`var twice = early * 2;`)
+			if err != nil {
+				t.Fatalf("compile error: %s", err)
+			}
+
+			eng.SetGlobal("late", &object.Integer{Value: 5})
+			eng.Register("double", func(args ...object.Object) (object.Object, error) {
+				return &object.Integer{Value: args[0].(*object.Integer).Value * 2}, nil
+			})
+
+			_, err = eng.RunWithIO(script, nil, &bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("run error: %s", err)
+			}
+
+			for name, expected := range map[string]string{"early": "1", "twice": "2", "late": "5"} {
+				value, ok := eng.Global(name)
+				if !ok || value.Inspect() != expected {
+					t.Errorf("global %s: expected %s, got %v (defined: %t)", name, expected, value, ok)
+				}
+			}
+
+			result, err := eng.Call("double", &object.Integer{Value: 21})
+			if err != nil || result.Inspect() != "42" {
+				t.Errorf("expected double(21) to be 42, got %v (%v)", result, err)
+			}
+		})
+	}
+}
+
+func TestVMScriptsReadNamesSetBeforeCompile(t *testing.T) {
+	eng := New()
+	eng.Backend = VMBackend
+
+	_, err := eng.Compile(`late;`)
+	if err == nil || !strings.Contains(err.Error(), "Cannot resolve variable 'late'") {
+		t.Errorf("expected late to be unresolved, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/vm"
+)
+
+type ErrorKind string
+
+const (
+	ParseError   ErrorKind = "PARSE"
+	CompileError ErrorKind = "COMPILE"
+	RuntimeError ErrorKind = "RUNTIME"
+	HostError    ErrorKind = "HOST"
+)
+
+var kindLabels = map[ErrorKind]string{
+	ParseError:   "Parser",
+	CompileError: "Compiler",
+	RuntimeError: "Runtime",
+	HostError:    "Host",
+}
+
+// Error is the structured error returned by every Engine/Script call.
+type Error struct {
+	Kind    ErrorKind
+	Line    uint
+	Column  uint
+	Message string
+	Hint    string
+
+	// Runtime errors only, innermost call first ---
+	Trace []object.TraceFrame
+}
+
+func (e *Error) Error() string {
+	label := kindLabels[e.Kind]
+
+	if e.Line == 0 && e.Column == 0 {
+		return fmt.Sprintf("%s::Error: %s", label, e.Message)
+	}
+
+	return fmt.Sprintf("[Ln %d:%d] %s::Error: %s", e.Line, e.Column, label, e.Message)
+}
+
+// StackTrace formats Trace one frame per line.
+func (e *Error) StackTrace() string {
+	var out strings.Builder
+
+	for _, frame := range e.Trace {
+		out.WriteString("    " + frame.String() + "\n")
+	}
+
+	return out.String()
+}
+
+func newParseError(msg string) *Error {
+	err := &Error{Kind: ParseError, Message: msg}
+
+	// Parser messages are prefixed with "[Ln <line>:<column>]" ---
+	var line, column uint
+	if n, _ := fmt.Sscanf(msg, "[Ln %d:%d]", &line, &column); n == 2 {
+		err.Line = line
+		err.Column = column
+
+		rest := msg[strings.Index(msg, "]")+1:]
+		rest = strings.TrimSpace(rest)
+		rest = strings.TrimPrefix(rest, "->")
+		err.Message = strings.TrimSpace(rest)
+	}
+
+	return err
+}
+
+func newCompileError(err error) *Error {
+	var compileErr *compiler.Error
+	if errors.As(err, &compileErr) {
+		return &Error{
+			Kind:    CompileError,
+			Line:    uint(compileErr.Line),
+			Column:  uint(compileErr.Column),
+			Message: compileErr.Message,
+		}
+	}
+
+	return &Error{Kind: CompileError, Message: err.Error()}
+}
+
+func newRuntimeError(obj *object.Error) *Error {
+	return &Error{
+		Kind:    RuntimeError,
+		Line:    obj.Line,
+		Column:  obj.Column,
+		Message: obj.Message,
+		Hint:    obj.Hint,
+		Trace:   obj.Trace,
+	}
+}
+
+// VM errors carry the script error when it has a position and trace ---
+func newVMError(err error) *Error {
+	var thrown *vm.ThrownError
+	if errors.As(err, &thrown) {
+		return newRuntimeError(thrown.Err)
+	}
+
+	return &Error{Kind: RuntimeError, Message: err.Error()}
+}
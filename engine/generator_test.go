@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+)
+
+var backends = map[string]Backend{
+	"evaluator": EvaluatorBackend,
+	"vm":        VMBackend,
+}
+
+// runOutput runs source on backend and returns what it printed ---
+func runOutput(t *testing.T, backend Backend, source string) string {
+	t.Helper()
+
+	eng := New()
+	eng.Backend = backend
+
+	script, err := eng.Compile(source)
+	if err != nil {
+		t.Fatalf("compile error: %s", err)
+	}
+
+	var out bytes.Buffer
+	_, err = eng.RunWithIO(script, nil, &out)
+	if err != nil {
+		t.Fatalf("run error: %s", err)
+	}
+
+	return out.String()
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name: "for in consumes every value",
+			source: `
+fn each(items) { for item in items { yield item; } }
+for x in each([0, 1, 2]) { print(x); }`,
+			expected: "0\n1\n2\n",
+		},
+		{
+			name: "next and is_done",
+			source: `
+fn gen() { var got = yield 1; print(got); return 2; }
+var g = gen();
+print(next(g)); print(next(g, "sent")); print(is_done(g));`,
+			expected: "1\nsent\n2\ntrue\n",
+		},
+		{
+			name: "return from the loop closes the generator",
+			source: `
+fn gen() { try { yield 1; yield 2; } finally { print("closed"); } }
+fn first() { for x in gen() { return x; } }
+print(first()); print("after");`,
+			expected: "closed\n1\nafter\n",
+		},
+		{
+			name: "error leaving the loop closes the generator, catch doesn't see it",
+			source: `
+fn gen() { try { yield 1; yield 2; } catch (e) { print("caught inside"); } finally { print("closed"); } }
+fn run() { for x in gen() { throw "boom"; } }
+try { run(); } catch (e) { print("caught outside"); }`,
+			expected: "closed\ncaught outside\n",
+		},
+		{
+			name: "exhausted generator runs finally once",
+			source: `
+fn gen() { try { yield 1; } finally { print("closed"); } }
+for x in gen() { print(x); }
+print("after");`,
+			expected: "1\nclosed\nafter\n",
+		},
+		{
+			name: "nested generators",
+			source: `
+fn inner(n) { yield n; yield n + 1; }
+fn outer() { for x in inner(1) { yield x; } for x in inner(10) { yield x; } }
+for v in outer() { print(v); }`,
+			expected: "1\n2\n10\n11\n",
+		},
+		{
+			name: "closing a nested generator closes the inner one first",
+			source: `
+fn inner() { try { yield 1; yield 2; } finally { print("inner closed"); } }
+fn outer() { try { for x in inner() { yield x * 10; } } finally { print("outer closed"); } }
+fn first() { for v in outer() { return v; } }
+print(first());`,
+			expected: "inner closed\nouter closed\n10\n",
+		},
+	}
+
+	for backendName, backend := range backends {
+		for _, tt := range tests {
+			t.Run(backendName+"/"+tt.name, func(t *testing.T) {
+				out := runOutput(t, backend, tt.source)
+				if out != tt.expected {
+					t.Errorf("wrong output\nexpected: %q\ngot:      %q", tt.expected, out)
+				}
+			})
+		}
+	}
+}
+
+func TestForInBodyDeclarations(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "var in the body rebinds every iteration",
+			source:   `for i in [1, 2, 3] { var sq = i * i; print(sq); } print(sq);`,
+			expected: "1\n4\n9\n9\n",
+		},
+		{
+			name: "pattern declaration over a generator",
+			source: `
+fn gen() { yield 1; yield 2; }
+for i in gen() { var [b] = [i]; print(b); }`,
+			expected: "1\n2\n",
+		},
+		{
+			name: "nested loops inside a function",
+			source: `
+fn run() { for i in [1, 2] { var row = ""; for j in [1, 2] { var cell = to_string(i * j); row += cell; } print(row); } }
+run();`,
+			expected: "12\n24\n",
+		},
+	}
+
+	for backendName, backend := range backends {
+		for _, tt := range tests {
+			t.Run(backendName+"/"+tt.name, func(t *testing.T) {
+				out := runOutput(t, backend, tt.source)
+				if out != tt.expected {
+					t.Errorf("wrong output\nexpected: %q\ngot:      %q", tt.expected, out)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package engine
+
+import "testing"
+
+func TestHashLiteralSourceOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "last repeated key wins",
+			source:   `var h = {1: "a", 1.0: "b"}; print(h[1], h);`,
+			expected: "b, {1: \"b\"}\n",
+		},
+		{
+			name:     "identical keys",
+			source:   `var h = {"k": 1, "x": 0, "k": 2}; print(h["k"]);`,
+			expected: "2\n",
+		},
+		{
+			name: "keys and values are evaluated in source order",
+			source: `
+fn say(v) { print(v); return v; }
+var h = {say("z"): say(1), say("a"): say(2),
+  say("m"): say(3)};`,
+			expected: "z\n1\na\n2\nm\n3\n",
+		},
+	}
+
+	for backendName, backend := range backends {
+		for _, tt := range tests {
+			t.Run(backendName+"/"+tt.name, func(t *testing.T) {
+				out := runOutput(t, backend, tt.source)
+				if out != tt.expected {
+					t.Errorf("wrong output\nexpected: %q\ngot:      %q", tt.expected, out)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package engine
+
+import "testing"
+
+func TestInfinityAndNaNMatchAcrossEngines(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{`print(1 / 0);`, "Infinity++\n"},
+		{`print(-1 / 0);`, "Infinity--\n"},
+		{`print(0 / 0);`, "NotANumber\n"},
+		{`print(1.5 / 0, 10.0 ^ 400);`, "Infinity++, Infinity++\n"},
+		{`print(-(1 / 0), (1 / 0) - (1 / 0), (1 / 0) * -2);`, "Infinity--, NotANumber, Infinity--\n"},
+		{`print(1 / 0 > 5, 0 / 0 == 0 / 0, 0 / 0 != 1, 1 / 0 == 1 / 0);`, "true, false, true, true\n"},
+		{`print(is_NaN(0 / 0), 3 / (1 / 0));`, "true, 0\n"},
+	}
+
+	for _, tt := range tests {
+		outputs := make(map[string]string)
+		for backendName, backend := range backends {
+			outputs[backendName] = runOutput(t, backend, tt.source)
+		}
+
+		if outputs["evaluator"] != tt.expected || outputs["vm"] != tt.expected {
+			t.Errorf("%s\nexpected:  %q\nevaluator: %q\nvm:        %q", tt.source, tt.expected, outputs["evaluator"], outputs["vm"])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// runError compiles and runs source on backend and returns the error ---
+// either step reported, the VM catches some at compile time ---
+func runError(t *testing.T, backend Backend, source string) *Error {
+	t.Helper()
+
+	eng := New()
+	eng.Backend = backend
+
+	script, err := eng.Compile(source)
+	if err == nil {
+		_, err = eng.RunWithIO(script, nil, &bytes.Buffer{})
+	}
+
+	var engineErr *Error
+	if err != nil && !errors.As(err, &engineErr) {
+		t.Fatalf("expected an *Error, got %T: %s", err, err)
+	}
+	return engineErr
+}
+
+func TestDuplicatePatternNames(t *testing.T) {
+	tests := []struct {
+		source string
+		line   uint
+		column uint
+	}{
+		{`var [a, a] = [1, 2];`, 1, 9},
+		{`var {x: a, y: [b, a]} = {"x": 1, "y": [2, 3]};`, 1, 19},
+		{`fn f(a, [a]) { a; }`, 1, 10},
+		{`fn f(a, a) { a; }`, 1, 9},
+		{`var g = fn([a, ...a]) { a; };`, 1, 19},
+	}
+
+	for backendName, backend := range backends {
+		for _, tt := range tests {
+			t.Run(backendName+"/"+tt.source, func(t *testing.T) {
+				err := runError(t, backend, tt.source)
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+
+				if err.Message != "Cannot bind 'a' twice in the same declaration" {
+					t.Errorf("wrong message: %q", err.Message)
+				}
+				if err.Line != tt.line || err.Column != tt.column {
+					t.Errorf("expected the error at %d:%d, got %d:%d", tt.line, tt.column, err.Line, err.Column)
+				}
+			})
+		}
+	}
+}
+
+func TestDistinctPatternNames(t *testing.T) {
+	source := `
+var [a, b] = [1, 2];
+fn f(c, [d, e], {f}) { print(a, b, c, d, e, f); }
+f(3, [4, 5], {"f": 6});`
+
+	for backendName, backend := range backends {
+		t.Run(backendName, func(t *testing.T) {
+			out := runOutput(t, backend, source)
+			if out != "1, 2, 3, 4, 5, 6\n" {
+				t.Errorf("wrong output: %q", out)
+			}
+		})
+	}
+}
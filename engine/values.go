@@ -0,0 +1,39 @@
+package engine
+
+import "github.com/caelondev/monkey-compiler-go/src/object"
+
+// Value is any script value (Integer, Number, BigInt, Decimal, String, Array, Hash, ...).
+type Value = object.Object
+
+// Function is the signature of a Go function callable from scripts.
+type Function = object.HostFunctionFn
+
+// Register exposes fn to scripts as the global name.
+// A returned error becomes a runtime error at the call site.
+// With VMBackend only scripts compiled after it can call it, see SetGlobal.
+func (e *Engine) Register(name string, fn Function) {
+	e.registry.Register(name, fn)
+}
+
+// RegisterFunc exposes any Go function to scripts, converting
+// arguments and results with FromValue/ToValue.
+//
+//	eng.RegisterFunc("shout", strings.ToUpper)
+func (e *Engine) RegisterFunc(name string, fn interface{}) error {
+	return e.registry.RegisterFunc(name, fn)
+}
+
+// ToValue converts a Go value (float64, string, bool, slices, maps, structs, funcs) into a script value.
+func ToValue(v interface{}) (Value, error) {
+	return object.FromGo(v)
+}
+
+// FromValue stores a script value into the Go value target points to.
+func FromValue(v Value, target interface{}) error {
+	return object.ToGo(v, target)
+}
+
+// Interface converts a script value into a plain Go value.
+func Interface(v Value) interface{} {
+	return object.ToGoValue(v)
+}
@@ -1,12 +1,17 @@
 package ast
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
 
 type Node interface {
 	String() string
 	GetLine() uint
 	GetColumn() uint
 	TokenLiteral() string
+	NodeSpan() Span
 }
 
 type Statement interface {
@@ -20,7 +25,17 @@ type Expression interface {
 }
 
 type Program struct {
+	Span
 	Statements []Statement
+
+	// Comments the lexer skipped, in source order. Tools like ---
+	// the formatter attach them back by position ---
+	Comments []token.Token
+
+	// Only in CST mode: the source, and every token with the ---
+	// whitespace and comments between them, in source order ---
+	Source string
+	Tokens []token.Token
 }
 
 func (p *Program) TokenLiteral() string {
@@ -0,0 +1,63 @@
+package ast
+
+import "reflect"
+
+// Clone deep copies node, so it can be modified without ---
+// touching the tree it came from ---
+func Clone(node Node) Node {
+	return cloneValue(reflect.ValueOf(node)).Interface().(Node)
+}
+
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.New(v.Elem().Type())
+		clone.Elem().Set(cloneValue(v.Elem()))
+		return clone
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(cloneValue(v.Elem()))
+		return clone
+
+	case reflect.Struct:
+		// Unexported fields are copied as they are, big.Int values ---
+		// are never modified in place ---
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if clone.Field(i).CanSet() {
+				clone.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+		return clone
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return clone
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			clone.SetMapIndex(cloneValue(iter.Key()), cloneValue(iter.Value()))
+		}
+		return clone
+	}
+
+	return v
+}
@@ -2,13 +2,16 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
 	"strings"
 
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
 	"github.com/caelondev/monkey-compiler-go/src/token"
 )
 
 // ---------------- NumberLiteral ----------------
 type StringLiteral struct {
+	Span
 	Token token.Token
 	Value string
 }
@@ -22,18 +25,50 @@ func (n *StringLiteral) GetColumn() uint {
 
 func (n *StringLiteral) expressionNode() {}
 func (n *StringLiteral) String() string {
+	return lexer.Quote(n.Value)
+}
+func (n *StringLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- InterpolatedString ----------------
+type InterpolatedString struct {
+	Span
+	Token token.Token // The STRING_HEAD ---
+
+	// The text as string literals, with what each ${...} holds between them ---
+	Parts []Expression
+}
+
+func (n *InterpolatedString) GetLine() uint {
+	return n.Token.Line
+}
+func (n *InterpolatedString) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *InterpolatedString) expressionNode() {}
+func (n *InterpolatedString) String() string {
 	var out bytes.Buffer
 	out.WriteString("\"")
-	out.WriteString(n.Token.Literal)
+	for _, part := range n.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			quoted := lexer.Quote(text.Value)
+			out.WriteString(quoted[1 : len(quoted)-1])
+		} else {
+			out.WriteString("${" + part.String() + "}")
+		}
+	}
 	out.WriteString("\"")
 	return out.String()
 }
-func (n *StringLiteral) TokenLiteral() string {
+func (n *InterpolatedString) TokenLiteral() string {
 	return n.Token.Literal
 }
 
 // ---------------- NumberLiteral ----------------
 type NumberLiteral struct {
+	Span
 	Token token.Token
 	Value float64
 }
@@ -56,8 +91,82 @@ func (n *NumberLiteral) TokenLiteral() string {
 	return n.Token.Literal
 }
 
+// ---------------- IntegerLiteral ----------------
+// A number literal without a '.' or an exponent ---
+type IntegerLiteral struct {
+	Span
+	Token token.Token
+	Value int64
+}
+
+func (n *IntegerLiteral) GetLine() uint {
+	return n.Token.Line
+}
+
+func (n *IntegerLiteral) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *IntegerLiteral) expressionNode() {}
+func (n *IntegerLiteral) String() string {
+	return n.Token.Literal
+}
+func (n *IntegerLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- BigIntLiteral ----------------
+// 123n, an integer of any size ---
+type BigIntLiteral struct {
+	Span
+	Token token.Token
+	Value *big.Int
+}
+
+func (n *BigIntLiteral) GetLine() uint {
+	return n.Token.Line
+}
+
+func (n *BigIntLiteral) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *BigIntLiteral) expressionNode() {}
+func (n *BigIntLiteral) String() string {
+	return n.Token.Literal
+}
+func (n *BigIntLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- DecimalLiteral ----------------
+// 1.10m, an exact decimal worth Unscaled / 10^Scale ---
+type DecimalLiteral struct {
+	Span
+	Token    token.Token
+	Unscaled *big.Int
+	Scale    int32
+}
+
+func (n *DecimalLiteral) GetLine() uint {
+	return n.Token.Line
+}
+
+func (n *DecimalLiteral) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *DecimalLiteral) expressionNode() {}
+func (n *DecimalLiteral) String() string {
+	return n.Token.Literal
+}
+func (n *DecimalLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
 // ---------------- NilLiteral ----------------
 type NilLiteral struct {
+	Span
 	Token token.Token
 }
 
@@ -80,6 +189,7 @@ func (n *NilLiteral) TokenLiteral() string {
 
 // ---------------- Identifier ----------------
 type Identifier struct {
+	Span
 	Token token.Token
 	Value string
 }
@@ -103,6 +213,7 @@ func (i *Identifier) TokenLiteral() string {
 
 // ---------------- UnaryExpression ----------------
 type UnaryExpression struct {
+	Span
 	Token    token.Token
 	Operator token.Token
 	Right    Expression
@@ -128,6 +239,7 @@ func (ue *UnaryExpression) TokenLiteral() string {
 
 // ---------------- BinaryExpression ----------------
 type BinaryExpression struct {
+	Span
 	Token    token.Token
 	Left     Expression
 	Operator token.Token
@@ -155,6 +267,7 @@ func (be *BinaryExpression) TokenLiteral() string {
 
 // ---------------- BooleanExpression ----------------
 type BooleanExpression struct {
+	Span
 	Token token.Token
 	Value bool
 }
@@ -178,6 +291,7 @@ func (be *BooleanExpression) TokenLiteral() string {
 
 // ---------------- TernaryExpression ----------------
 type TernaryExpression struct {
+	Span
 	Token       token.Token
 	Condition   Expression
 	Consequence Expression
@@ -207,9 +321,19 @@ func (te *TernaryExpression) TokenLiteral() string {
 
 // ---------------- FunctionLiteral ----------------
 type FunctionLiteral struct {
-	Token      token.Token
-	Parameters []*Identifier
-	Body       *BlockStatement
+	Span
+	Token       token.Token
+	Parameters  []*Identifier
+	Body        *BlockStatement
+	IsGenerator bool // Body contains a yield ---
+
+	// One per parameter, nil where it isn't annotated ---
+	ParameterTypes []*TypeAnnotation
+	ReturnType     *TypeAnnotation
+
+	// One per parameter, nil where it's a plain name. A pattern's ---
+	// parameter is named after it and unpacked before the body runs ---
+	Patterns []Expression
 }
 
 func (fl *FunctionLiteral) GetLine() uint {
@@ -224,13 +348,18 @@ func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 	out.WriteString(fl.Token.Literal)
 	out.WriteString("(")
-	for i, param := range fl.Parameters {
+	for i := range fl.Parameters {
 		if i > 0 {
 			out.WriteString(", ")
 		}
-		out.WriteString(param.String())
+		out.WriteString(parameterString(fl.Parameters, fl.ParameterTypes, i))
 	}
-	out.WriteString(") ")
+	out.WriteString(")")
+	if fl.ReturnType != nil {
+		out.WriteString(": ")
+		out.WriteString(fl.ReturnType.String())
+	}
+	out.WriteString(" ")
 	out.WriteString("{\n")
 	out.WriteString(fl.Body.String())
 	out.WriteString("}")
@@ -241,8 +370,48 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 	return fl.Token.Literal
 }
 
+// ---------------- MacroLiteral ----------------
+// Macros are expanded before the program runs, a macro's body ---
+// is evaluated with its arguments quoted and returns a quote ---
+type MacroLiteral struct {
+	Span
+	Token      token.Token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (ml *MacroLiteral) GetLine() uint {
+	return ml.Token.Line
+}
+func (ml *MacroLiteral) GetColumn() uint {
+	return ml.Token.Column
+}
+
+func (ml *MacroLiteral) expressionNode() {}
+func (ml *MacroLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString(ml.Token.Literal)
+	out.WriteString("(")
+	for i, param := range ml.Parameters {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(param.String())
+	}
+	out.WriteString(") ")
+	out.WriteString("{\n")
+	out.WriteString(ml.Body.String())
+	out.WriteString("}")
+
+	return out.String()
+}
+func (ml *MacroLiteral) TokenLiteral() string {
+	return ml.Token.Literal
+}
+
 // ---------------- CallExpression ----------------
 type CallExpression struct {
+	Span
 	Token     token.Token
 	Function  Expression
 	Arguments []Expression
@@ -275,6 +444,7 @@ func (ce *CallExpression) TokenLiteral() string {
 
 // ---------------- NaNLiteral ----------------
 type NaNLiteral struct {
+	Span
 	Token token.Token
 }
 
@@ -297,6 +467,7 @@ func (n *NaNLiteral) TokenLiteral() string {
 
 // ---------------- InfinityLiteral ----------------
 type InfinityLiteral struct {
+	Span
 	Token token.Token
 	Sign  int // -1 +1 ---
 }
@@ -320,9 +491,14 @@ func (n *InfinityLiteral) TokenLiteral() string {
 
 // ---------------- AssignmentExpression ----------------
 type AssignmentExpression struct {
-	Token    token.Token
+	Span
+	Token    token.Token // =, a compound one like +=, or ++ and -- ---
 	Assignee Expression
 	NewValue Expression
+
+	// Operator is the + of +=, or of ++ whose NewValue is 1, ---
+	// a plain = leaves it empty ---
+	Operator token.Token
 }
 
 func (n *AssignmentExpression) GetLine() uint {
@@ -336,16 +512,40 @@ func (n *AssignmentExpression) expressionNode() {}
 func (n *AssignmentExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(n.Assignee.String())
-	out.WriteString(" = ")
-	out.WriteString(n.NewValue.String())
+	out.WriteString(assignmentString(n.Token, n.NewValue))
 	return out.String()
 }
 func (n *AssignmentExpression) TokenLiteral() string {
 	return n.Token.Literal
 }
 
+// assignmentString is the part of an assignment after its assignee ---
+func assignmentString(tok token.Token, value Expression) string {
+	if IsIncrement(tok.Type) {
+		return tok.Literal
+	}
+
+	return " " + tok.Literal + " " + value.String()
+}
+
+// Assigned is the value an assignment stores as an expression, ---
+// `x + v` for x += v and v itself for x = v ---
+func Assigned(tok, operator token.Token, assignee, value Expression) Expression {
+	if operator.Type == "" {
+		return value
+	}
+
+	return &BinaryExpression{Token: tok, Operator: operator, Left: assignee, Right: value}
+}
+
+// IsIncrement reports whether an assignment's token is ++ or -- ---
+func IsIncrement(tokenType token.TokenType) bool {
+	return tokenType == token.INCREMENT || tokenType == token.DECREMENT
+}
+
 // ---------------- ArrayLiteral ----------------
 type ArrayLiteral struct {
+	Span
 	Token    token.Token
 	Elements []Expression
 }
@@ -381,6 +581,7 @@ func (n *ArrayLiteral) TokenLiteral() string {
 
 // ---------------- IndexExpression ----------------
 type IndexExpression struct {
+	Span
 	Token  token.Token
 	Index  Expression
 	Target Expression
@@ -410,10 +611,12 @@ func (n *IndexExpression) TokenLiteral() string {
 
 // ---------------- IndexAssignmentExpression ----------------
 type IndexAssignmentExpression struct {
+	Span
 	Token    token.Token
 	Index    Expression
 	Target   Expression
 	NewValue Expression
+	Operator token.Token // Like AssignmentExpression's ---
 }
 
 func (n *IndexAssignmentExpression) GetLine() uint {
@@ -430,8 +633,8 @@ func (n *IndexAssignmentExpression) String() string {
 	out.WriteString(n.Target.String())
 	out.WriteString("[")
 	out.WriteString(n.Index.String())
-	out.WriteString("] = ")
-	out.WriteString(n.NewValue.String())
+	out.WriteString("]")
+	out.WriteString(assignmentString(n.Token, n.NewValue))
 
 	return out.String()
 }
@@ -441,6 +644,7 @@ func (n *IndexAssignmentExpression) TokenLiteral() string {
 
 // ---------------- HashLiteral ----------------
 type HashLiteral struct {
+	Span
 	Token token.Token
 	Pairs map[Expression]Expression
 }
@@ -476,6 +680,7 @@ func (n *HashLiteral) TokenLiteral() string {
 
 // ---------------- AbsoluteExpression ----------------
 type AbsoluteExpression struct {
+	Span
 	Token token.Token
 	Value Expression
 }
@@ -503,6 +708,7 @@ func (n *AbsoluteExpression) TokenLiteral() string {
 
 // ---------------- IndexSliceExpression ----------------
 type IndexSliceExpression struct {
+	Span
 	Token  token.Token
 	Target Expression
 	Start  Expression
@@ -540,3 +746,87 @@ func (n *IndexSliceExpression) String() string {
 func (n *IndexSliceExpression) TokenLiteral() string {
 	return n.Token.Literal
 }
+
+// ---------------- SpawnExpression ----------------
+type SpawnExpression struct {
+	Span
+	Token token.Token
+	Call  *CallExpression
+}
+
+func (n *SpawnExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *SpawnExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *SpawnExpression) expressionNode() {}
+func (n *SpawnExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("spawn ")
+	out.WriteString(n.Call.String())
+
+	return out.String()
+}
+func (n *SpawnExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- AwaitExpression ----------------
+type AwaitExpression struct {
+	Span
+	Token token.Token
+	Value Expression
+}
+
+func (n *AwaitExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *AwaitExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *AwaitExpression) expressionNode() {}
+func (n *AwaitExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("await ")
+	out.WriteString(n.Value.String())
+
+	return out.String()
+}
+func (n *AwaitExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- YieldExpression ----------------
+type YieldExpression struct {
+	Span
+	Token token.Token
+	Value Expression // nil for a bare yield ---
+}
+
+func (n *YieldExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *YieldExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *YieldExpression) expressionNode() {}
+func (n *YieldExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("yield")
+	if n.Value != nil {
+		out.WriteString(" ")
+		out.WriteString(n.Value.String())
+	}
+
+	return out.String()
+}
+func (n *YieldExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
@@ -0,0 +1,15 @@
+package ast
+
+// ModifierFunc returns the node to put in place of node, ---
+// returning node itself leaves it unchanged ---
+type ModifierFunc func(Node) Node
+
+// Modify walks node depth first, replacing every node with what ---
+// modifier returns for it once its children have been modified. ---
+// A replacement of the wrong kind for its place is dropped ---
+func Modify(node Node, modifier ModifierFunc) Node {
+	return Rewrite(node, nil, func(c *Cursor) bool {
+		c.Replace(modifier(c.Node()))
+		return true
+	})
+}
@@ -0,0 +1,219 @@
+package ast
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// Patterns unpack a value into names, after var and assign and in ---
+// place of a parameter. An element is an Identifier, a nested ---
+// pattern or a DefaultPattern around either ---
+//
+// var [a, b = 0, ...rest] = array;
+// var {name, age: years, "home town": town} = hash;
+
+// ---------------- ArrayPattern ----------------
+type ArrayPattern struct {
+	Span
+	Token    token.Token // [ ---
+	Elements []Expression
+	Rest     *Identifier // ...rest, nil without one ---
+}
+
+func (ap *ArrayPattern) GetLine() uint {
+	return ap.Token.Line
+}
+func (ap *ArrayPattern) GetColumn() uint {
+	return ap.Token.Column
+}
+
+func (ap *ArrayPattern) expressionNode() {}
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("[")
+	for i, element := range ap.Elements {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(element.String())
+	}
+	if ap.Rest != nil {
+		if len(ap.Elements) != 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString("...")
+		out.WriteString(ap.Rest.String())
+	}
+	out.WriteString("]")
+
+	return out.String()
+}
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+// Required is how many elements an array needs at least, up to ---
+// the last one without a default ---
+func (ap *ArrayPattern) Required() int {
+	for i := len(ap.Elements) - 1; i >= 0; i-- {
+		if _, ok := ap.Elements[i].(*DefaultPattern); !ok {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// ---------------- HashPattern ----------------
+type HashPattern struct {
+	Span
+	Token   token.Token // { ---
+	Entries []*PatternEntry
+}
+
+// PatternEntry takes Key out of a hash. Key is a string, its token ---
+// is the IDENTIFIER or STRING it was written as ---
+type PatternEntry struct {
+	Key   *StringLiteral
+	Value Expression // An Identifier named like Key for {name} ---
+}
+
+func (hp *HashPattern) GetLine() uint {
+	return hp.Token.Line
+}
+func (hp *HashPattern) GetColumn() uint {
+	return hp.Token.Column
+}
+
+func (hp *HashPattern) expressionNode() {}
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("{")
+	for i, entry := range hp.Entries {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(entry.String())
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+func (hp *HashPattern) TokenLiteral() string {
+	return hp.Token.Literal
+}
+
+func (pe *PatternEntry) String() string {
+	if pe.IsShorthand() {
+		return pe.Value.String()
+	}
+
+	key := pe.Key.Value
+	if pe.Key.Token.Type != token.IDENTIFIER {
+		key = strconv.Quote(key)
+	}
+	return key + ": " + pe.Value.String()
+}
+
+// IsShorthand reports whether the entry can be written as {name} ---
+// or {name = default} ---
+func (pe *PatternEntry) IsShorthand() bool {
+	value := pe.Value
+	if def, ok := value.(*DefaultPattern); ok {
+		value = def.Target
+	}
+
+	ident, ok := value.(*Identifier)
+	return ok && pe.Key.Token.Type == token.IDENTIFIER && ident.Value == pe.Key.Value
+}
+
+// ---------------- DefaultPattern ----------------
+// Target gets Default when its value is missing or nil ---
+type DefaultPattern struct {
+	Span
+	Token   token.Token // = ---
+	Target  Expression
+	Default Expression
+}
+
+func (dp *DefaultPattern) GetLine() uint {
+	return dp.Token.Line
+}
+func (dp *DefaultPattern) GetColumn() uint {
+	return dp.Token.Column
+}
+
+func (dp *DefaultPattern) expressionNode() {}
+func (dp *DefaultPattern) String() string {
+	return dp.Target.String() + " = " + dp.Default.String()
+}
+func (dp *DefaultPattern) TokenLiteral() string {
+	return dp.Token.Literal
+}
+
+// PatternNames lists the names pattern binds, in source order ---
+func PatternNames(pattern Expression) []*Identifier {
+	switch pattern := pattern.(type) {
+	case *Identifier:
+		return []*Identifier{pattern}
+	case *DefaultPattern:
+		return PatternNames(pattern.Target)
+	case *ArrayPattern:
+		names := make([]*Identifier, 0, len(pattern.Elements)+1)
+		for _, element := range pattern.Elements {
+			names = append(names, PatternNames(element)...)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest)
+		}
+		return names
+	case *HashPattern:
+		names := make([]*Identifier, 0, len(pattern.Entries))
+		for _, entry := range pattern.Entries {
+			names = append(names, PatternNames(entry.Value)...)
+		}
+		return names
+	}
+
+	return nil
+}
+
+// ParameterNames lists the names a parameter list binds, in source order, ---
+// pattern parameters contribute the names inside their pattern ---
+func ParameterNames(parameters []*Identifier, patterns []Expression) []*Identifier {
+	names := make([]*Identifier, 0, len(parameters))
+	for i, param := range parameters {
+		if i < len(patterns) && patterns[i] != nil {
+			names = append(names, PatternNames(patterns[i])...)
+			continue
+		}
+		names = append(names, param)
+	}
+	return names
+}
+
+// DuplicateName returns the first name of names that was already bound ---
+// earlier in it, or nil when every name is distinct ---
+func DuplicateName(names []*Identifier) *Identifier {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name.Value] {
+			return name
+		}
+		seen[name.Value] = true
+	}
+	return nil
+}
+
+// IsPattern reports whether expr is an array or hash pattern ---
+func IsPattern(expr Expression) bool {
+	switch expr.(type) {
+	case *ArrayPattern, *HashPattern:
+		return true
+	}
+	return false
+}
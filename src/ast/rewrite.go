@@ -0,0 +1,93 @@
+package ast
+
+// Cursor is a node being visited by Rewrite ---
+type Cursor struct {
+	node   Node
+	parent Node
+	scope  *Scope
+	set    func(Node) bool
+}
+
+// Node is the current node, after any Replace ---
+func (c *Cursor) Node() Node {
+	return c.node
+}
+
+// Parent is the node holding the current one, nil for the root ---
+func (c *Cursor) Parent() Node {
+	return c.parent
+}
+
+// Scope is the innermost scope the current node is in. A function's ---
+// own node is in the scope around it, its children in its own ---
+func (c *Cursor) Scope() *Scope {
+	return c.scope
+}
+
+// Replace puts n in place of the current node, reporting false when ---
+// n doesn't fit there, e.g. a statement where an expression goes ---
+func (c *Cursor) Replace(n Node) bool {
+	if n == nil || !c.set(n) {
+		return false
+	}
+
+	c.node = n
+	return true
+}
+
+// Rewrite traverses root depth first. pre is called before a node's ---
+// children and post after them, either may be nil. pre returning ---
+// false skips the children and post, post returning false stops the ---
+// whole traversal. The root, replaced or not, is returned ---
+func Rewrite(root Node, pre, post func(*Cursor) bool) Node {
+	r := &rewriter{pre: pre, post: post}
+
+	var scope *Scope
+	if _, ok := root.(*Program); !ok {
+		// Partial trees start without a global scope ---
+		scope = &Scope{Node: root, Names: make(map[string]*Identifier)}
+	}
+
+	result := root
+	r.apply(nil, scope, root, func(n Node) bool {
+		result = n
+		return true
+	})
+
+	return result
+}
+
+type rewriter struct {
+	pre, post func(*Cursor) bool
+	stopped   bool
+}
+
+func (r *rewriter) apply(parent Node, scope *Scope, node Node, set func(Node) bool) {
+	c := &Cursor{node: node, parent: parent, scope: scope, set: set}
+
+	if r.pre != nil && !r.pre(c) {
+		return
+	}
+
+	inner := scope
+	if OpensScope(c.node) {
+		inner = NewScope(c.node, scope)
+	}
+
+	for _, s := range slots(c.node) {
+		if r.stopped {
+			return
+		}
+		if child := s.get(); !IsNil(child) {
+			r.apply(c.node, inner, child, s.set)
+		}
+	}
+
+	if r.stopped {
+		return
+	}
+
+	if r.post != nil && !r.post(c) {
+		r.stopped = true
+	}
+}
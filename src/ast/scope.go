@@ -0,0 +1,97 @@
+package ast
+
+// Scope is what one function, or the program, declares. Only ---
+// functions open a scope, like in both engines ---
+type Scope struct {
+	Node  Node // *Program, *FunctionLiteral, *FunctionDeclarationStatement or *MacroLiteral ---
+	Outer *Scope
+	Names map[string]*Identifier // First declaration of each name ---
+}
+
+// OpensScope reports whether node's children are in a scope of their own ---
+func OpensScope(node Node) bool {
+	switch node.(type) {
+	case *Program, *FunctionLiteral, *FunctionDeclarationStatement, *MacroLiteral:
+		return true
+	}
+	return false
+}
+
+// NewScope collects the names declared directly in node: parameters, ---
+// vars, function declarations, loop variables and catch parameters ---
+func NewScope(node Node, outer *Scope) *Scope {
+	s := &Scope{Node: node, Outer: outer, Names: make(map[string]*Identifier)}
+
+	var body Node
+	switch node := node.(type) {
+	case *Program:
+		body = node
+	case *FunctionLiteral:
+		s.declare(node.Parameters...)
+		s.declarePatterns(node.Patterns...)
+		body = node.Body
+	case *FunctionDeclarationStatement:
+		s.declare(node.Parameters...)
+		s.declarePatterns(node.Patterns...)
+		body = node.Body
+	case *MacroLiteral:
+		s.declare(node.Parameters...)
+		body = node.Body
+	default:
+		return s
+	}
+
+	Inspect(body, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarStatement:
+			s.declare(n.Names...)
+			s.declarePatterns(n.Pattern)
+		case *FunctionDeclarationStatement:
+			// Its name is ours, the rest is its own scope ---
+			s.declare(n.Name)
+			return false
+		case *FunctionLiteral, *MacroLiteral:
+			return false
+		case *ForInStatement:
+			s.declare(n.Variable)
+		case *TryStatement:
+			if n.CatchParam != nil {
+				s.declare(n.CatchParam)
+			}
+		}
+		return true
+	})
+
+	return s
+}
+
+func (s *Scope) declare(idents ...*Identifier) {
+	for _, ident := range idents {
+		if _, exists := s.Names[ident.Value]; !exists {
+			s.Names[ident.Value] = ident
+		}
+	}
+}
+
+func (s *Scope) declarePatterns(patterns ...Expression) {
+	for _, pattern := range patterns {
+		s.declare(PatternNames(pattern)...)
+	}
+}
+
+// Lookup finds the declaration name resolves to from s, and the ---
+// scope declaring it. Builtins aren't declared anywhere ---
+func (s *Scope) Lookup(name string) (*Identifier, *Scope) {
+	for ; s != nil; s = s.Outer {
+		if ident, ok := s.Names[name]; ok {
+			return ident, s
+		}
+	}
+
+	return nil, nil
+}
+
+// IsGlobal reports whether s is the program's scope ---
+func (s *Scope) IsGlobal() bool {
+	return s.Outer == nil
+}
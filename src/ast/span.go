@@ -0,0 +1,82 @@
+package ast
+
+import (
+	"sort"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// Span is the stretch of source a node was parsed from, End is ---
+// just past its last byte. Every node embeds one, nodes built outside ---
+// the parser, like the ones macros unquote, have a zero Span ---
+type Span struct {
+	Start token.Position
+	End   token.Position
+}
+
+func (s Span) NodeSpan() Span {
+	return s
+}
+
+func (s *Span) SetSpan(span Span) {
+	*s = span
+}
+
+// IsZero reports whether the span was never set ---
+func (s Span) IsZero() bool {
+	return s == Span{}
+}
+
+// Contains reports whether the byte at offset is inside the span ---
+func (s Span) Contains(offset int) bool {
+	return s.Start.Offset <= offset && offset < s.End.Offset
+}
+
+// SpanOf covers the tokens from first through last ---
+func SpanOf(first, last token.Token) Span {
+	return Span{Start: first.Start(), End: last.End()}
+}
+
+// Text is the source of node, only a program parsed in CST mode ---
+// keeps its source ---
+func (p *Program) Text(node Node) string {
+	span := node.NodeSpan()
+	if p.Source == "" || span.IsZero() {
+		return ""
+	}
+
+	return p.Source[span.Start.Offset:span.End.Offset]
+}
+
+// TokensOf lists the tokens and trivia inside node, in CST mode ---
+func (p *Program) TokensOf(node Node) []token.Token {
+	span := node.NodeSpan()
+	if span.IsZero() {
+		return nil
+	}
+
+	first := sort.Search(len(p.Tokens), func(i int) bool {
+		return p.Tokens[i].Offset >= span.Start.Offset
+	})
+	last := sort.Search(len(p.Tokens), func(i int) bool {
+		return p.Tokens[i].EndOffset > span.End.Offset
+	})
+
+	return p.Tokens[first:max(first, last)]
+}
+
+// NodeAt is the innermost node under root covering offset ---
+func NodeAt(root Node, offset int) Node {
+	var found Node
+
+	Inspect(root, func(node Node) bool {
+		if node == nil || !node.NodeSpan().Contains(offset) {
+			return false
+		}
+
+		found = node
+		return true
+	})
+
+	return found
+}
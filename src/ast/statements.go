@@ -8,9 +8,14 @@ import (
 
 // ---------------- VarStatement ----------------
 type VarStatement struct {
-	Token token.Token   // LET Token
-	Names []*Identifier // All names will receive same value
+	Span
+	Token token.Token     // LET Token
+	Names []*Identifier   // All names will receive same value
+	Type  *TypeAnnotation // nil when not annotated ---
 	Value Expression
+
+	// var [a, b] = v; unpacks v instead, Names is empty then ---
+	Pattern Expression
 }
 
 func (vs *VarStatement) GetLine() uint {
@@ -26,6 +31,13 @@ func (vs *VarStatement) String() string {
 	out.WriteString(vs.Token.Literal)
 	out.WriteString(" ")
 
+	if vs.Pattern != nil {
+		out.WriteString(vs.Pattern.String())
+		out.WriteString(" = ")
+		out.WriteString(vs.Value.String())
+		return out.String()
+	}
+
 	if len(vs.Names) > 1 {
 		for i := 0; i < len(vs.Names)-1; i++ {
 			out.WriteString(vs.Names[i].String())
@@ -33,6 +45,10 @@ func (vs *VarStatement) String() string {
 		}
 	}
 	out.WriteString(vs.Names[len(vs.Names)-1].String())
+	if vs.Type != nil {
+		out.WriteString(": ")
+		out.WriteString(vs.Type.String())
+	}
 	out.WriteString(" = ")
 	out.WriteString(vs.Value.String())
 
@@ -44,6 +60,7 @@ func (vs *VarStatement) TokenLiteral() string {
 
 // ---------------- ReturnStatement ----------------
 type ReturnStatement struct {
+	Span
 	Token       token.Token // RETURN Token
 	ReturnValue Expression
 }
@@ -71,6 +88,7 @@ func (rs *ReturnStatement) TokenLiteral() string {
 
 // ---------------- ExpressionStatement ----------------
 type ExpressionStatement struct {
+	Span
 	Token      token.Token
 	Expression Expression
 }
@@ -96,6 +114,7 @@ func (es *ExpressionStatement) TokenLiteral() string {
 
 // ---------------- BlockStatement ----------------
 type BlockStatement struct {
+	Span
 	Token      token.Token
 	Statements []Statement
 }
@@ -123,6 +142,7 @@ func (bs *BlockStatement) TokenLiteral() string {
 
 // ---------------- IfStatement ----------------
 type IfStatement struct {
+	Span
 	Token       token.Token
 	Condition   Expression
 	Consequence Statement
@@ -160,9 +180,18 @@ func (is *IfStatement) TokenLiteral() string {
 
 // ---------------- BatchAssignmentStatement ----------------
 type BatchAssignmentStatement struct {
+	Span
 	Token     token.Token
 	Assignees []*Identifier
 	NewValue  Expression
+
+	// Assign is the =, += or ++ after the assignees, Operator ---
+	// is set like AssignmentExpression's ---
+	Assign   token.Token
+	Operator token.Token
+
+	// assign [a, b] = v; unpacks v instead, Assignees is empty then ---
+	Pattern Expression
 }
 
 func (bs *BatchAssignmentStatement) GetLine() uint {
@@ -178,6 +207,13 @@ func (ba *BatchAssignmentStatement) String() string {
 
 	out.WriteString(ba.Token.Literal)
 
+	if ba.Pattern != nil {
+		out.WriteString(" ")
+		out.WriteString(ba.Pattern.String())
+		out.WriteString(assignmentString(ba.Assign, ba.NewValue))
+		return out.String()
+	}
+
 	for i := range len(ba.Assignees) - 1 {
 		assignee := ba.Assignees[i]
 		out.WriteString(assignee.String())
@@ -186,8 +222,7 @@ func (ba *BatchAssignmentStatement) String() string {
 
 	assignee := ba.Assignees[len(ba.Assignees)-1]
 	out.WriteString(assignee.String())
-	out.WriteString(" = ")
-	out.WriteString(ba.NewValue.String())
+	out.WriteString(assignmentString(ba.Assign, ba.NewValue))
 
 	return out.String()
 }
@@ -197,10 +232,20 @@ func (ba *BatchAssignmentStatement) TokenLiteral() string {
 
 // ---------------- FunctionDeclarationStatement ----------------
 type FunctionDeclarationStatement struct {
-	Token      token.Token
-	Name       *Identifier
-	Parameters []*Identifier
-	Body       *BlockStatement
+	Span
+	Token       token.Token
+	Name        *Identifier
+	Parameters  []*Identifier
+	Body        *BlockStatement
+	IsGenerator bool // Body contains a yield ---
+
+	// One per parameter, nil where it isn't annotated ---
+	ParameterTypes []*TypeAnnotation
+	ReturnType     *TypeAnnotation
+
+	// One per parameter, nil where it's a plain name. A pattern's ---
+	// parameter is named after it and unpacked before the body runs ---
+	Patterns []Expression
 }
 
 func (bs *FunctionDeclarationStatement) GetLine() uint {
@@ -222,16 +267,19 @@ func (ba *FunctionDeclarationStatement) String() string {
 	if len(ba.Parameters) != 0 {
 		for i := range len(ba.Parameters) - 1 {
 
-			param := ba.Parameters[i]
-			out.WriteString(param.String())
+			out.WriteString(parameterString(ba.Parameters, ba.ParameterTypes, i))
 			out.WriteString(", ")
 		}
 
-	param := ba.Parameters[len(ba.Parameters)-1]
-	out.WriteString(param.String())
+		out.WriteString(parameterString(ba.Parameters, ba.ParameterTypes, len(ba.Parameters)-1))
 	}
 
-	out.WriteString(") {\n")
+	out.WriteString(")")
+	if ba.ReturnType != nil {
+		out.WriteString(": ")
+		out.WriteString(ba.ReturnType.String())
+	}
+	out.WriteString(" {\n")
 
 	for _, stmt := range ba.Body.Statements {
 		out.WriteString("\t")
@@ -246,3 +294,103 @@ func (ba *FunctionDeclarationStatement) String() string {
 func (ba *FunctionDeclarationStatement) TokenLiteral() string {
 	return ba.Token.Literal
 }
+
+// ---------------- ForInStatement ----------------
+type ForInStatement struct {
+	Span
+	Token    token.Token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForInStatement) GetLine() uint {
+	return fs.Token.Line
+}
+func (fs *ForInStatement) GetColumn() uint {
+	return fs.Token.Column
+}
+
+func (fs *ForInStatement) statementNode() {}
+func (fs *ForInStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("for ")
+	out.WriteString(fs.Variable.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(" {\n")
+	out.WriteString(fs.Body.String())
+	out.WriteString("}")
+	return out.String()
+}
+func (fs *ForInStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+// ---------------- TryStatement ----------------
+type TryStatement struct {
+	Span
+	Token      token.Token
+	Block      *BlockStatement
+	CatchParam *Identifier // Optional ---
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (ts *TryStatement) GetLine() uint {
+	return ts.Token.Line
+}
+func (ts *TryStatement) GetColumn() uint {
+	return ts.Token.Column
+}
+
+func (ts *TryStatement) statementNode() {}
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("try {\n")
+	out.WriteString(ts.Block.String())
+	out.WriteString("}")
+
+	if ts.Catch != nil {
+		out.WriteString(" catch ")
+		if ts.CatchParam != nil {
+			out.WriteString("(" + ts.CatchParam.String() + ") ")
+		}
+		out.WriteString("{\n")
+		out.WriteString(ts.Catch.String())
+		out.WriteString("}")
+	}
+
+	if ts.Finally != nil {
+		out.WriteString(" finally {\n")
+		out.WriteString(ts.Finally.String())
+		out.WriteString("}")
+	}
+
+	return out.String()
+}
+func (ts *TryStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+// ---------------- ThrowStatement ----------------
+type ThrowStatement struct {
+	Span
+	Token token.Token
+	Value Expression
+}
+
+func (ts *ThrowStatement) GetLine() uint {
+	return ts.Token.Line
+}
+func (ts *ThrowStatement) GetColumn() uint {
+	return ts.Token.Column
+}
+
+func (ts *ThrowStatement) statementNode() {}
+func (ts *ThrowStatement) String() string {
+	return "throw " + ts.Value.String()
+}
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// ---------------- TypeAnnotation ----------------
+// Optional `: type` after a var name, parameter or parameter list. ---
+// Only `monkey check --types` reads them, both engines skip them ---
+//
+// number, integer, bigint, decimal, string, boolean, nil, function, array, hash, any
+// [<type>]           array of <type>
+// {<type>: <type>}   hash of keys to values
+type TypeAnnotation struct {
+	Span
+	Token   token.Token // The type name, [ or { ---
+	Name    string
+	Key     *TypeAnnotation // Hash keys ---
+	Element *TypeAnnotation // Array elements and hash values ---
+}
+
+func (ta *TypeAnnotation) GetLine() uint {
+	return ta.Token.Line
+}
+func (ta *TypeAnnotation) GetColumn() uint {
+	return ta.Token.Column
+}
+
+func (ta *TypeAnnotation) String() string {
+	var out bytes.Buffer
+
+	switch {
+	case ta.Key != nil:
+		out.WriteString("{")
+		out.WriteString(ta.Key.String())
+		out.WriteString(": ")
+		out.WriteString(ta.Element.String())
+		out.WriteString("}")
+	case ta.Element != nil:
+		out.WriteString("[")
+		out.WriteString(ta.Element.String())
+		out.WriteString("]")
+	default:
+		out.WriteString(ta.Name)
+	}
+
+	return out.String()
+}
+func (ta *TypeAnnotation) TokenLiteral() string {
+	return ta.Token.Literal
+}
+
+// parameterString is the i-th parameter with its annotation, if any ---
+func parameterString(params []*Identifier, types []*TypeAnnotation, i int) string {
+	if i >= len(types) || types[i] == nil {
+		return params[i].String()
+	}
+
+	return params[i].String() + ": " + types[i].String()
+}
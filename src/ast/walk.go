@@ -0,0 +1,374 @@
+package ast
+
+import (
+	"reflect"
+	"sort"
+)
+
+// slot is one child of a node: where it's read from and how ---
+// it's replaced. set reports false when the new node doesn't fit ---
+type slot struct {
+	get func() Node
+	set func(Node) bool
+}
+
+// A missing child reads as nil ---
+func expressionSlot(expr *Expression) slot {
+	return slot{
+		get: func() Node {
+			if *expr == nil {
+				return nil
+			}
+			return *expr
+		},
+		set: func(n Node) bool {
+			e, ok := n.(Expression)
+			if ok {
+				*expr = e
+			}
+			return ok
+		},
+	}
+}
+
+func statementSlot(stmt *Statement) slot {
+	return slot{
+		get: func() Node {
+			if *stmt == nil {
+				return nil
+			}
+			return *stmt
+		},
+		set: func(n Node) bool {
+			s, ok := n.(Statement)
+			if ok {
+				*stmt = s
+			}
+			return ok
+		},
+	}
+}
+
+func blockSlot(block **BlockStatement) slot {
+	return slot{
+		get: func() Node {
+			if *block == nil {
+				return nil
+			}
+			return *block
+		},
+		set: func(n Node) bool {
+			b, ok := n.(*BlockStatement)
+			if ok {
+				*block = b
+			}
+			return ok
+		},
+	}
+}
+
+func identifierSlot(ident **Identifier) slot {
+	return slot{
+		get: func() Node {
+			if *ident == nil {
+				return nil
+			}
+			return *ident
+		},
+		set: func(n Node) bool {
+			i, ok := n.(*Identifier)
+			if ok {
+				*ident = i
+			}
+			return ok
+		},
+	}
+}
+
+func typeSlot(annotation **TypeAnnotation) slot {
+	return slot{
+		get: func() Node {
+			if *annotation == nil {
+				return nil
+			}
+			return *annotation
+		},
+		set: func(n Node) bool {
+			t, ok := n.(*TypeAnnotation)
+			if ok {
+				*annotation = t
+			}
+			return ok
+		},
+	}
+}
+
+func callSlot(call **CallExpression) slot {
+	return slot{
+		get: func() Node { return *call },
+		set: func(n Node) bool {
+			c, ok := n.(*CallExpression)
+			if ok {
+				*call = c
+			}
+			return ok
+		},
+	}
+}
+
+func identifierSlots(idents []*Identifier) []slot {
+	slots := make([]slot, len(idents))
+	for i := range idents {
+		slots[i] = identifierSlot(&idents[i])
+	}
+	return slots
+}
+
+func expressionSlots(exprs []Expression) []slot {
+	slots := make([]slot, len(exprs))
+	for i := range exprs {
+		slots[i] = expressionSlot(&exprs[i])
+	}
+	return slots
+}
+
+func statementSlots(stmts []Statement) []slot {
+	slots := make([]slot, len(stmts))
+	for i := range stmts {
+		slots[i] = statementSlot(&stmts[i])
+	}
+	return slots
+}
+
+// parameterSlots interleaves each parameter, or the pattern in its ---
+// place, with its annotation ---
+func parameterSlots(params []*Identifier, patterns []Expression, types []*TypeAnnotation) []slot {
+	slots := make([]slot, 0, len(params)+len(types))
+	for i := range params {
+		if i < len(patterns) && patterns[i] != nil {
+			slots = append(slots, expressionSlot(&patterns[i]))
+		} else {
+			slots = append(slots, identifierSlot(&params[i]))
+		}
+		if i < len(types) {
+			slots = append(slots, typeSlot(&types[i]))
+		}
+	}
+	return slots
+}
+
+// Hash keys are map keys, replacing one moves its value over ---
+func hashSlots(hash *HashLiteral) []slot {
+	slots := make([]slot, 0, len(hash.Pairs)*2)
+
+	for _, key := range SortedKeys(hash) {
+		slots = append(slots,
+			slot{
+				get: func() Node { return key },
+				set: func(n Node) bool {
+					replacement, ok := n.(Expression)
+					if !ok {
+						return false
+					}
+					value := hash.Pairs[key]
+					delete(hash.Pairs, key)
+					hash.Pairs[replacement] = value
+					key = replacement
+					return true
+				},
+			},
+			slot{
+				get: func() Node { return hash.Pairs[key] },
+				set: func(n Node) bool {
+					value, ok := n.(Expression)
+					if ok {
+						hash.Pairs[key] = value
+					}
+					return ok
+				},
+			},
+		)
+	}
+
+	return slots
+}
+
+// SortedKeys orders a hash literal's keys as they appear in the source ---
+func SortedKeys(hash *HashLiteral) []Expression {
+	keys := make([]Expression, 0, len(hash.Pairs))
+	for key := range hash.Pairs {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		return a.GetLine() < b.GetLine() || (a.GetLine() == b.GetLine() && a.GetColumn() < b.GetColumn())
+	})
+
+	return keys
+}
+
+// slots lists the children of node in source order ---
+func slots(node Node) []slot {
+	switch node := node.(type) {
+	case *Program:
+		return statementSlots(node.Statements)
+	case *BlockStatement:
+		return statementSlots(node.Statements)
+
+	case *ExpressionStatement:
+		return []slot{expressionSlot(&node.Expression)}
+	case *VarStatement:
+		return append(identifierSlots(node.Names), expressionSlot(&node.Pattern), typeSlot(&node.Type), expressionSlot(&node.Value))
+	case *BatchAssignmentStatement:
+		return append(identifierSlots(node.Assignees), expressionSlot(&node.Pattern), expressionSlot(&node.NewValue))
+	case *ReturnStatement:
+		return []slot{expressionSlot(&node.ReturnValue)}
+	case *ThrowStatement:
+		return []slot{expressionSlot(&node.Value)}
+	case *IfStatement:
+		return []slot{expressionSlot(&node.Condition), statementSlot(&node.Consequence), statementSlot(&node.Alternative)}
+	case *ForInStatement:
+		return []slot{identifierSlot(&node.Variable), expressionSlot(&node.Iterable), blockSlot(&node.Body)}
+	case *TryStatement:
+		return []slot{blockSlot(&node.Block), identifierSlot(&node.CatchParam), blockSlot(&node.Catch), blockSlot(&node.Finally)}
+
+	case *FunctionDeclarationStatement:
+		children := []slot{identifierSlot(&node.Name)}
+		children = append(children, parameterSlots(node.Parameters, node.Patterns, node.ParameterTypes)...)
+		return append(children, typeSlot(&node.ReturnType), blockSlot(&node.Body))
+	case *FunctionLiteral:
+		children := parameterSlots(node.Parameters, node.Patterns, node.ParameterTypes)
+		return append(children, typeSlot(&node.ReturnType), blockSlot(&node.Body))
+	case *MacroLiteral:
+		return append(identifierSlots(node.Parameters), blockSlot(&node.Body))
+
+	case *UnaryExpression:
+		return []slot{expressionSlot(&node.Right)}
+	case *BinaryExpression:
+		return []slot{expressionSlot(&node.Left), expressionSlot(&node.Right)}
+	case *TernaryExpression:
+		return []slot{expressionSlot(&node.Consequence), expressionSlot(&node.Condition), expressionSlot(&node.Alternative)}
+	case *AbsoluteExpression:
+		return []slot{expressionSlot(&node.Value)}
+	case *AwaitExpression:
+		return []slot{expressionSlot(&node.Value)}
+	case *YieldExpression:
+		return []slot{expressionSlot(&node.Value)}
+	case *SpawnExpression:
+		return []slot{callSlot(&node.Call)}
+
+	case *CallExpression:
+		return append([]slot{expressionSlot(&node.Function)}, expressionSlots(node.Arguments)...)
+	case *AssignmentExpression:
+		return []slot{expressionSlot(&node.Assignee), expressionSlot(&node.NewValue)}
+	case *IndexExpression:
+		return []slot{expressionSlot(&node.Target), expressionSlot(&node.Index)}
+	case *IndexAssignmentExpression:
+		return []slot{expressionSlot(&node.Target), expressionSlot(&node.Index), expressionSlot(&node.NewValue)}
+	case *IndexSliceExpression:
+		return []slot{expressionSlot(&node.Target), expressionSlot(&node.Start), expressionSlot(&node.End)}
+
+	case *ArrayLiteral:
+		return expressionSlots(node.Elements)
+	case *InterpolatedString:
+		return expressionSlots(node.Parts)
+	case *HashLiteral:
+		return hashSlots(node)
+
+	case *ArrayPattern:
+		return append(expressionSlots(node.Elements), identifierSlot(&node.Rest))
+	case *HashPattern:
+		children := make([]slot, len(node.Entries))
+		for i, entry := range node.Entries {
+			children[i] = expressionSlot(&entry.Value)
+		}
+		return children
+	case *DefaultPattern:
+		return []slot{expressionSlot(&node.Target), expressionSlot(&node.Default)}
+
+	case *TypeAnnotation:
+		return []slot{typeSlot(&node.Key), typeSlot(&node.Element)}
+	}
+
+	// Literals and identifiers are leaves ---
+	return nil
+}
+
+// Children lists the children of node in source order ---
+func Children(node Node) []Node {
+	children := make([]Node, 0)
+	for _, s := range slots(node) {
+		if child := s.get(); !IsNil(child) {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// IsNil also catches the typed nil nodes a parse error can leave behind ---
+func IsNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(node)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// A Visitor's Visit is called for each node Walk finds. If it returns ---
+// a Visitor w, Walk visits the node's children with w and then calls ---
+// w.Visit(nil) ---
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses node depth first, in source order ---
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	for _, child := range Children(node) {
+		Walk(v, child)
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect calls f for node and everything under it, depth first. ---
+// Returning false skips the node's children, after the children ---
+// f is called with nil ---
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Parents maps every node under root to the node holding it ---
+func Parents(root Node) map[Node]Node {
+	parents := make(map[Node]Node)
+	stack := make([]Node, 0)
+
+	Inspect(root, func(node Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+
+		if len(stack) != 0 {
+			parents[node] = stack[len(stack)-1]
+		}
+		stack = append(stack, node)
+		return true
+	})
+
+	return parents
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/caelondev/monkey-compiler-go/src/code"
 	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/evaluation"
 	"github.com/caelondev/monkey-compiler-go/src/lexer"
 	"github.com/caelondev/monkey-compiler-go/src/object"
 	"github.com/caelondev/monkey-compiler-go/src/parser"
@@ -16,7 +17,7 @@ import (
 )
 
 const MAGIC = "MCGO"
-const VERSION = 1
+const VERSION = vm.BYTECODE_VERSION
 
 func BuildFile(path string) {
 	input, err := os.ReadFile(path)
@@ -35,7 +36,14 @@ func BuildFile(path string) {
 		return
 	}
 
+	program, expandErr := evaluation.Expand(program)
+	if expandErr != nil {
+		fmt.Println(expandErr.Inspect())
+		return
+	}
+
 	comp := compiler.New()
+	comp.File = path
 	err = comp.Compile(program)
 	if err != nil {
 		panic(err)
@@ -44,7 +52,7 @@ func BuildFile(path string) {
 	bytecode := comp.Bytecode()
 
 	// Convert bytecode to raw bytes
-	encodedBytes := EncodeBytecode(bytecode.Constants, bytecode.Instructions)
+	encodedBytes := EncodeBytecode(bytecode)
 
 	outputPath := FormatFileName(path)
 	WriteByteToFile(outputPath, encodedBytes)
@@ -52,13 +60,18 @@ func BuildFile(path string) {
 	fmt.Println("Build successful")
 }
 
-func EncodeBytecode(constants []object.Object, instructions []byte) []byte {
+func EncodeBytecode(bytecode *compiler.Bytecode) []byte {
 	buf := new(bytes.Buffer)
 
 	buf.Write([]byte(MAGIC))
 	buf.WriteByte(VERSION)
-	buf.Write(serializeConstants(constants))
-	buf.Write(serializeInstructions(instructions))
+	buf.Write(serializeConstants(bytecode.Constants))
+	buf.Write(serializeInstructions(bytecode.Instructions))
+
+	// Error handling and debug info, every section is required ---
+	buf.Write(serializeHandlers(bytecode.Handlers))
+	buf.Write(serializeLines(bytecode.Lines))
+	writeString(buf, bytecode.File)
 
 	return buf.Bytes()
 }
@@ -111,6 +124,9 @@ func DisassembleFile(path string) error {
 				val := int(instructions[i])<<8 | int(instructions[i+1])
 				fmt.Printf(" [%d]", val)
 				i += 2
+			} else if width == 1 {
+				fmt.Printf(" [%d]", instructions[i])
+				i += 1
 			} else {
 				fmt.Printf(" [unsupported operand width %d]", width)
 			}
@@ -124,8 +140,17 @@ func DisassembleFile(path string) error {
 		switch v := c.(type) {
 		case *object.Number:
 			fmt.Printf("%d: %g\n", idx, v.Value)
+		case *object.Integer:
+			fmt.Printf("%d: %d\n", idx, v.Value)
+		case *object.BigInt, *object.Decimal:
+			fmt.Printf("%d: %s\n", idx, v.Inspect())
 		case *object.String:
 			fmt.Printf("%d: \"%s\"\n", idx, v.Value)
+		case *object.CompiledFunction:
+			fmt.Printf("%d: %s\n", idx, v.Inspect())
+			fmt.Print(v.Instructions.String())
+		case *object.Quote:
+			fmt.Printf("%d: %s\n", idx, v.Inspect())
 
 		default:
 			fmt.Printf("%d: unknown constant type %T\n", idx, c)
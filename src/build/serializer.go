@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/binary"
 
+	"github.com/caelondev/monkey-compiler-go/src/ast"
 	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/format"
 	"github.com/caelondev/monkey-compiler-go/src/object"
 )
 
@@ -16,6 +18,10 @@ func writeFloat64(buf *bytes.Buffer, v float64) {
 	_ = binary.Write(buf, binary.BigEndian, v)
 }
 
+func writeInt64(buf *bytes.Buffer, v int64) {
+	_ = binary.Write(buf, binary.BigEndian, v)
+}
+
 func writeString(buf *bytes.Buffer, v string) {
 	writeUint32(buf, uint32(len(v)))
 	buf.WriteString(v)
@@ -32,9 +38,37 @@ func serializeConstants(constants []object.Object) []byte {
 		case *object.Number:
 			buf.WriteByte(byte(code.CONSTANT_NUMBER))
 			writeFloat64(buf, obj.Value)
+		case *object.Integer:
+			buf.WriteByte(byte(code.CONSTANT_INTEGER))
+			writeInt64(buf, obj.Value)
+		case *object.BigInt:
+			buf.WriteByte(byte(code.CONSTANT_BIGINT))
+			writeString(buf, obj.Value.String())
+		case *object.Decimal:
+			buf.WriteByte(byte(code.CONSTANT_DECIMAL))
+			writeString(buf, obj.Unscaled.String())
+			writeUint32(buf, uint32(obj.Scale))
 		case *object.String:
 			buf.WriteByte(byte(code.CONSTANT_STRING))
 			writeString(buf, obj.Value)
+		case *object.CompiledFunction:
+			buf.WriteByte(byte(code.CONSTANT_FUNCTION))
+			writeString(buf, obj.Name)
+			writeUint32(buf, uint32(obj.NumLocals))
+			writeUint32(buf, uint32(obj.NumParameters))
+			if obj.IsGenerator {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+			buf.Write(serializeInstructions(obj.Instructions))
+			buf.Write(serializeHandlers(obj.Handlers))
+			buf.Write(serializeLines(obj.Lines))
+
+		case *object.Quote:
+			// String() drops grouping parentheses, the formatter keeps them ---
+			buf.WriteByte(byte(code.CONSTANT_QUOTE))
+			writeString(buf, quoteSource(obj.Node))
 
 		default:
 			panic("unsupported constant type")
@@ -50,3 +84,41 @@ func serializeInstructions(instructions []byte) []byte {
 	buf.Write(instructions)
 	return buf.Bytes()
 }
+
+func serializeHandlers(handlers []code.Handler) []byte {
+	buf := new(bytes.Buffer)
+	writeUint32(buf, uint32(len(handlers)))
+
+	for _, handler := range handlers {
+		writeUint32(buf, uint32(handler.Start))
+		writeUint32(buf, uint32(handler.End))
+		writeUint32(buf, uint32(handler.Target))
+		writeUint32(buf, uint32(handler.StackDepth))
+		writeUint32(buf, uint32(handler.Finally))
+	}
+
+	return buf.Bytes()
+}
+
+func serializeLines(lines []code.SourcePos) []byte {
+	buf := new(bytes.Buffer)
+	writeUint32(buf, uint32(len(lines)))
+
+	for _, pos := range lines {
+		writeUint32(buf, uint32(pos.Offset))
+		writeUint32(buf, uint32(pos.Line))
+		writeUint32(buf, uint32(pos.Column))
+	}
+
+	return buf.Bytes()
+}
+
+// quoteSource is the text a quote constant is stored as, ---
+// the decoder parses it back ---
+func quoteSource(node ast.Node) string {
+	if expr, ok := node.(ast.Expression); ok {
+		return format.Expression(expr)
+	}
+
+	return node.String()
+}
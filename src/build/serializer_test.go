@@ -0,0 +1,66 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/format"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
+	"github.com/caelondev/monkey-compiler-go/src/vm"
+)
+
+func TestQuoteRoundTrip(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{`quote((1 + 2) * 3);`, "(1 + 2) * 3"},
+		{`quote(-(a - b) ^ (2 - c));`, "-(a - b) ^ (2 - c)"},
+		{`quote(f(x)[0] + "a\nb");`, `f(x)[0] + "a\nb"`},
+		{`quote(fn(a) { return (a + 1) * 2; });`, "(fn(a) {\n    return (a + 1) * 2;\n})"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.source))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%q: parser errors: %v", tt.source, p.Errors())
+		}
+
+		comp := compiler.New()
+		if err := comp.Compile(program); err != nil {
+			t.Fatalf("%q: compiler error: %s", tt.source, err)
+		}
+
+		original := findQuote(t, comp.Bytecode().Constants)
+
+		decoded, err := vm.DecodeBytecode(EncodeBytecode(comp.Bytecode()))
+		if err != nil {
+			t.Fatalf("%q: decode error: %s", tt.source, err)
+		}
+		reloaded := findQuote(t, decoded.Constants)
+
+		// Printed with grouping, equal text means an equal tree ---
+		before := format.Expression(original.Node.(ast.Expression))
+		after := format.Expression(reloaded.Node.(ast.Expression))
+		if before != tt.expected || after != tt.expected {
+			t.Errorf("%q: expected %q, got %q before and %q after the round trip", tt.source, tt.expected, before, after)
+		}
+	}
+}
+
+func findQuote(t *testing.T, constants []object.Object) *object.Quote {
+	t.Helper()
+
+	for _, constant := range constants {
+		if quote, ok := constant.(*object.Quote); ok {
+			return quote
+		}
+	}
+
+	t.Fatalf("no quote constant")
+	return nil
+}
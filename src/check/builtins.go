@@ -0,0 +1,75 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+type arity struct {
+	min, max int // max is -1 for variadic builtins ---
+}
+
+func (a arity) accepts(n int) bool {
+	return n >= a.min && (a.max == -1 || n <= a.max)
+}
+
+func (a arity) String() string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+
+	switch {
+	case a.max == -1:
+		return "at least " + plural(a.min)
+	case a.min == a.max:
+		return plural(a.min)
+	default:
+		return fmt.Sprintf("%d to %s", a.min, plural(a.max))
+	}
+}
+
+// Keep in step with object.BuiltinNames ---
+var builtinArity = map[string]arity{
+	"len":        {1, 1},
+	"print":      {0, -1},
+	"prompt":     {1, 1},
+	"time":       {0, 0},
+	"to_string":  {1, 1},
+	"to_number":  {1, 1},
+	"to_integer": {1, 1},
+	"bigint":     {1, 1},
+	"decimal":    {1, 1},
+	"type":       {1, 1},
+	"is_NaN":     {1, 1},
+	"is_Inf":     {1, 1},
+	"is_nil":     {1, 1},
+	"random":     {0, 0},
+	"next":       {1, 2},
+	"is_done":    {1, 1},
+	"div":        {2, 2},
+	"mod":        {2, 2},
+
+	"channel": {0, 1},
+	"send":    {2, 2},
+	"recv":    {1, 1},
+	"close":   {1, 1},
+	"select":  {1, -1},
+
+	// Not values, the evaluator handles them when they're called ---
+	"quote":   {1, 1},
+	"unquote": {1, 1},
+}
+
+func isBuiltin(name string) bool {
+	for _, builtin := range object.BuiltinNames {
+		if builtin == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+// Package check is the static checker behind `monkey check`, it finds
+// mistakes that otherwise only surface when the evaluator reaches them.
+package check
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+type Rule string
+
+const (
+	Unresolved  Rule = "unresolved"
+	Redeclared  Rule = "redeclared"
+	NotCallable Rule = "not-callable"
+	Arity       Rule = "arity"
+	Unreachable Rule = "unreachable"
+	Unused      Rule = "unused"
+
+	// Only runs with Config.Types ---
+	Types Rule = "types"
+)
+
+// Rules lists every rule, in the order they're documented ---
+var Rules = []Rule{Unresolved, Redeclared, NotCallable, Arity, Unreachable, Unused, Types}
+
+type Diagnostic struct {
+	Rule    Rule
+	Line    uint
+	Column  uint
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("[Ln %d:%d] %s (%s)", d.Line, d.Column, d.Message, d.Rule)
+}
+
+// Config turns rules off, every rule but types runs by default ---
+type Config struct {
+	Disabled map[Rule]bool
+
+	// Infers types and checks them against the annotations ---
+	Types bool
+}
+
+func (c Config) enabled(rule Rule) bool {
+	return !c.Disabled[rule]
+}
+
+// Source parses and checks a whole file, a parse error is returned as is.
+func Source(source string, config Config) ([]Diagnostic, error) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(p.Errors()[0])
+	}
+
+	return Program(program, config), nil
+}
+
+// Program checks a parsed program, diagnostics come back in source order.
+func Program(program *ast.Program, config Config) []Diagnostic {
+	c := newChecker(config.Types && config.enabled(Types))
+	c.run(program)
+
+	ignored := ignoredLines(program.Comments)
+
+	diagnostics := make([]Diagnostic, 0, len(c.diagnostics))
+	for _, d := range c.diagnostics {
+		if config.enabled(d.Rule) && !ignored.covers(d) {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+
+	return diagnostics
+}
+
+// ignores maps a line to the rules silenced on it, ---
+// an empty set silences every rule ---
+type ignores map[uint]map[Rule]bool
+
+// `// lint:ignore [rule...]` silences its own line and the next one ---
+func ignoredLines(comments []token.Token) ignores {
+	lines := make(ignores)
+
+	for _, comment := range comments {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Literal, "//"))
+		rest, ok := strings.CutPrefix(text, "lint:ignore")
+		if !ok || (rest != "" && rest[0] != ' ') {
+			continue
+		}
+
+		rules := make(map[Rule]bool)
+		for _, name := range strings.Fields(rest) {
+			rules[Rule(name)] = true
+		}
+
+		lines[comment.Line] = rules
+		lines[comment.Line+1] = rules
+	}
+
+	return lines
+}
+
+func (i ignores) covers(d Diagnostic) bool {
+	rules, ok := i[d.Line]
+	return ok && (len(rules) == 0 || rules[d.Rule])
+}
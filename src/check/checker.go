@@ -0,0 +1,493 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+type symbolKind int
+
+const (
+	variableSymbol symbolKind = iota
+	parameterSymbol
+	functionSymbol
+)
+
+type symbol struct {
+	ident *ast.Identifier
+	kind  symbolKind
+
+	valueType  object.ObjectType // Of the initializer, "" when unknown ---
+	reassigned bool
+	reads      int
+	local      bool // Declared inside a function ---
+
+	// For `--types`, declared is the annotation. Otherwise the type ---
+	// is inferred from value, in scope, when it's first needed ---
+	declared     *Type
+	value        ast.Expression
+	scope        *scope
+	inferredType *Type
+	inferred     bool
+	inferring    bool
+}
+
+// Only functions open a scope, like in both engines ---
+type scope struct {
+	outer    *scope
+	symbols  map[string]*symbol
+	function bool  // False for the global scope ---
+	returns  *Type // The function's return annotation ---
+}
+
+func (s *scope) resolve(name string) (*symbol, bool) {
+	for ; s != nil; s = s.outer {
+		if sym, ok := s.symbols[name]; ok {
+			return sym, true
+		}
+	}
+
+	return nil, false
+}
+
+type call struct {
+	node   *ast.CallExpression
+	callee *symbol
+}
+
+type checker struct {
+	diagnostics []Diagnostic
+
+	// Function bodies are checked after the code around them, ---
+	// by the time they're called everything outside is declared ---
+	pending []func()
+
+	calls   []call
+	symbols []*symbol
+
+	types      bool
+	typeChecks []func()
+}
+
+func newChecker(types bool) *checker {
+	return &checker{diagnostics: make([]Diagnostic, 0), types: types}
+}
+
+func (c *checker) report(rule Rule, node ast.Node, format string, a ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Rule:    rule,
+		Line:    node.GetLine(),
+		Column:  node.GetColumn(),
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (c *checker) run(program *ast.Program) {
+	global := &scope{symbols: make(map[string]*symbol)}
+	c.statements(program.Statements, global)
+
+	for len(c.pending) != 0 {
+		next := c.pending[0]
+		c.pending = c.pending[1:]
+		next()
+	}
+
+	for _, check := range c.typeChecks {
+		check()
+	}
+
+	c.checkCalls()
+	c.checkUnused()
+}
+
+// [ SYMBOLS ] ---
+
+func (c *checker) declare(s *scope, ident *ast.Identifier, kind symbolKind, valueType object.ObjectType) *symbol {
+	if previous, exists := s.symbols[ident.Value]; exists {
+		c.report(Redeclared, ident, "'%s' is already declared at Ln %d:%d", ident.Value, previous.ident.Token.Line, previous.ident.Token.Column)
+	} else if s.outer == nil && isBuiltin(ident.Value) {
+		c.report(Redeclared, ident, "'%s' is a builtin and can't be redeclared", ident.Value)
+	}
+
+	sym := &symbol{ident: ident, kind: kind, valueType: valueType, local: s.function, scope: s}
+	s.symbols[ident.Value] = sym
+	c.symbols = append(c.symbols, sym)
+	return sym
+}
+
+// bind is for loop variables and catch parameters, which reuse ---
+// a variable of the same scope instead of redeclaring it ---
+func (c *checker) bind(s *scope, ident *ast.Identifier) {
+	if sym, exists := s.symbols[ident.Value]; exists {
+		sym.reassigned = true
+		return
+	}
+
+	// Never reported as unused, they're bound by the statement ---
+	sym := c.declare(s, ident, parameterSymbol, "")
+	sym.reads++
+}
+
+func (c *checker) read(s *scope, ident *ast.Identifier) *symbol {
+	if sym, ok := s.resolve(ident.Value); ok {
+		sym.reads++
+		return sym
+	}
+
+	if _, special := builtinArity[ident.Value]; !special && !isBuiltin(ident.Value) {
+		c.report(Unresolved, ident, "Cannot resolve variable '%s'", ident.Value)
+	}
+
+	return nil
+}
+
+func (c *checker) write(s *scope, ident *ast.Identifier) {
+	if sym, ok := s.resolve(ident.Value); ok {
+		sym.reassigned = true
+		return
+	}
+
+	c.report(Unresolved, ident, "Assignment to an undefined variable '%s'", ident.Value)
+}
+
+// [ STATEMENTS ] ---
+
+func (c *checker) statements(stmts []ast.Statement, s *scope) {
+	for i, stmt := range stmts {
+		c.statement(stmt, s)
+
+		if i+1 < len(stmts) && exits(stmt) {
+			c.report(Unreachable, stmts[i+1], "Unreachable code after '%s'", stmt.TokenLiteral())
+		}
+	}
+}
+
+// exits reports whether control never continues past stmt ---
+func exits(stmt ast.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStatement, *ast.ThrowStatement:
+		return true
+	case *ast.BlockStatement:
+		return len(stmt.Statements) != 0 && exits(stmt.Statements[len(stmt.Statements)-1])
+	case *ast.IfStatement:
+		return stmt.Alternative != nil && exits(stmt.Consequence) && exits(stmt.Alternative)
+	}
+
+	return false
+}
+
+func (c *checker) statement(stmt ast.Statement, s *scope) {
+	switch stmt := stmt.(type) {
+	case *ast.VarStatement:
+		c.expression(stmt.Value, s)
+		if stmt.Pattern != nil {
+			c.pattern(stmt.Pattern, s, func(name *ast.Identifier) {
+				c.declare(s, name, variableSymbol, "")
+			})
+			return
+		}
+
+		declared := fromAnnotation(stmt.Type)
+		for _, name := range stmt.Names {
+			sym := c.declare(s, name, variableSymbol, literalType(stmt.Value))
+			sym.declared, sym.value = declared, stmt.Value
+		}
+
+		// `var x: number;` leaves it nil until it's assigned ---
+		if nilLit, ok := stmt.Value.(*ast.NilLiteral); stmt.Type != nil && (!ok || nilLit.Token.Type != token.SEMICOLON) {
+			c.typed(func() { c.expect(declared, stmt.Value, s, "Variable '"+stmt.Names[0].Value+"'") })
+		}
+
+	case *ast.BatchAssignmentStatement:
+		c.expression(stmt.NewValue, s)
+		if stmt.Pattern != nil {
+			c.pattern(stmt.Pattern, s, func(name *ast.Identifier) { c.write(s, name) })
+			return
+		}
+
+		for _, assignee := range stmt.Assignees {
+			value := ast.Assigned(stmt.Assign, stmt.Operator, assignee, stmt.NewValue)
+			if binary, ok := value.(*ast.BinaryExpression); ok {
+				c.read(s, assignee)
+				c.typed(func() { c.checkBinary(binary, s) })
+			}
+
+			c.write(s, assignee)
+			c.typed(func() { c.checkAssignment(assignee, value, s) })
+		}
+
+	case *ast.FunctionDeclarationStatement:
+		// Declared first, so the body can call itself ---
+		sym := c.declare(s, stmt.Name, functionSymbol, object.FUNCTION_OBJECT)
+		sym.inferredType, sym.inferred = functionType(stmt.ParameterTypes, stmt.ReturnType, stmt.IsGenerator), true
+		c.function(stmt.Parameters, stmt.Patterns, stmt.ParameterTypes, stmt.ReturnType, stmt.Body, s)
+
+	case *ast.ExpressionStatement:
+		c.expression(stmt.Expression, s)
+
+	case *ast.ReturnStatement:
+		if stmt.ReturnValue != nil {
+			c.expression(stmt.ReturnValue, s)
+		}
+		c.typed(func() { c.checkReturn(stmt, s) })
+
+	case *ast.ThrowStatement:
+		c.expression(stmt.Value, s)
+
+	case *ast.BlockStatement:
+		c.statements(stmt.Statements, s)
+
+	case *ast.IfStatement:
+		c.expression(stmt.Condition, s)
+		c.statement(stmt.Consequence, s)
+		if stmt.Alternative != nil {
+			c.statement(stmt.Alternative, s)
+		}
+
+	case *ast.ForInStatement:
+		c.expression(stmt.Iterable, s)
+		c.typed(func() { c.checkIterable(stmt, s) })
+		c.bind(s, stmt.Variable)
+		c.statement(stmt.Body, s)
+
+	case *ast.TryStatement:
+		c.statement(stmt.Block, s)
+		if stmt.Catch != nil {
+			if stmt.CatchParam != nil {
+				c.bind(s, stmt.CatchParam)
+			}
+			c.statement(stmt.Catch, s)
+		}
+		if stmt.Finally != nil {
+			c.statement(stmt.Finally, s)
+		}
+	}
+}
+
+func (c *checker) function(params []*ast.Identifier, patterns []ast.Expression, types []*ast.TypeAnnotation, returns *ast.TypeAnnotation, body *ast.BlockStatement, outer *scope) {
+	s := &scope{outer: outer, symbols: make(map[string]*symbol), function: true, returns: fromAnnotation(returns)}
+	for i, param := range params {
+		sym := c.declare(s, param, parameterSymbol, "")
+		if i < len(types) {
+			sym.declared = fromAnnotation(types[i])
+		}
+	}
+
+	c.pending = append(c.pending, func() {
+		// A pattern's parameter is read by unpacking it ---
+		for i, pattern := range patterns {
+			if pattern == nil {
+				continue
+			}
+
+			s.symbols[params[i].Value].reads++
+			c.pattern(pattern, s, func(name *ast.Identifier) {
+				c.declare(s, name, parameterSymbol, "")
+			})
+		}
+
+		c.statements(body.Statements, s)
+	})
+}
+
+// pattern checks the defaults of pattern and hands its names to bind, ---
+// in the order they're unpacked ---
+func (c *checker) pattern(pattern ast.Expression, s *scope, bind func(*ast.Identifier)) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		bind(pattern)
+
+	case *ast.DefaultPattern:
+		c.expression(pattern.Default, s)
+		c.pattern(pattern.Target, s, bind)
+
+	case *ast.ArrayPattern:
+		for _, element := range pattern.Elements {
+			c.pattern(element, s, bind)
+		}
+		if pattern.Rest != nil {
+			bind(pattern.Rest)
+		}
+
+	case *ast.HashPattern:
+		for _, entry := range pattern.Entries {
+			c.pattern(entry.Value, s, bind)
+		}
+	}
+}
+
+// [ EXPRESSIONS ] ---
+
+func (c *checker) expression(expr ast.Expression, s *scope) {
+	switch expr := expr.(type) {
+	case *ast.Identifier:
+		c.read(s, expr)
+
+	case *ast.FunctionLiteral:
+		c.function(expr.Parameters, expr.Patterns, expr.ParameterTypes, expr.ReturnType, expr.Body, s)
+	case *ast.MacroLiteral:
+		c.function(expr.Parameters, nil, nil, nil, expr.Body, s)
+
+	case *ast.CallExpression:
+		c.call(expr, s)
+
+	case *ast.AssignmentExpression:
+		// x += v reads x too, and checks x + v ---
+		value := ast.Assigned(expr.Token, expr.Operator, expr.Assignee, expr.NewValue)
+		c.expression(value, s)
+		if ident, ok := expr.Assignee.(*ast.Identifier); ok {
+			c.write(s, ident)
+			c.typed(func() { c.checkAssignment(ident, value, s) })
+		}
+
+	case *ast.IndexAssignmentExpression:
+		c.expression(expr.Target, s)
+		c.expression(expr.Index, s)
+		c.expression(expr.NewValue, s)
+
+		element := &ast.IndexExpression{Token: expr.Token, Target: expr.Target, Index: expr.Index}
+		value := ast.Assigned(expr.Token, expr.Operator, element, expr.NewValue)
+		c.typed(func() {
+			elementType := c.checkIndex(expr, expr.Target, expr.Index, s)
+			if binary, ok := value.(*ast.BinaryExpression); ok {
+				c.checkBinary(binary, s)
+			}
+			c.expect(elementType, value, s, "Element")
+		})
+
+	case *ast.UnaryExpression:
+		c.expression(expr.Right, s)
+		c.typed(func() { c.checkUnary(expr, s) })
+	case *ast.BinaryExpression:
+		c.expression(expr.Left, s)
+		c.expression(expr.Right, s)
+		c.typed(func() { c.checkBinary(expr, s) })
+	case *ast.TernaryExpression:
+		c.expression(expr.Consequence, s)
+		c.expression(expr.Condition, s)
+		c.expression(expr.Alternative, s)
+	case *ast.IndexExpression:
+		c.expression(expr.Target, s)
+		c.expression(expr.Index, s)
+		c.typed(func() { c.checkIndex(expr, expr.Target, expr.Index, s) })
+	case *ast.IndexSliceExpression:
+		c.expression(expr.Target, s)
+		c.typed(func() { c.checkSlice(expr, s) })
+		if expr.Start != nil {
+			c.expression(expr.Start, s)
+		}
+		if expr.End != nil {
+			c.expression(expr.End, s)
+		}
+	case *ast.AbsoluteExpression:
+		c.expression(expr.Value, s)
+	case *ast.SpawnExpression:
+		c.call(expr.Call, s)
+	case *ast.AwaitExpression:
+		c.expression(expr.Value, s)
+	case *ast.YieldExpression:
+		if expr.Value != nil {
+			c.expression(expr.Value, s)
+		}
+
+	case *ast.ArrayLiteral:
+		for _, element := range expr.Elements {
+			c.expression(element, s)
+		}
+	case *ast.InterpolatedString:
+		for _, part := range expr.Parts {
+			c.expression(part, s)
+		}
+	case *ast.HashLiteral:
+		for key, value := range expr.Pairs {
+			c.expression(key, s)
+			c.expression(value, s)
+		}
+	}
+}
+
+func (c *checker) call(node *ast.CallExpression, s *scope) {
+	for _, arg := range node.Arguments {
+		c.expression(arg, s)
+	}
+	c.typed(func() { c.checkCall(node, s) })
+
+	ident, ok := node.Function.(*ast.Identifier)
+	if !ok {
+		c.expression(node.Function, s)
+
+		if valueType := literalType(node.Function); valueType != "" && valueType != object.FUNCTION_OBJECT {
+			c.report(NotCallable, node.Function, "Cannot call a value of type '%s'", valueType)
+		}
+		return
+	}
+
+	if sym := c.read(s, ident); sym != nil {
+		// Known once every assignment has been seen ---
+		c.calls = append(c.calls, call{node: node, callee: sym})
+		return
+	}
+
+	if arity, ok := builtinArity[ident.Value]; ok && !arity.accepts(len(node.Arguments)) {
+		c.report(Arity, ident, "%s() expects %s, got %d", ident.Value, arity, len(node.Arguments))
+	}
+}
+
+func (c *checker) checkCalls() {
+	for _, call := range c.calls {
+		sym := call.callee
+		if sym.reassigned {
+			continue
+		}
+
+		if sym.valueType != "" && sym.valueType != object.FUNCTION_OBJECT {
+			c.report(NotCallable, call.node.Function, "Cannot call '%s', it holds a value of type '%s'", sym.ident.Value, sym.valueType)
+		}
+	}
+}
+
+func (c *checker) checkUnused() {
+	for _, sym := range c.symbols {
+		// Globals may be there for a script's side effects ---
+		if !sym.local || sym.kind != variableSymbol || sym.reads != 0 || sym.ident.Value[0] == '_' {
+			continue
+		}
+
+		c.report(Unused, sym.ident, "'%s' is declared but never used", sym.ident.Value)
+	}
+}
+
+// literalType is the type of values that are known without running anything ---
+func literalType(expr ast.Expression) object.ObjectType {
+	switch expr.(type) {
+	case *ast.NumberLiteral:
+		return object.NUMBER_OBJECT
+	case *ast.IntegerLiteral:
+		return object.INTEGER_OBJECT
+	case *ast.BigIntLiteral:
+		return object.BIGINT_OBJECT
+	case *ast.DecimalLiteral:
+		return object.DECIMAL_OBJECT
+	case *ast.NaNLiteral:
+		return object.NAN_OBJECT
+	case *ast.InfinityLiteral:
+		return object.INFINITY_OBJECT
+	case *ast.StringLiteral, *ast.InterpolatedString:
+		return object.STRING_OBJECT
+	case *ast.BooleanExpression:
+		return object.BOOLEAN_OBJECT
+	case *ast.NilLiteral:
+		return object.NIL_OBJECT
+	case *ast.ArrayLiteral:
+		return object.ARRAY_OBJECT
+	case *ast.HashLiteral:
+		return object.HASH_OBJECT
+	case *ast.FunctionLiteral:
+		return object.FUNCTION_OBJECT
+	}
+
+	return ""
+}
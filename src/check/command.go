@@ -0,0 +1,128 @@
+package check
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/run"
+)
+
+// Command runs `monkey check [--types] [-disable rules] [-only rules] <paths>`, ---
+// printing one line per diagnostic and failing if there were any ---
+func Command(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	disable := flags.String("disable", "", "comma separated rules to turn off")
+	only := flags.String("only", "", "comma separated rules to run, instead of all of them")
+	types := flags.Bool("types", false, "also infer types and check them against the annotations")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("Usage: monkey check [--types] [-disable rules] [-only rules] <path>...\nRules: %s", ruleList(Rules))
+	}
+
+	config, err := newConfig(*disable, *only)
+	if err != nil {
+		return err
+	}
+	config.Types = *types
+
+	files, err := run.SourceFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	found := 0
+	for _, path := range files {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		diagnostics, err := Source(string(source), config)
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+
+		for _, d := range diagnostics {
+			fmt.Fprintf(out, "%s:%d:%d: %s (%s)\n", path, d.Line, d.Column, d.Message, d.Rule)
+		}
+		found += len(diagnostics)
+	}
+
+	if found != 0 {
+		return fmt.Errorf("%d problem(s) found", found)
+	}
+
+	return nil
+}
+
+func newConfig(disable, only string) (Config, error) {
+	config := Config{Disabled: make(map[Rule]bool)}
+
+	disabled, err := parseRules(disable)
+	if err != nil {
+		return config, err
+	}
+	for _, rule := range disabled {
+		config.Disabled[rule] = true
+	}
+
+	if only == "" {
+		return config, nil
+	}
+
+	enabled, err := parseRules(only)
+	if err != nil {
+		return config, err
+	}
+	for _, rule := range Rules {
+		config.Disabled[rule] = true
+	}
+	for _, rule := range enabled {
+		delete(config.Disabled, rule)
+	}
+
+	return config, nil
+}
+
+func parseRules(list string) ([]Rule, error) {
+	rules := make([]Rule, 0)
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if !isRule(Rule(name)) {
+			return nil, fmt.Errorf("Unknown rule '%s', expected one of %s", name, ruleList(Rules))
+		}
+		rules = append(rules, Rule(name))
+	}
+
+	return rules, nil
+}
+
+func isRule(rule Rule) bool {
+	for _, known := range Rules {
+		if known == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ruleList(rules []Rule) string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = string(rule)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,164 @@
+package check
+
+import (
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// Type is what `--types` knows about a value. A nil *Type is any, ---
+// it's compatible with everything, which keeps the checking gradual ---
+type Type struct {
+	Kind object.ObjectType
+
+	Key     *Type // Hash keys ---
+	Element *Type // Array elements and hash values ---
+
+	// Functions, Signature is false when the parameters are unknown ---
+	Signature bool
+	Params    []*Type
+	Return    *Type
+	Generator bool
+}
+
+func (t *Type) String() string {
+	switch {
+	case t == nil:
+		return "any"
+	case t.Kind == object.ARRAY_OBJECT && t.Element != nil:
+		return "[" + t.Element.String() + "]"
+	case t.Kind == object.HASH_OBJECT && (t.Key != nil || t.Element != nil):
+		return "{" + t.Key.String() + ": " + t.Element.String() + "}"
+	case t.Kind == object.FUNCTION_OBJECT && t.Signature:
+		params := make([]string, len(t.Params))
+		for i, param := range t.Params {
+			params[i] = param.String()
+		}
+		return "FUNCTION(" + strings.Join(params, ", ") + "): " + t.Return.String()
+	}
+
+	return string(t.Kind)
+}
+
+var annotationKinds = map[string]object.ObjectType{
+	"number":   object.NUMBER_OBJECT,
+	"integer":  object.INTEGER_OBJECT,
+	"bigint":   object.BIGINT_OBJECT,
+	"decimal":  object.DECIMAL_OBJECT,
+	"string":   object.STRING_OBJECT,
+	"boolean":  object.BOOLEAN_OBJECT,
+	"nil":      object.NIL_OBJECT,
+	"function": object.FUNCTION_OBJECT,
+	"array":    object.ARRAY_OBJECT,
+	"hash":     object.HASH_OBJECT,
+}
+
+// fromAnnotation is nil for a missing annotation and for `any` ---
+func fromAnnotation(annotation *ast.TypeAnnotation) *Type {
+	if annotation == nil {
+		return nil
+	}
+
+	kind, ok := annotationKinds[annotation.Name]
+	if !ok {
+		return nil
+	}
+
+	return &Type{
+		Kind:    kind,
+		Key:     fromAnnotation(annotation.Key),
+		Element: fromAnnotation(annotation.Element),
+	}
+}
+
+func functionType(params []*ast.TypeAnnotation, returns *ast.TypeAnnotation, generator bool) *Type {
+	t := &Type{
+		Kind:      object.FUNCTION_OBJECT,
+		Signature: true,
+		Params:    make([]*Type, len(params)),
+		Return:    fromAnnotation(returns),
+		Generator: generator,
+	}
+
+	for i, param := range params {
+		t.Params[i] = fromAnnotation(param)
+	}
+
+	return t
+}
+
+// assignable reports whether a value of type from may be stored where ---
+// a value of type to is expected ---
+func assignable(to, from *Type) bool {
+	if to == nil || from == nil {
+		return true
+	}
+
+	// Integers are promoted where a number is expected ---
+	if to.Kind != from.Kind {
+		return to.Kind == object.NUMBER_OBJECT && from.Kind == object.INTEGER_OBJECT
+	}
+
+	switch to.Kind {
+	case object.ARRAY_OBJECT:
+		return assignable(to.Element, from.Element)
+	case object.HASH_OBJECT:
+		return assignable(to.Key, from.Key) && assignable(to.Element, from.Element)
+	case object.FUNCTION_OBJECT:
+		if !to.Signature || !from.Signature {
+			return true
+		}
+		if len(to.Params) != len(from.Params) {
+			return false
+		}
+		// Arguments flow the other way ---
+		for i := range to.Params {
+			if !assignable(from.Params[i], to.Params[i]) {
+				return false
+			}
+		}
+		return assignable(to.Return, from.Return)
+	}
+
+	return true
+}
+
+// join is the type shared by all of types, any when they disagree ---
+func join(types []*Type) *Type {
+	if len(types) == 0 {
+		return nil
+	}
+
+	for _, t := range types[1:] {
+		if t == nil || types[0] == nil || t.String() != types[0].String() {
+			return nil
+		}
+	}
+
+	return types[0]
+}
+
+// Keep in step with the builtins, missing ones return any ---
+var builtinReturns = map[string]object.ObjectType{
+	"len":        object.INTEGER_OBJECT,
+	"print":      object.NIL_OBJECT,
+	"prompt":     object.STRING_OBJECT,
+	"time":       object.NUMBER_OBJECT,
+	"to_string":  object.STRING_OBJECT,
+	"to_number":  object.NUMBER_OBJECT,
+	"to_integer": object.INTEGER_OBJECT,
+	"bigint":     object.BIGINT_OBJECT,
+	"decimal":    object.DECIMAL_OBJECT,
+	"type":       object.STRING_OBJECT,
+	"is_NaN":     object.BOOLEAN_OBJECT,
+	"is_Inf":     object.BOOLEAN_OBJECT,
+	"is_nil":     object.BOOLEAN_OBJECT,
+	"random":     object.NUMBER_OBJECT,
+	"is_done":    object.BOOLEAN_OBJECT,
+
+	"channel": object.CHANNEL_OBJECT,
+	"send":    object.NIL_OBJECT,
+	"close":   object.NIL_OBJECT,
+	"select":  object.ARRAY_OBJECT,
+}
@@ -0,0 +1,448 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// typed queues a type check, they run once every symbol is known ---
+// and only with `--types` ---
+func (c *checker) typed(check func()) {
+	if c.types {
+		c.typeChecks = append(c.typeChecks, check)
+	}
+}
+
+// symbolType is the annotation, or the initializer's type for a ---
+// variable that's never reassigned ---
+func (c *checker) symbolType(sym *symbol) *Type {
+	if sym.declared != nil || sym.reassigned {
+		return sym.declared
+	}
+
+	if !sym.inferred && !sym.inferring && sym.value != nil {
+		sym.inferring = true
+		sym.inferredType = c.typeOf(sym.value, sym.scope)
+		sym.inferring = false
+		sym.inferred = true
+	}
+
+	return sym.inferredType
+}
+
+// typeOf infers the type of expr without reporting anything, ---
+// nil when it can't be known ---
+func (c *checker) typeOf(expr ast.Expression, s *scope) *Type {
+	switch expr := expr.(type) {
+	case *ast.NumberLiteral, *ast.NaNLiteral, *ast.InfinityLiteral:
+		return &Type{Kind: object.NUMBER_OBJECT}
+	case *ast.IntegerLiteral:
+		return &Type{Kind: object.INTEGER_OBJECT}
+	case *ast.BigIntLiteral:
+		return &Type{Kind: object.BIGINT_OBJECT}
+	case *ast.DecimalLiteral:
+		return &Type{Kind: object.DECIMAL_OBJECT}
+	case *ast.AbsoluteExpression:
+		return numericType(c.typeOf(expr.Value, s), nil)
+	case *ast.StringLiteral, *ast.InterpolatedString:
+		return &Type{Kind: object.STRING_OBJECT}
+	case *ast.BooleanExpression:
+		return &Type{Kind: object.BOOLEAN_OBJECT}
+	case *ast.NilLiteral:
+		return &Type{Kind: object.NIL_OBJECT}
+
+	case *ast.ArrayLiteral:
+		elements := make([]*Type, len(expr.Elements))
+		for i, element := range expr.Elements {
+			elements[i] = c.typeOf(element, s)
+		}
+		return &Type{Kind: object.ARRAY_OBJECT, Element: join(elements)}
+
+	case *ast.HashLiteral:
+		keys := make([]*Type, 0, len(expr.Pairs))
+		values := make([]*Type, 0, len(expr.Pairs))
+		for key, value := range expr.Pairs {
+			keys = append(keys, c.typeOf(key, s))
+			values = append(values, c.typeOf(value, s))
+		}
+		return &Type{Kind: object.HASH_OBJECT, Key: join(keys), Element: join(values)}
+
+	case *ast.FunctionLiteral:
+		return functionType(expr.ParameterTypes, expr.ReturnType, expr.IsGenerator)
+
+	case *ast.Identifier:
+		if sym, ok := s.resolve(expr.Value); ok {
+			return c.symbolType(sym)
+		}
+
+	case *ast.UnaryExpression:
+		switch expr.Operator.Type {
+		case token.NOT:
+			return &Type{Kind: object.BOOLEAN_OBJECT}
+		case token.MINUS:
+			return numericType(c.typeOf(expr.Right, s), nil)
+		case token.TILDE:
+			right := c.typeOf(expr.Right, s)
+			return bitwiseType(right, right)
+		}
+
+	case *ast.BinaryExpression:
+		switch expr.Operator.Type {
+		case token.PLUS:
+			left, right := c.typeOf(expr.Left, s), c.typeOf(expr.Right, s)
+			if isKind(left, object.STRING_OBJECT) || isKind(right, object.STRING_OBJECT) {
+				return &Type{Kind: object.STRING_OBJECT}
+			}
+			if isNumeric(left) || isNumeric(right) {
+				return numericType(left, right)
+			}
+		case token.MINUS, token.STAR, token.PERCENT, token.FLOOR_DIVIDE:
+			return numericType(c.typeOf(expr.Left, s), c.typeOf(expr.Right, s))
+		case token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+			return bitwiseType(c.typeOf(expr.Left, s), c.typeOf(expr.Right, s))
+		case token.SLASH, token.CARET:
+			// 6 / 2 stays an integer but 7 / 2 doesn't, the same ---
+			// goes for BigInts, which become Decimals ---
+			left, right := c.typeOf(expr.Left, s), c.typeOf(expr.Right, s)
+			switch result := numericType(left, right); {
+			case result == nil, result.Kind == object.DECIMAL_OBJECT:
+				return result
+			case result.Kind != object.NUMBER_OBJECT:
+				return nil
+			}
+			return &Type{Kind: object.NUMBER_OBJECT}
+		default:
+			return &Type{Kind: object.BOOLEAN_OBJECT}
+		}
+
+	case *ast.TernaryExpression:
+		return join([]*Type{c.typeOf(expr.Consequence, s), c.typeOf(expr.Alternative, s)})
+
+	case *ast.IndexExpression:
+		if target := c.typeOf(expr.Target, s); target != nil {
+			switch target.Kind {
+			case object.ARRAY_OBJECT, object.HASH_OBJECT:
+				return target.Element
+			}
+		}
+
+	case *ast.IndexSliceExpression:
+		if target := c.typeOf(expr.Target, s); target != nil {
+			switch target.Kind {
+			case object.ARRAY_OBJECT, object.STRING_OBJECT:
+				return target
+			}
+		}
+
+	case *ast.CallExpression:
+		return c.callType(expr, s)
+	case *ast.SpawnExpression:
+		return &Type{Kind: object.TASK_OBJECT}
+
+	case *ast.AssignmentExpression:
+		return c.typeOf(ast.Assigned(expr.Token, expr.Operator, expr.Assignee, expr.NewValue), s)
+	case *ast.IndexAssignmentExpression:
+		element := &ast.IndexExpression{Token: expr.Token, Target: expr.Target, Index: expr.Index}
+		return c.typeOf(ast.Assigned(expr.Token, expr.Operator, element, expr.NewValue), s)
+	}
+
+	return nil
+}
+
+func isKind(t *Type, kind object.ObjectType) bool {
+	return t != nil && t.Kind == kind
+}
+
+func isNumeric(t *Type) bool {
+	return t != nil && exactRanks[t.Kind] > 0 || isKind(t, object.NUMBER_OBJECT)
+}
+
+// exactRanks orders the exact numbers, arithmetic on two of them ---
+// gives the higher one ---
+var exactRanks = map[object.ObjectType]int{
+	object.INTEGER_OBJECT: 1,
+	object.BIGINT_OBJECT:  2,
+	object.DECIMAL_OBJECT: 3,
+}
+
+// numericType is the type of arithmetic on left and right, integers ---
+// stay integers, BigInts and Decimals take over integers and anything ---
+// else is a number. right is nil for unary operators. It's nil when ---
+// a BigInt or a Decimal meets a float, which doesn't work ---
+func numericType(left, right *Type) *Type {
+	if right == nil {
+		right = left
+	}
+
+	l, r := 0, 0
+	if left != nil {
+		l = exactRanks[left.Kind]
+	}
+	if right != nil {
+		r = exactRanks[right.Kind]
+	}
+
+	switch {
+	case l > 0 && r > 0:
+		if l >= r {
+			return &Type{Kind: left.Kind}
+		}
+		return &Type{Kind: right.Kind}
+	case max(l, r) > 1:
+		// A float, or something unknown, next to a BigInt or a Decimal ---
+		return nil
+	}
+
+	return &Type{Kind: object.NUMBER_OBJECT}
+}
+
+// bitwiseType is the type of & | xor << >> and ~, booleans stay ---
+// booleans and a BigInt keeps the result one, anything else that ---
+// works is an integer ---
+func bitwiseType(left, right *Type) *Type {
+	switch {
+	case isKind(left, object.BOOLEAN_OBJECT) && isKind(right, object.BOOLEAN_OBJECT):
+		return &Type{Kind: object.BOOLEAN_OBJECT}
+	case isKind(left, object.BIGINT_OBJECT) || isKind(right, object.BIGINT_OBJECT):
+		return &Type{Kind: object.BIGINT_OBJECT}
+	case isBitwise(left) && isBitwise(right):
+		return &Type{Kind: object.INTEGER_OBJECT}
+	}
+
+	return nil
+}
+
+// isBitwise reports whether t has bits to operate on, floats may ---
+// be whole numbers ---
+func isBitwise(t *Type) bool {
+	return isKind(t, object.INTEGER_OBJECT) || isKind(t, object.BIGINT_OBJECT) || isKind(t, object.NUMBER_OBJECT)
+}
+
+func (c *checker) callType(node *ast.CallExpression, s *scope) *Type {
+	if ident, ok := node.Function.(*ast.Identifier); ok {
+		if _, shadowed := s.resolve(ident.Value); !shadowed {
+			if kind, ok := builtinReturns[ident.Value]; ok {
+				return &Type{Kind: kind}
+			}
+			return nil
+		}
+	}
+
+	callee := c.typeOf(node.Function, s)
+	if callee == nil || callee.Kind != object.FUNCTION_OBJECT {
+		return nil
+	}
+	if callee.Generator {
+		return &Type{Kind: object.GENERATOR_OBJECT}
+	}
+
+	return callee.Return
+}
+
+// mismatch finds the part of expr that doesn't fit expected, looking ---
+// inside array and hash literals to point at the offending element ---
+type mismatch struct {
+	node     ast.Expression
+	expected *Type
+	got      *Type
+}
+
+func (c *checker) findMismatch(expected *Type, expr ast.Expression, s *scope) *mismatch {
+	if expected != nil {
+		switch literal := expr.(type) {
+		case *ast.ArrayLiteral:
+			if expected.Kind == object.ARRAY_OBJECT {
+				for _, element := range literal.Elements {
+					if m := c.findMismatch(expected.Element, element, s); m != nil {
+						return m
+					}
+				}
+				return nil
+			}
+
+		case *ast.HashLiteral:
+			if expected.Kind == object.HASH_OBJECT {
+				for _, key := range ast.SortedKeys(literal) {
+					if m := c.findMismatch(expected.Key, key, s); m != nil {
+						return m
+					}
+					if m := c.findMismatch(expected.Element, literal.Pairs[key], s); m != nil {
+						return m
+					}
+				}
+				return nil
+			}
+		}
+	}
+
+	if got := c.typeOf(expr, s); !assignable(expected, got) {
+		return &mismatch{node: expr, expected: expected, got: got}
+	}
+
+	return nil
+}
+
+// expect reports expr when it doesn't fit expected, what describes ---
+// where the value goes ---
+func (c *checker) expect(expected *Type, expr ast.Expression, s *scope, what string) {
+	m := c.findMismatch(expected, expr, s)
+	if m == nil {
+		return
+	}
+
+	if m.expected == expected {
+		c.report(Types, m.node, "%s expects %s, got %s", what, expected, m.got)
+	} else {
+		c.report(Types, m.node, "%s expects %s, found %s where %s belongs", what, expected, m.got, m.expected)
+	}
+}
+
+// [ CHECKS ] ---
+
+func (c *checker) checkAssignment(ident *ast.Identifier, value ast.Expression, s *scope) {
+	if sym, ok := s.resolve(ident.Value); ok && sym.declared != nil {
+		c.expect(sym.declared, value, s, "Variable '"+ident.Value+"'")
+	}
+}
+
+func (c *checker) checkReturn(stmt *ast.ReturnStatement, s *scope) {
+	if s.returns == nil {
+		return
+	}
+
+	if stmt.ReturnValue == nil {
+		if !assignable(s.returns, &Type{Kind: object.NIL_OBJECT}) {
+			c.report(Types, stmt, "Function returns %s, got an empty return", s.returns)
+		}
+		return
+	}
+
+	c.expect(s.returns, stmt.ReturnValue, s, "Return value")
+}
+
+func (c *checker) checkCall(node *ast.CallExpression, s *scope) {
+	if ident, ok := node.Function.(*ast.Identifier); ok {
+		if _, declared := s.resolve(ident.Value); !declared {
+			return
+		}
+	}
+
+	callee := c.typeOf(node.Function, s)
+	if callee == nil || callee.Kind != object.FUNCTION_OBJECT || !callee.Signature {
+		return
+	}
+
+	for i, arg := range node.Arguments {
+		if i < len(callee.Params) {
+			c.expect(callee.Params[i], arg, s, fmt.Sprintf("Argument %d of '%s'", i+1, node.Function))
+		}
+	}
+}
+
+func (c *checker) checkUnary(node *ast.UnaryExpression, s *scope) {
+	right := c.typeOf(node.Right, s)
+	if node.Operator.Type == token.MINUS && right != nil && !isNumeric(right) {
+		c.report(Types, node, "Cannot negate a value of type %s", right)
+	}
+	if node.Operator.Type == token.TILDE && right != nil && !isBitwise(right) {
+		c.report(Types, node, "Cannot invert the bits of a value of type %s", right)
+	}
+}
+
+func (c *checker) checkBinary(node *ast.BinaryExpression, s *scope) {
+	switch node.Operator.Type {
+	case token.AND, token.OR:
+		return
+	}
+
+	left, right := c.typeOf(node.Left, s), c.typeOf(node.Right, s)
+	if left == nil || right == nil {
+		return
+	}
+
+	valid := false
+	switch {
+	case isBitwiseOperator(node.Operator.Type):
+		shift := node.Operator.Type == token.SHIFT_LEFT || node.Operator.Type == token.SHIFT_RIGHT
+		booleans := isKind(left, object.BOOLEAN_OBJECT) && isKind(right, object.BOOLEAN_OBJECT)
+		valid = (isBitwise(left) && isBitwise(right)) || (booleans && !shift)
+	case isNumeric(left) && isNumeric(right):
+		// Any two numbers compare, exactly ---
+		valid = numericType(left, right) != nil || isComparison(node.Operator.Type)
+	case left.Kind == object.STRING_OBJECT && right.Kind == object.STRING_OBJECT:
+		valid = node.Operator.Type == token.PLUS
+	case left.Kind == object.BOOLEAN_OBJECT && right.Kind == object.BOOLEAN_OBJECT:
+		valid = node.Operator.Type == token.EQUAL || node.Operator.Type == token.NOT_EQUAL
+	}
+
+	if !valid {
+		c.report(Types, node, "Cannot perform `%s %s %s`", left.Kind, node.Operator.Literal, right.Kind)
+	}
+}
+
+func (c *checker) checkIndex(node ast.Node, target, index ast.Expression, s *scope) *Type {
+	targetType := c.typeOf(target, s)
+	if targetType == nil {
+		return nil
+	}
+
+	switch targetType.Kind {
+	case object.ARRAY_OBJECT:
+		c.expect(&Type{Kind: object.NUMBER_OBJECT}, index, s, "Array index")
+	case object.HASH_OBJECT:
+		c.expect(targetType.Key, index, s, "Hash key")
+	default:
+		c.report(Types, node, "Cannot index a value of type %s", targetType)
+		return nil
+	}
+
+	return targetType.Element
+}
+
+func (c *checker) checkSlice(node *ast.IndexSliceExpression, s *scope) {
+	target := c.typeOf(node.Target, s)
+	if target != nil && target.Kind != object.ARRAY_OBJECT && target.Kind != object.STRING_OBJECT {
+		c.report(Types, node.Target, "Cannot slice a value of type %s", target)
+	}
+
+	for _, bound := range []ast.Expression{node.Start, node.End} {
+		if bound != nil {
+			c.expect(&Type{Kind: object.NUMBER_OBJECT}, bound, s, "Slice bound")
+		}
+	}
+}
+
+func (c *checker) checkIterable(node *ast.ForInStatement, s *scope) {
+	iterable := c.typeOf(node.Iterable, s)
+	if iterable == nil {
+		return
+	}
+
+	switch iterable.Kind {
+	case object.ARRAY_OBJECT, object.STRING_OBJECT, object.GENERATOR_OBJECT, object.CHANNEL_OBJECT:
+		return
+	}
+
+	c.report(Types, node.Iterable, "Cannot iterate over a value of type %s", iterable)
+}
+
+func isBitwiseOperator(operator token.TokenType) bool {
+	switch operator {
+	case token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		return true
+	}
+
+	return false
+}
+
+func isComparison(operator token.TokenType) bool {
+	switch operator {
+	case token.EQUAL, token.NOT_EQUAL, token.LESS, token.GREATER, token.LESS_EQUAL, token.GREATER_EQUAL:
+		return true
+	}
+
+	return false
+}
@@ -42,8 +42,99 @@ const (
 	OpGetGlobal
 
 	OpPop
+
+	OpHash
+	OpIndex
+	OpSetIndex
+
+	OpCall
+	OpReturnValue
+	OpReturn
+
+	OpGetLocal
+	OpSetLocal
+	OpGetBuiltin
+	OpClosure
+	OpGetFree
+	OpCurrentClosure
+
+	OpSpawn
+	OpAwait
+
+	OpYield
+	OpIter
+	OpIterNext
+
+	OpThrow
+
+	OpConcat
+
+	// Floored division and the bitwise operators ---
+	OpModulo
+	OpFloorDivide
+	OpBitAnd
+	OpBitOr
+	OpBitXor
+	OpShiftLeft
+	OpShiftRight
+	OpBitNot
+
+	OpPick
+	OpBury
+
+	// Destructuring, the value unpacked stays on the stack until ---
+	// its pattern is done ---
+	OpUnpackArray
+	OpUnpackElement
+	OpUnpackHash
+	OpUnpackKey
+	OpJumpNotNil
+
+	// Captured variables, shared through an object.Cell ---
+	OpCaptureLocal
+	OpCaptureFree
+	OpSetFree
 )
 
+// Handler protects the instructions [Start, End) of one function, ---
+// an error raised there resumes at Target with the error pushed ---
+type Handler struct {
+	Start  int
+	End    int
+	Target int
+
+	// Values (for ... in iterators) kept below the handler's stack ---
+	StackDepth int
+
+	// Where fatal errors (that catch can't see) resume instead, the ---
+	// try's finally handler, or 0 when it has none ---
+	Finally int
+}
+
+// SourcePos maps the instructions from Offset up to the next entry ---
+// back to the source position they were compiled from ---
+type SourcePos struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// LineAt finds the source position of the instruction at offset, ---
+// lines must be sorted by Offset (the compiler emits them in order) ---
+func LineAt(lines []SourcePos, offset int) (int, int) {
+	line, column := 0, 0
+
+	for _, pos := range lines {
+		if pos.Offset > offset {
+			break
+		}
+
+		line, column = pos.Line, pos.Column
+	}
+
+	return line, column
+}
+
 type Definition struct {
 	Name          string
 	OperandWidths []int
@@ -75,6 +166,54 @@ var definitions = map[OpCode]*Definition{
 	OpDivide:        {"OpDivide", []int{}},
 	OpExponent:      {"OpExponent", []int{}},
 	OpPop:           {"OpPop", []int{}},
+
+	OpHash:     {"OpHash", []int{2}},
+	OpIndex:    {"OpIndex", []int{}},
+	OpSetIndex: {"OpSetIndex", []int{}},
+
+	OpCall:        {"OpCall", []int{1}},
+	OpReturnValue: {"OpReturnValue", []int{}},
+	OpReturn:      {"OpReturn", []int{}},
+
+	OpGetLocal:       {"OpGetLocal", []int{1}},
+	OpSetLocal:       {"OpSetLocal", []int{1}},
+	OpGetBuiltin:     {"OpGetBuiltin", []int{1}},
+	OpClosure:        {"OpClosure", []int{2, 1}},
+	OpGetFree:        {"OpGetFree", []int{1}},
+	OpCurrentClosure: {"OpCurrentClosure", []int{}},
+
+	OpSpawn: {"OpSpawn", []int{1}},
+	OpAwait: {"OpAwait", []int{}},
+
+	OpYield:    {"OpYield", []int{}},
+	OpIter:     {"OpIter", []int{}},
+	OpIterNext: {"OpIterNext", []int{2}}, // Jumps to the operand once exhausted ---
+
+	OpThrow: {"OpThrow", []int{}},
+
+	OpConcat: {"OpConcat", []int{2}}, // Joins the operand's count of values the way print shows them ---
+
+	OpModulo:      {"OpModulo", []int{}},
+	OpFloorDivide: {"OpFloorDivide", []int{}},
+	OpBitAnd:      {"OpBitAnd", []int{}},
+	OpBitOr:       {"OpBitOr", []int{}},
+	OpBitXor:      {"OpBitXor", []int{}},
+	OpShiftLeft:   {"OpShiftLeft", []int{}},
+	OpShiftRight:  {"OpShiftRight", []int{}},
+	OpBitNot:      {"OpBitNot", []int{}},
+
+	OpPick: {"OpPick", []int{1}}, // Pushes the value the operand's count below the top again ---
+	OpBury: {"OpBury", []int{1}}, // Moves the top value the operand's count of values down ---
+
+	OpUnpackArray:   {"OpUnpackArray", []int{2, 2, 1}}, // Checks the array fits: required, count, has rest ---
+	OpUnpackElement: {"OpUnpackElement", []int{2, 1}},  // Pushes the element at the index, or the rest from it ---
+	OpUnpackHash:    {"OpUnpackHash", []int{}},
+	OpUnpackKey:     {"OpUnpackKey", []int{2, 1}}, // Pushes the value of the key constant, has default ---
+	OpJumpNotNil:    {"OpJumpNotNil", []int{2}},   // Keeps the top and jumps unless it's nil, pops it otherwise ---
+
+	OpCaptureLocal: {"OpCaptureLocal", []int{1}}, // Boxes the local into a cell (once) and pushes the cell ---
+	OpCaptureFree:  {"OpCaptureFree", []int{1}},  // Pushes the free variable's cell itself ---
+	OpSetFree:      {"OpSetFree", []int{1}},
 }
 
 func Lookup(opcode OpCode) (*Definition, error) {
@@ -95,6 +234,7 @@ func (ins Instructions) String() string {
 		def, err := Lookup(OpCode(ins[i]))
 		if err != nil {
 			fmt.Fprintf(&out, "ERROR: %s\n", err)
+			i++
 			continue
 		}
 
@@ -119,6 +259,8 @@ func (ins Instructions) fmtInstruction(def *Definition, operands []int) string {
 		return fmt.Sprintf("%s\n", def.Name)
 	case 1:
 		return fmt.Sprintf("%s %d\n", def.Name, operands[0])
+	case 2:
+		return fmt.Sprintf("%s %d %d\n", def.Name, operands[0], operands[1])
 	}
 
 	return fmt.Sprintf("ERROR: unhandled operandCount for %s\n", def.Name)
@@ -148,6 +290,8 @@ func Make(opcode OpCode, operands ...int) []byte {
 		switch width {
 		case 2:
 			binary.BigEndian.PutUint16(instruction[offset:], uint16(operand))
+		case 1:
+			instruction[offset] = byte(operand)
 		}
 
 		// Advance offset based on current width
@@ -165,6 +309,8 @@ func ReadOperands(def *Definition, ins Instructions) ([]int, int) {
 		switch width {
 		case 2:
 			operands[i] = int(ReadUint16(ins[offset:]))
+		case 1:
+			operands[i] = int(ReadUint8(ins[offset:]))
 		}
 
 		offset += width
@@ -176,3 +322,7 @@ func ReadOperands(def *Definition, ins Instructions) ([]int, int) {
 func ReadUint16(ins Instructions) uint16 {
 	return binary.BigEndian.Uint16(ins)
 }
+
+func ReadUint8(ins Instructions) uint8 {
+	return uint8(ins[0])
+}
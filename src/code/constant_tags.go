@@ -3,6 +3,11 @@ package code
 type Tag byte
 
 const (
-	CONSTANT_NUMBER Tag = 1
-	CONSTANT_STRING Tag = 2
+	CONSTANT_NUMBER   Tag = 1
+	CONSTANT_STRING   Tag = 2
+	CONSTANT_FUNCTION Tag = 3
+	CONSTANT_QUOTE    Tag = 4 // Stored as source, parsed again when loaded ---
+	CONSTANT_INTEGER  Tag = 5
+	CONSTANT_BIGINT   Tag = 6 // Stored as decimal digits ---
+	CONSTANT_DECIMAL  Tag = 7 // Unscaled digits, then the scale ---
 )
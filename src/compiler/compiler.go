@@ -14,41 +14,102 @@ type EmittedInstruction struct {
 	Position int
 }
 
-type Compiler struct {
+type CompilationScope struct {
 	instructions code.Instructions // []byte
-	constants    []object.Object
-	symbolTable  *SymbolTable
 
 	lastInstruction     EmittedInstruction
 	previousInstruction EmittedInstruction
+
+	handlers  []code.Handler
+	tries     []*tryContext // Enclosing try statements, innermost last ---
+	loopDepth int           // Live for ... in iterators on the stack ---
+
+	lines []code.SourcePos
+}
+
+type Compiler struct {
+	constants   []object.Object
+	symbolTable *SymbolTable
+
+	scopes     []CompilationScope
+	scopeIndex int
+
+	// Source position of the node being compiled ---
+	line   int
+	column int
+
+	// Recorded in stack traces ---
+	File string
+
+	// Kept for debuggers, see FunctionSymbols ---
+	functionSymbols map[*object.CompiledFunction]*SymbolTable
+
+	// Function declarations whose name hoistFunctions defined ---
+	hoisted map[*ast.FunctionDeclarationStatement]Symbol
 }
 
 type Bytecode struct {
 	Instructions code.Instructions // []byte
 	Constants    []object.Object
+	Handlers     []code.Handler // Of the top-level code ---
+	Lines        []code.SourcePos
+	File         string
 }
 
 func New() *Compiler {
-	return &Compiler{
-		instructions: make(code.Instructions, 0),
-		constants:    make([]object.Object, 0),
-		symbolTable:  NewSymbolTable(),
-
+	mainScope := CompilationScope{
+		instructions:        make(code.Instructions, 0),
 		lastInstruction:     EmittedInstruction{},
 		previousInstruction: EmittedInstruction{},
 	}
+
+	symbolTable := NewSymbolTable()
+	defineBuiltins(symbolTable)
+
+	return &Compiler{
+		constants:   make([]object.Object, 0),
+		symbolTable: symbolTable,
+
+		scopes:     []CompilationScope{mainScope},
+		scopeIndex: 0,
+
+		functionSymbols: make(map[*object.CompiledFunction]*SymbolTable),
+		hoisted:         make(map[*ast.FunctionDeclarationStatement]Symbol),
+	}
 }
 
 func NewWithState(table *SymbolTable, constants []object.Object) *Compiler {
 	compiler := New()
+	defineBuiltins(table)
 	compiler.symbolTable = table
 	compiler.constants = constants
 	return compiler
 }
 
-func (c *Compiler) Compile(node ast.Node) error {
+func defineBuiltins(table *SymbolTable) {
+	for i, name := range object.BuiltinNames {
+		table.DefineBuiltin(i, name)
+	}
+}
+
+func (c *Compiler) Compile(node ast.Node) (err error) {
+	// Instructions are attributed to the innermost node emitting them ---
+	if node.GetLine() != 0 {
+		prevLine, prevColumn := c.line, c.column
+		c.line, c.column = int(node.GetLine()), int(node.GetColumn())
+		defer func() {
+			err = c.positionError(err)
+			c.line, c.column = prevLine, prevColumn
+		}()
+	}
+
 	switch node := node.(type) {
 	case *ast.Program:
+		err := c.hoistFunctions(node.Statements)
+		if err != nil {
+			return err
+		}
+
 		for _, stmt := range node.Statements {
 			err := c.Compile(stmt)
 			if err != nil {
@@ -93,39 +154,24 @@ func (c *Compiler) Compile(node ast.Node) error {
 			return rightErr
 		}
 
-		switch node.Operator.Type {
-		case token.CARET:
-			c.emit(code.OpExponent)
-		case token.PLUS:
-			c.emit(code.OpAdd)
-		case token.MINUS:
-			c.emit(code.OpSubtract)
-		case token.STAR:
-			c.emit(code.OpMultiply)
-		case token.SLASH:
-			c.emit(code.OpDivide)
-
-		case token.EQUAL:
-			c.emit(code.OpEqual)
-		case token.NOT_EQUAL:
-			c.emit(code.OpNotEqual)
-		case token.LESS:
-			c.emit(code.OpLess)
-		case token.GREATER:
-			c.emit(code.OpGreater)
-		case token.LESS_EQUAL:
-			c.emit(code.OpLessEqual)
-		case token.GREATER_EQUAL:
-			c.emit(code.OpGreaterEqual)
-
-		default:
-			return fmt.Errorf("Unknown binary operator token: '%s'", node.Operator.Type)
-		}
+		return c.emitOperator(node.Operator)
 
 	case *ast.NumberLiteral:
 		num := &object.Number{Value: node.Value}
 		c.emit(code.OpConstant, c.addConstant(num))
 
+	case *ast.IntegerLiteral:
+		integer := &object.Integer{Value: node.Value}
+		c.emit(code.OpConstant, c.addConstant(integer))
+
+	case *ast.BigIntLiteral:
+		bigint := &object.BigInt{Value: node.Value}
+		c.emit(code.OpConstant, c.addConstant(bigint))
+
+	case *ast.DecimalLiteral:
+		decimal := &object.Decimal{Unscaled: node.Unscaled, Scale: node.Scale}
+		c.emit(code.OpConstant, c.addConstant(decimal))
+
 	case *ast.AbsoluteExpression:
 		err := c.Compile(node.Value)
 		if err != nil {
@@ -145,6 +191,8 @@ func (c *Compiler) Compile(node ast.Node) error {
 			c.emit(code.OpNot)
 		case token.MINUS:
 			c.emit(code.OpNegate)
+		case token.TILDE:
+			c.emit(code.OpBitNot)
 		default:
 			return fmt.Errorf("Unknown unary operator token: '%s'", node.Operator.Type)
 		}
@@ -166,23 +214,21 @@ func (c *Compiler) Compile(node ast.Node) error {
 		// Emit with some bogus value
 		jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
 
+		// NOTE: If is a statement, so both branches keep their ---
+		// OpPop and nothing is left behind on the stack ---
 		err = c.Compile(node.Consequence)
 		if err != nil {
 			return err
 		}
 
-		if c.lastInstructionIsPop() {
-			c.removeLastPop()
-		}
-
 		if node.Alternative == nil {
 			// Reassign jump pos to the end of if stmt address
-			posAfterConsequence := len(c.instructions)
+			posAfterConsequence := len(c.currentInstructions())
 			c.changeOperand(jumpNotTruthyPos, posAfterConsequence)
 		} else {
 			// Emit with some bogus value
 			jumpPos := c.emit(code.OpJump, 9999)
-			posAfterConsequence := len(c.instructions)
+			posAfterConsequence := len(c.currentInstructions())
 			c.changeOperand(jumpNotTruthyPos, posAfterConsequence)
 
 			err := c.Compile(node.Alternative)
@@ -190,11 +236,7 @@ func (c *Compiler) Compile(node ast.Node) error {
 				return err
 			}
 
-			if c.lastInstructionIsPop() {
-				c.removeLastPop()
-			}
-
-			posAfterAlternative := len(c.instructions)
+			posAfterAlternative := len(c.currentInstructions())
 			c.changeOperand(jumpPos, posAfterAlternative)
 		}
 
@@ -218,7 +260,7 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		// Emit with bogus value / placeholder ---
 		jumpPos := c.emit(code.OpJump, 9999)
-		posAfterConsequence := len(c.instructions)
+		posAfterConsequence := len(c.currentInstructions())
 
 		// Set end of jumpNotTruthyPos to "jump pos"
 		// But we're not directly using jumpPos
@@ -233,16 +275,35 @@ func (c *Compiler) Compile(node ast.Node) error {
 			c.removeLastPop()
 		}
 
-		posAfterAlternative := len(c.instructions)
+		posAfterAlternative := len(c.currentInstructions())
 		c.changeOperand(jumpPos, posAfterAlternative)
 
 	case *ast.VarStatement:
+		if node.Pattern != nil {
+			err := checkDuplicates(ast.PatternNames(node.Pattern))
+			if err != nil {
+				return err
+			}
+
+			err = c.Compile(node.Value)
+			if err != nil {
+				return err
+			}
+
+			return c.destructure(node.Pattern, c.declare)
+		}
+
 		for _, name := range node.Names {
 			// TODO: This operation is lowkey expensive
 			// maybe optimize this? this probably doesnt affect the
 			// runtime that much, but its better to point this one out
 			// ... Maybe recompiling is the only option???
-			err := c.Compile(node.Value)
+			var err error
+			if fnLit, ok := node.Value.(*ast.FunctionLiteral); ok {
+				err = c.compileFunction(name.Value, fnLit.Parameters, fnLit.Patterns, fnLit.Body, fnLit.IsGenerator)
+			} else {
+				err = c.Compile(node.Value)
+			}
 			if err != nil {
 				return err
 			}
@@ -252,7 +313,7 @@ func (c *Compiler) Compile(node ast.Node) error {
 				return fmt.Errorf("Cannot redeclare already existing variable '%s'", name.Value)
 			}
 
-			c.emit(code.OpSetGlobal, symbol.Index)
+			c.storeSymbol(symbol)
 		}
 
 	case *ast.Identifier:
@@ -261,7 +322,280 @@ func (c *Compiler) Compile(node ast.Node) error {
 			return fmt.Errorf("Cannot resolve variable '%s'", node.Value)
 		}
 
-		c.emit(code.OpGetGlobal, symbol.Index)
+		c.loadSymbol(symbol)
+
+	case *ast.AssignmentExpression:
+		symbol, exists := c.symbolTable.Resolve(node.Assignee.TokenLiteral())
+		if !exists {
+			return fmt.Errorf("Assignment to an undefined variable '%s'", node.Assignee.TokenLiteral())
+		}
+
+		// x += v is x = x + v, x is read before v ---
+		if node.Operator.Type != "" {
+			c.loadSymbol(symbol)
+		}
+
+		// x++ is postfix, a copy of the value before stays behind ---
+		postfix := ast.IsIncrement(node.Token.Type)
+		if postfix {
+			c.emit(code.OpPick, 0)
+		}
+
+		err := c.Compile(node.NewValue)
+		if err != nil {
+			return err
+		}
+
+		if node.Operator.Type != "" {
+			err = c.emitOperator(node.Operator)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = c.checkAssignable(symbol)
+		if err != nil {
+			return err
+		}
+
+		// Assignment is an expression, so the new value is loaded back ---
+		c.storeSymbol(symbol)
+		if !postfix {
+			c.loadSymbol(symbol)
+		}
+
+	case *ast.BatchAssignmentStatement:
+		if node.Pattern != nil {
+			err := c.Compile(node.NewValue)
+			if err != nil {
+				return err
+			}
+
+			return c.destructure(node.Pattern, c.assign)
+		}
+
+		symbols := make([]Symbol, len(node.Assignees))
+		for i, assignee := range node.Assignees {
+			symbol, exists := c.symbolTable.Resolve(assignee.Value)
+			if !exists {
+				return fmt.Errorf("Cannot resolve variable '%s'", assignee.Value)
+			}
+
+			err := c.checkAssignable(symbol)
+			if err != nil {
+				return err
+			}
+			symbols[i] = symbol
+		}
+
+		err := c.Compile(node.NewValue)
+		if err != nil {
+			return err
+		}
+
+		// The value stays under each new one, which are all worked ---
+		// out before the first is stored ---
+		for i, symbol := range symbols {
+			if node.Operator.Type == "" {
+				c.emit(code.OpPick, i)
+				continue
+			}
+
+			c.loadSymbol(symbol)
+			c.emit(code.OpPick, i+1)
+			err = c.emitOperator(node.Operator)
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := len(symbols) - 1; i >= 0; i-- {
+			c.storeSymbol(symbols[i])
+		}
+		c.emit(code.OpPop)
+
+	case *ast.FunctionLiteral:
+		return c.compileFunction("", node.Parameters, node.Patterns, node.Body, node.IsGenerator)
+
+	case *ast.MacroLiteral:
+		return fmt.Errorf("Macros can only be defined at the top level, with `var <name> = macro(...) { ... };`")
+
+	case *ast.FunctionDeclarationStatement:
+		// Defined first (or hoisted), so the body can call itself ---
+		symbol, hoisted := c.hoisted[node]
+		if !hoisted {
+			var exists bool
+			symbol, exists = c.symbolTable.Define(node.Name.Value)
+			if exists {
+				return fmt.Errorf("Cannot redeclare already existing variable '%s'", node.Name.Value)
+			}
+		}
+
+		err := c.compileFunction(node.Name.Value, node.Parameters, node.Patterns, node.Body, node.IsGenerator)
+		if err != nil {
+			return err
+		}
+
+		c.storeSymbol(symbol)
+
+	case *ast.ReturnStatement:
+		if node.ReturnValue == nil {
+			c.emit(code.OpNil)
+		} else {
+			err := c.Compile(node.ReturnValue)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Pending finally blocks run before leaving the function ---
+		err := c.compilePendingFinally()
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpReturnValue)
+		c.resumeTries()
+
+	case *ast.TryStatement:
+		return c.compileTry(node)
+
+	case *ast.ThrowStatement:
+		err := c.Compile(node.Value)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpThrow)
+
+	case *ast.CallExpression:
+		if isCallTo(node, "quote") {
+			return c.compileQuote(node)
+		}
+
+		err := c.Compile(node.Function)
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range node.Arguments {
+			err := c.Compile(arg)
+			if err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpCall, len(node.Arguments))
+
+	case *ast.SpawnExpression:
+		err := c.Compile(node.Call.Function)
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range node.Call.Arguments {
+			err := c.Compile(arg)
+			if err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpSpawn, len(node.Call.Arguments))
+
+	case *ast.YieldExpression:
+		if node.Value == nil {
+			c.emit(code.OpNil)
+		} else {
+			err := c.Compile(node.Value)
+			if err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpYield)
+
+	case *ast.ForInStatement:
+		return c.compileForIn(node)
+
+	case *ast.AwaitExpression:
+		err := c.Compile(node.Value)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpAwait)
+
+	case *ast.IndexExpression:
+		err := c.Compile(node.Target)
+		if err != nil {
+			return err
+		}
+
+		err = c.Compile(node.Index)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpIndex)
+
+	case *ast.IndexAssignmentExpression:
+		err := c.Compile(node.Target)
+		if err != nil {
+			return err
+		}
+
+		err = c.Compile(node.Index)
+		if err != nil {
+			return err
+		}
+
+		// a[i] += v copies a and i to read a[i] before v, so both ---
+		// are evaluated once ---
+		if node.Operator.Type != "" {
+			c.emit(code.OpPick, 1)
+			c.emit(code.OpPick, 1)
+			c.emit(code.OpIndex)
+		}
+
+		// a[i]++ is postfix, a copy of a[i] goes under a and i ---
+		postfix := ast.IsIncrement(node.Token.Type)
+		if postfix {
+			c.emit(code.OpPick, 0)
+			c.emit(code.OpBury, 3)
+		}
+
+		err = c.Compile(node.NewValue)
+		if err != nil {
+			return err
+		}
+
+		if node.Operator.Type != "" {
+			err = c.emitOperator(node.Operator)
+			if err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpSetIndex)
+		if postfix {
+			c.emit(code.OpPop)
+		}
+
+	case *ast.HashLiteral:
+		// In source order like the evaluator, so a repeated key's last value wins ---
+		for _, key := range ast.SortedKeys(node) {
+			err := c.Compile(key)
+			if err != nil {
+				return err
+			}
+
+			err = c.Compile(node.Pairs[key])
+			if err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpHash, len(node.Pairs)*2)
 
 	case *ast.IndexSliceExpression:
 		err := c.Compile(node.Target)
@@ -299,6 +633,22 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		c.emit(code.OpArray, len(node.Elements))
 
+	case *ast.InterpolatedString:
+		count := 0
+		for _, part := range node.Parts {
+			if text, ok := part.(*ast.StringLiteral); ok && text.Value == "" {
+				continue
+			}
+
+			err := c.Compile(part)
+			if err != nil {
+				return err
+			}
+			count++
+		}
+
+		c.emit(code.OpConcat, count)
+
 	default:
 		return fmt.Errorf("Unknown AST node: '%s' (%T)", node.String(), node)
 	}
@@ -328,6 +678,9 @@ func (c *Compiler) Disassemble() {
 			case 2:
 				operands[j] = int(code.ReadUint16(instructions[i:]))
 				i += 2
+			case 1:
+				operands[j] = int(code.ReadUint8(instructions[i:]))
+				i += 1
 			default:
 				panic("Unsupported operand width")
 			}
@@ -343,8 +696,14 @@ func (c *Compiler) Disassemble() {
 		switch v := c.(type) {
 		case *object.Number:
 			fmt.Printf("%d: %g\n", idx, v.Value)
+		case *object.Integer:
+			fmt.Printf("%d: %d\n", idx, v.Value)
+		case *object.BigInt, *object.Decimal:
+			fmt.Printf("%d: %s\n", idx, v.Inspect())
 		case *object.String:
 			fmt.Printf("%d: \"%s\"\n", idx, v.Value)
+		case *object.CompiledFunction:
+			fmt.Printf("%d: %s\n", idx, v.Inspect())
 
 		default:
 			fmt.Printf("%d: unknown constant type %T\n", idx, c)
@@ -358,43 +717,91 @@ func (c *Compiler) emit(opcode code.OpCode, operands ...int) int {
 	position := c.addInstruction(instruction)
 
 	c.setLastInstruction(opcode, position)
+	c.addSourcePos(position)
 	return position
 }
 
+func (c *Compiler) currentInstructions() code.Instructions {
+	return c.scopes[c.scopeIndex].instructions
+}
+
 func (c *Compiler) setLastInstruction(opcode code.OpCode, position int) {
 	// Shifts instructions
-	previous := c.lastInstruction
+	previous := c.scopes[c.scopeIndex].lastInstruction
 	last := EmittedInstruction{OpCode: opcode, Position: position}
 
-	c.previousInstruction = previous
-	c.lastInstruction = last
+	c.scopes[c.scopeIndex].previousInstruction = previous
+	c.scopes[c.scopeIndex].lastInstruction = last
 }
 
-func (c *Compiler) changeOperand(opPos int, operand int) {
+func (c *Compiler) changeOperand(opPos int, operands ...int) {
 	// Get opcode on given position
-	opcode := code.OpCode(c.instructions[opPos])
+	opcode := code.OpCode(c.currentInstructions()[opPos])
 
 	// Attach an operand to the opcode
-	newInstruction := code.Make(opcode, operand)
+	newInstruction := code.Make(opcode, operands...)
 
 	c.replaceInstruction(opPos, newInstruction)
 }
 
 func (c *Compiler) replaceInstruction(position int, newInstruction []byte) {
+	ins := c.currentInstructions()
+
 	for i := 0; i < len(newInstruction); i++ {
 		// Replaces all instruction bytes in the given offset
-		c.instructions[position+i] = newInstruction[i]
+		ins[position+i] = newInstruction[i]
+	}
+}
+
+func (c *Compiler) lastInstructionIs(opcode code.OpCode) bool {
+	if len(c.currentInstructions()) == 0 {
+		return false
 	}
+
+	return c.scopes[c.scopeIndex].lastInstruction.OpCode == opcode
 }
 
 func (c *Compiler) lastInstructionIsPop() bool {
-	return c.lastInstruction.OpCode == code.OpPop
+	return c.lastInstructionIs(code.OpPop)
 }
 
 func (c *Compiler) removeLastPop() {
+	last := c.scopes[c.scopeIndex].lastInstruction
+	previous := c.scopes[c.scopeIndex].previousInstruction
+
 	// resets the instructions up until the last instruction position
-	c.instructions = c.instructions[:c.lastInstruction.Position]
-	c.lastInstruction = c.previousInstruction
+	c.scopes[c.scopeIndex].instructions = c.currentInstructions()[:last.Position]
+	c.scopes[c.scopeIndex].lastInstruction = previous
+	c.trimSourcePos(last.Position)
+}
+
+func (c *Compiler) addSourcePos(position int) {
+	scope := &c.scopes[c.scopeIndex]
+
+	if n := len(scope.lines); n > 0 {
+		last := scope.lines[n-1]
+		if last.Line == c.line && last.Column == c.column {
+			return
+		}
+	}
+
+	scope.lines = append(scope.lines, code.SourcePos{Offset: position, Line: c.line, Column: c.column})
+}
+
+// Drops the positions of instructions removed from the end ---
+func (c *Compiler) trimSourcePos(length int) {
+	scope := &c.scopes[c.scopeIndex]
+
+	for len(scope.lines) > 0 && scope.lines[len(scope.lines)-1].Offset >= length {
+		scope.lines = scope.lines[:len(scope.lines)-1]
+	}
+}
+
+func (c *Compiler) replaceLastPopWithReturn() {
+	lastPos := c.scopes[c.scopeIndex].lastInstruction.Position
+	c.replaceInstruction(lastPos, code.Make(code.OpReturnValue))
+
+	c.scopes[c.scopeIndex].lastInstruction.OpCode = code.OpReturnValue
 }
 
 func (c *Compiler) addConstant(obj object.Object) int {
@@ -403,14 +810,297 @@ func (c *Compiler) addConstant(obj object.Object) int {
 }
 
 func (c *Compiler) addInstruction(ins []byte) int {
-	insPos := len(c.instructions)
-	c.instructions = append(c.instructions, ins...)
+	insPos := len(c.currentInstructions())
+	c.scopes[c.scopeIndex].instructions = append(c.currentInstructions(), ins...)
 	return insPos // Return instruction "address"
 }
 
+func (c *Compiler) enterScope() {
+	scope := CompilationScope{
+		instructions:        make(code.Instructions, 0),
+		lastInstruction:     EmittedInstruction{},
+		previousInstruction: EmittedInstruction{},
+	}
+
+	c.scopes = append(c.scopes, scope)
+	c.scopeIndex++
+
+	c.symbolTable = NewEnclosedSymbolTable(c.symbolTable)
+}
+
+func (c *Compiler) leaveScope() code.Instructions {
+	instructions := c.currentInstructions()
+
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.scopeIndex--
+
+	c.symbolTable = c.symbolTable.Outer
+	return instructions
+}
+
+func (c *Compiler) compileFunction(name string, parameters []*ast.Identifier, patterns []ast.Expression, body *ast.BlockStatement, isGenerator bool) error {
+	err := checkDuplicates(ast.ParameterNames(parameters, patterns))
+	if err != nil {
+		return err
+	}
+
+	c.enterScope()
+
+	if name != "" {
+		c.symbolTable.DefineFunctionName(name)
+	}
+
+	params := make([]Symbol, len(parameters))
+	for i, param := range parameters {
+		params[i], _ = c.symbolTable.Define(param.Value)
+	}
+
+	// Pattern parameters are unpacked before the body runs ---
+	for i, pattern := range patterns {
+		if pattern == nil {
+			continue
+		}
+
+		c.loadSymbol(params[i])
+		err := c.destructure(pattern, c.declare)
+		if err != nil {
+			c.leaveScope()
+			return err
+		}
+	}
+
+	err = c.hoistFunctions(body.Statements)
+	if err == nil {
+		err = c.Compile(body)
+	}
+	if err != nil {
+		c.leaveScope()
+		return err
+	}
+
+	// Implicit return of a trailing expression, like the evaluator ---
+	// anything else falls through to a bare OpReturn (nil) ---
+	var lastStmt ast.Statement
+	if len(body.Statements) > 0 {
+		lastStmt = body.Statements[len(body.Statements)-1]
+	}
+
+	switch lastStmt.(type) {
+	case *ast.ExpressionStatement:
+		c.replaceLastPopWithReturn()
+	case *ast.ReturnStatement:
+	default:
+		c.emit(code.OpReturn)
+	}
+
+	freeSymbols := c.symbolTable.FreeSymbols
+	numLocals := c.symbolTable.NumDefinitions()
+	handlers := c.scopes[c.scopeIndex].handlers
+	lines := c.scopes[c.scopeIndex].lines
+	symbols := c.symbolTable
+	instructions := c.leaveScope()
+
+	for _, symbol := range freeSymbols {
+		c.captureSymbol(symbol)
+	}
+
+	fn := &object.CompiledFunction{
+		Instructions:  instructions,
+		NumLocals:     numLocals,
+		NumParameters: len(parameters),
+		Name:          name,
+		IsGenerator:   isGenerator,
+		Handlers:      handlers,
+		Lines:         lines,
+	}
+	c.functionSymbols[fn] = symbols
+
+	c.emit(code.OpClosure, c.addConstant(fn), len(freeSymbols))
+	return nil
+}
+
+// The iterator stays on the stack for the whole loop, ---
+// OpIterNext pops it and jumps past the body once exhausted ---
+func (c *Compiler) compileForIn(node *ast.ForInStatement) error {
+	err := c.Compile(node.Iterable)
+	if err != nil {
+		return err
+	}
+
+	c.emit(code.OpIter)
+	c.scopes[c.scopeIndex].loopDepth++
+	defer func() { c.scopes[c.scopeIndex].loopDepth-- }()
+
+	loopStart := len(c.currentInstructions())
+	iterNextPos := c.emit(code.OpIterNext, 9999)
+
+	// The loop variable lives in the enclosing scope, like the evaluator ---
+	symbol, exists := c.symbolTable.Resolve(node.Variable.Value)
+	if !exists || !c.isOwnSymbol(symbol) {
+		symbol, _ = c.symbolTable.Define(node.Variable.Value)
+	}
+	c.storeSymbol(symbol)
+
+	err = c.Compile(node.Body)
+	if err != nil {
+		return err
+	}
+
+	c.emit(code.OpJump, loopStart)
+	c.changeOperand(iterNextPos, len(c.currentInstructions()))
+	return nil
+}
+
+// isOwnSymbol reports whether symbol is a variable of the scope being compiled ---
+func (c *Compiler) isOwnSymbol(symbol Symbol) bool {
+	if c.symbolTable.Outer == nil {
+		return symbol.Scope == GlobalScope
+	}
+
+	return symbol.Scope == LocalScope
+}
+
+// emitOperator emits the opcode of a binary operator, besides and ---
+// and or which jump ---
+func (c *Compiler) emitOperator(operator token.Token) error {
+	switch operator.Type {
+	case token.CARET:
+		c.emit(code.OpExponent)
+	case token.PLUS:
+		c.emit(code.OpAdd)
+	case token.MINUS:
+		c.emit(code.OpSubtract)
+	case token.STAR:
+		c.emit(code.OpMultiply)
+	case token.SLASH:
+		c.emit(code.OpDivide)
+	case token.PERCENT:
+		c.emit(code.OpModulo)
+	case token.FLOOR_DIVIDE:
+		c.emit(code.OpFloorDivide)
+
+	case token.AMPERSAND:
+		c.emit(code.OpBitAnd)
+	case token.PIPE:
+		c.emit(code.OpBitOr)
+	case token.XOR:
+		c.emit(code.OpBitXor)
+	case token.SHIFT_LEFT:
+		c.emit(code.OpShiftLeft)
+	case token.SHIFT_RIGHT:
+		c.emit(code.OpShiftRight)
+
+	case token.EQUAL:
+		c.emit(code.OpEqual)
+	case token.NOT_EQUAL:
+		c.emit(code.OpNotEqual)
+	case token.LESS:
+		c.emit(code.OpLess)
+	case token.GREATER:
+		c.emit(code.OpGreater)
+	case token.LESS_EQUAL:
+		c.emit(code.OpLessEqual)
+	case token.GREATER_EQUAL:
+		c.emit(code.OpGreaterEqual)
+
+	default:
+		return fmt.Errorf("Unknown binary operator token: '%s'", operator.Type)
+	}
+
+	return nil
+}
+
+func (c *Compiler) loadSymbol(symbol Symbol) {
+	switch symbol.Scope {
+	case GlobalScope:
+		c.emit(code.OpGetGlobal, symbol.Index)
+	case LocalScope:
+		c.emit(code.OpGetLocal, symbol.Index)
+	case BuiltinScope:
+		c.emit(code.OpGetBuiltin, symbol.Index)
+	case FreeScope:
+		c.emit(code.OpGetFree, symbol.Index)
+	case FunctionScope:
+		c.emit(code.OpCurrentClosure)
+	}
+}
+
+func (c *Compiler) storeSymbol(symbol Symbol) {
+	switch symbol.Scope {
+	case GlobalScope:
+		c.emit(code.OpSetGlobal, symbol.Index)
+	case FreeScope:
+		c.emit(code.OpSetFree, symbol.Index)
+	default:
+		c.emit(code.OpSetLocal, symbol.Index)
+	}
+}
+
+// captureSymbol pushes what a new closure keeps of symbol, locals ---
+// and free variables are shared through their cell, not copied ---
+func (c *Compiler) captureSymbol(symbol Symbol) {
+	switch symbol.Scope {
+	case LocalScope:
+		c.emit(code.OpCaptureLocal, symbol.Index)
+	case FreeScope:
+		c.emit(code.OpCaptureFree, symbol.Index)
+	default:
+		c.loadSymbol(symbol)
+	}
+}
+
+// Builtins and a function's own name are the only read-only names ---
+func (c *Compiler) checkAssignable(symbol Symbol) error {
+	switch symbol.Scope {
+	case GlobalScope, LocalScope, FreeScope:
+		return nil
+	default:
+		return fmt.Errorf("Cannot assign to '%s'", symbol.Name)
+	}
+}
+
+// hoistFunctions defines the names of the function declarations in ---
+// statements up front, so a function can call one declared after it ---
+func (c *Compiler) hoistFunctions(statements []ast.Statement) error {
+	for _, stmt := range statements {
+		decl, ok := stmt.(*ast.FunctionDeclarationStatement)
+		if !ok {
+			continue
+		}
+
+		symbol, exists := c.symbolTable.Define(decl.Name.Value)
+		if exists {
+			return &Error{
+				Line:    int(decl.GetLine()),
+				Column:  int(decl.GetColumn()),
+				Message: fmt.Sprintf("Cannot redeclare already existing variable '%s'", decl.Name.Value),
+			}
+		}
+
+		c.hoisted[decl] = symbol
+	}
+
+	return nil
+}
+
+// SymbolTable exposes the (global) symbol table, e.g. to look up globals by name.
+func (c *Compiler) SymbolTable() *SymbolTable {
+	return c.symbolTable
+}
+
+// FunctionSymbols returns the scope fn was compiled in, ---
+// DefinedNames gives its locals by slot ---
+func (c *Compiler) FunctionSymbols(fn *object.CompiledFunction) (*SymbolTable, bool) {
+	table, ok := c.functionSymbols[fn]
+	return table, ok
+}
+
 func (c *Compiler) Bytecode() *Bytecode {
 	return &Bytecode{
-		Instructions: c.instructions,
+		Instructions: c.currentInstructions(),
 		Constants:    c.constants,
+		Handlers:     c.scopes[c.scopeIndex].handlers,
+		Lines:        c.scopes[c.scopeIndex].lines,
+		File:         c.File,
 	}
 }
@@ -0,0 +1,22 @@
+package compiler
+
+// Error is a compile error at the position of the node that caused it, ---
+// Error() is the bare message so existing output stays the same ---
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errors from nested nodes keep the innermost position ---
+func (c *Compiler) positionError(err error) error {
+	if _, ok := err.(*Error); ok || err == nil {
+		return err
+	}
+
+	return &Error{Line: c.line, Column: c.column, Message: err.Error()}
+}
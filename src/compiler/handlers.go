@@ -0,0 +1,177 @@
+package compiler
+
+import (
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/code"
+)
+
+// tryContext tracks the handler entries of one try statement. A region ---
+// can be split into several entries: inlined finally code (before a ---
+// return) must not be protected by the try it belongs to ---
+type tryContext struct {
+	finally *ast.BlockStatement
+	depth   int
+
+	tryEntries   []int // Indexes into the scope's handlers ---
+	catchEntries []int
+
+	open      *[]int // Group of the region currently open ---
+	suspended *[]int // Group closed by a return, reopened after it ---
+}
+
+// Layout ---
+//
+//	try block          (protected, errors go to catch or finally handler)
+//	OpJump done
+//	catch: store e     (protected by the finally handler, if any)
+//	catch block
+//	OpJump done
+//	finally handler:   (error on the stack)
+//	finally block
+//	OpThrow
+//	done:
+//	finally block
+func (c *Compiler) compileTry(node *ast.TryStatement) error {
+	t := &tryContext{finally: node.Finally, depth: c.scopes[c.scopeIndex].loopDepth}
+	c.scopes[c.scopeIndex].tries = append(c.scopes[c.scopeIndex].tries, t)
+
+	c.openRegion(t, &t.tryEntries)
+	err := c.Compile(node.Block)
+	if err != nil {
+		return err
+	}
+	c.closeRegion(t)
+
+	doneJumps := []int{c.emit(code.OpJump, 9999)}
+
+	if node.Catch != nil {
+		c.patchHandlers(t.tryEntries, len(c.currentInstructions()))
+
+		if node.Finally != nil {
+			c.openRegion(t, &t.catchEntries)
+		}
+
+		if node.CatchParam != nil {
+			symbol, exists := c.symbolTable.Resolve(node.CatchParam.Value)
+			if !exists || !c.isOwnSymbol(symbol) {
+				symbol, _ = c.symbolTable.Define(node.CatchParam.Value)
+			}
+			c.storeSymbol(symbol)
+		} else {
+			c.emit(code.OpPop)
+		}
+
+		err := c.Compile(node.Catch)
+		if err != nil {
+			return err
+		}
+		c.closeRegion(t)
+
+		doneJumps = append(doneJumps, c.emit(code.OpJump, 9999))
+	}
+
+	tries := c.scopes[c.scopeIndex].tries
+	c.scopes[c.scopeIndex].tries = tries[:len(tries)-1]
+
+	if node.Finally != nil {
+		handler := len(c.currentInstructions())
+		if node.Catch != nil {
+			c.patchHandlers(t.catchEntries, handler)
+		} else {
+			c.patchHandlers(t.tryEntries, handler)
+		}
+		c.patchFinally(t.tryEntries, handler)
+		c.patchFinally(t.catchEntries, handler)
+
+		err := c.Compile(node.Finally)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpThrow) // Rethrow ---
+	}
+
+	done := len(c.currentInstructions())
+	for _, jump := range doneJumps {
+		c.changeOperand(jump, done)
+	}
+
+	if node.Finally != nil {
+		return c.Compile(node.Finally)
+	}
+
+	return nil
+}
+
+// compilePendingFinally inlines the finally blocks of every enclosing ---
+// try (innermost first) before a return, each one unprotected by its own try ---
+func (c *Compiler) compilePendingFinally() error {
+	tries := c.scopes[c.scopeIndex].tries
+
+	for i := len(tries) - 1; i >= 0; i-- {
+		if tries[i].open != nil {
+			tries[i].suspended = tries[i].open
+			c.closeRegion(tries[i])
+		}
+
+		if tries[i].finally == nil {
+			continue
+		}
+
+		c.scopes[c.scopeIndex].tries = tries[:i]
+		err := c.Compile(tries[i].finally)
+		c.scopes[c.scopeIndex].tries = tries
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resumeTries reopens the regions a return closed, outermost first ---
+// so inner handlers keep winning (see vm.handleError) ---
+func (c *Compiler) resumeTries() {
+	for _, t := range c.scopes[c.scopeIndex].tries {
+		if t.suspended != nil {
+			c.openRegion(t, t.suspended)
+			t.suspended = nil
+		}
+	}
+}
+
+func (c *Compiler) openRegion(t *tryContext, group *[]int) {
+	scope := &c.scopes[c.scopeIndex]
+	scope.handlers = append(scope.handlers, code.Handler{
+		Start:      len(scope.instructions),
+		End:        len(scope.instructions),
+		StackDepth: t.depth,
+	})
+
+	*group = append(*group, len(scope.handlers)-1)
+	t.open = group
+}
+
+func (c *Compiler) closeRegion(t *tryContext) {
+	if t.open == nil {
+		return
+	}
+
+	scope := &c.scopes[c.scopeIndex]
+	entries := *t.open
+	scope.handlers[entries[len(entries)-1]].End = len(scope.instructions)
+	t.open = nil
+}
+
+func (c *Compiler) patchHandlers(entries []int, target int) {
+	for _, entry := range entries {
+		c.scopes[c.scopeIndex].handlers[entry].Target = target
+	}
+}
+
+func (c *Compiler) patchFinally(entries []int, target int) {
+	for _, entry := range entries {
+		c.scopes[c.scopeIndex].handlers[entry].Finally = target
+	}
+}
@@ -0,0 +1,134 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// binder stores the value on top of the stack in a name ---
+type binder func(name *ast.Identifier) error
+
+// destructure unpacks the value on top of the stack into the names of ---
+// pattern through bind, the value is consumed ---
+func (c *Compiler) destructure(pattern ast.Expression, bind binder) (err error) {
+	// Like Compile, so a value that doesn't fit is reported at its pattern ---
+	prevLine, prevColumn := c.line, c.column
+	c.line, c.column = int(pattern.GetLine()), int(pattern.GetColumn())
+	defer func() {
+		err = c.positionError(err)
+		c.line, c.column = prevLine, prevColumn
+	}()
+
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		return bind(pattern)
+
+	case *ast.DefaultPattern:
+		jumpPos := c.emit(code.OpJumpNotNil, 9999)
+
+		err := c.Compile(pattern.Default)
+		if err != nil {
+			return err
+		}
+
+		c.changeOperand(jumpPos, len(c.currentInstructions()))
+		return c.destructure(pattern.Target, bind)
+
+	case *ast.ArrayPattern:
+		rest := 0
+		if pattern.Rest != nil {
+			rest = 1
+		}
+		c.emit(code.OpUnpackArray, pattern.Required(), len(pattern.Elements), rest)
+
+		for i, element := range pattern.Elements {
+			c.emit(code.OpUnpackElement, i, 0)
+
+			err := c.destructure(element, bind)
+			if err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			c.emit(code.OpUnpackElement, len(pattern.Elements), 1)
+
+			err := bind(pattern.Rest)
+			if err != nil {
+				return err
+			}
+		}
+		c.emit(code.OpPop)
+
+	case *ast.HashPattern:
+		c.emit(code.OpUnpackHash)
+
+		for _, entry := range pattern.Entries {
+			hasDefault := 0
+			if _, ok := entry.Value.(*ast.DefaultPattern); ok {
+				hasDefault = 1
+			}
+
+			key := c.addConstant(&object.String{Value: entry.Key.Value})
+			c.line, c.column = int(entry.Key.GetLine()), int(entry.Key.GetColumn())
+			c.emit(code.OpUnpackKey, key, hasDefault)
+			c.line, c.column = int(pattern.GetLine()), int(pattern.GetColumn())
+
+			err := c.destructure(entry.Value, bind)
+			if err != nil {
+				return err
+			}
+		}
+		c.emit(code.OpPop)
+
+	default:
+		return fmt.Errorf("Cannot destructure into %s", pattern.String())
+	}
+
+	return nil
+}
+
+// checkDuplicates rejects a declaration or parameter list that binds ---
+// a name twice, reported at the second one ---
+func checkDuplicates(names []*ast.Identifier) error {
+	duplicate := ast.DuplicateName(names)
+	if duplicate == nil {
+		return nil
+	}
+
+	return &Error{
+		Line:    int(duplicate.GetLine()),
+		Column:  int(duplicate.GetColumn()),
+		Message: fmt.Sprintf("Cannot bind '%s' twice in the same declaration", duplicate.Value),
+	}
+}
+
+// declare binds names as new variables of the current scope ---
+func (c *Compiler) declare(name *ast.Identifier) error {
+	symbol, exists := c.symbolTable.Define(name.Value)
+	if exists {
+		return fmt.Errorf("Cannot redeclare already existing variable '%s'", name.Value)
+	}
+
+	c.storeSymbol(symbol)
+	return nil
+}
+
+// assign binds names to the variables they already are ---
+func (c *Compiler) assign(name *ast.Identifier) error {
+	symbol, exists := c.symbolTable.Resolve(name.Value)
+	if !exists {
+		return fmt.Errorf("Cannot resolve variable '%s'", name.Value)
+	}
+
+	err := c.checkAssignable(symbol)
+	if err != nil {
+		return err
+	}
+
+	c.storeSymbol(symbol)
+	return nil
+}
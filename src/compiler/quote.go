@@ -0,0 +1,37 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// A quote is a constant, unquote(...) needs the evaluator since ---
+// its argument is turned back into code while the program runs ---
+func (c *Compiler) compileQuote(node *ast.CallExpression) error {
+	if len(node.Arguments) != 1 {
+		return fmt.Errorf("quote() expects 1 argument, got %d", len(node.Arguments))
+	}
+
+	unquoted := false
+	ast.Inspect(node.Arguments[0], func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpression); ok && isCallTo(call, "unquote") {
+			unquoted = true
+		}
+		return !unquoted
+	})
+
+	if unquoted {
+		return fmt.Errorf("unquote() outside of a macro is only supported by the evaluator")
+	}
+
+	c.emit(code.OpConstant, c.addConstant(&object.Quote{Node: node.Arguments[0]}))
+	return nil
+}
+
+func isCallTo(call *ast.CallExpression, name string) bool {
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == name
+}
@@ -3,7 +3,11 @@ package compiler
 type SymbolScope string
 
 const (
-	GlobalScope SymbolScope = "GLOBAL"
+	GlobalScope   SymbolScope = "GLOBAL"
+	LocalScope    SymbolScope = "LOCAL"
+	BuiltinScope  SymbolScope = "BUILTIN"
+	FreeScope     SymbolScope = "FREE"
+	FunctionScope SymbolScope = "FUNCTION"
 )
 
 type Symbol struct {
@@ -13,30 +17,98 @@ type Symbol struct {
 }
 
 type SymbolTable struct {
+	Outer *SymbolTable
+
 	store          map[string]Symbol
 	numDefinitions int
+
+	FreeSymbols []Symbol
 }
 
 func NewSymbolTable() *SymbolTable {
 	return &SymbolTable{
-		store: make(map[string]Symbol),
+		store:       make(map[string]Symbol),
+		FreeSymbols: make([]Symbol, 0),
 	}
 }
 
+func NewEnclosedSymbolTable(outer *SymbolTable) *SymbolTable {
+	table := NewSymbolTable()
+	table.Outer = outer
+	return table
+}
+
+// Define only rejects names declared in this same scope,
+// so locals are allowed to shadow outer variables ---
 func (s *SymbolTable) Define(name string) (Symbol, bool) {
-	_, exists := s.Resolve(name)
-	if exists {
+	if existing, exists := s.store[name]; exists && existing.Scope != FunctionScope {
 		return Symbol{}, exists
 	}
 
-	symbol := Symbol{Name: name, Scope: GlobalScope, Index: s.numDefinitions}
+	symbol := Symbol{Name: name, Index: s.numDefinitions}
+	if s.Outer == nil {
+		symbol.Scope = GlobalScope
+	} else {
+		symbol.Scope = LocalScope
+	}
+
 	s.store[name] = symbol
 	s.numDefinitions++
 	return symbol, false
 }
 
+func (s *SymbolTable) DefineBuiltin(index int, name string) Symbol {
+	symbol := Symbol{Name: name, Scope: BuiltinScope, Index: index}
+	s.store[name] = symbol
+	return symbol
+}
+
+// DefineFunctionName lets a function refer to itself ---
+// without capturing a not-yet-assigned variable ---
+func (s *SymbolTable) DefineFunctionName(name string) Symbol {
+	symbol := Symbol{Name: name, Scope: FunctionScope, Index: 0}
+	s.store[name] = symbol
+	return symbol
+}
 
 func (s *SymbolTable) Resolve(name string) (Symbol, bool) {
 	symbol, exists := s.store[name]
-	return symbol, exists
+	if exists || s.Outer == nil {
+		return symbol, exists
+	}
+
+	symbol, exists = s.Outer.Resolve(name)
+	if !exists {
+		return symbol, exists
+	}
+
+	if symbol.Scope == GlobalScope || symbol.Scope == BuiltinScope {
+		return symbol, exists
+	}
+
+	return s.defineFree(symbol), true
+}
+
+// DefinedNames returns the names defined in this scope (not builtins), indexed by slot.
+func (s *SymbolTable) DefinedNames() []string {
+	names := make([]string, s.numDefinitions)
+	for name, symbol := range s.store {
+		if symbol.Scope == GlobalScope || symbol.Scope == LocalScope {
+			names[symbol.Index] = name
+		}
+	}
+
+	return names
+}
+
+func (s *SymbolTable) NumDefinitions() int {
+	return s.numDefinitions
+}
+
+func (s *SymbolTable) defineFree(original Symbol) Symbol {
+	s.FreeSymbols = append(s.FreeSymbols, original)
+
+	symbol := Symbol{Name: original.Name, Scope: FreeScope, Index: len(s.FreeSymbols) - 1}
+	s.store[original.Name] = symbol
+	return symbol
 }
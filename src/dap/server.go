@@ -0,0 +1,493 @@
+// Package dap serves the Debug Adapter Protocol (`monkey dap`) over a
+// reader/writer pair, on top of the same debug.Session as `monkey debug`.
+package dap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/caelondev/monkey-compiler-go/src/debug"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/run"
+	"github.com/caelondev/monkey-compiler-go/src/vm"
+)
+
+// Scripts run on a single VM, so there's only ever one thread ---
+const THREAD_ID = 1
+
+type Server struct {
+	reader *bufio.Reader
+
+	// Responses come from Serve, events also from the VM's goroutine ---
+	writeMu sync.Mutex
+	writer  io.Writer
+	seq     int
+
+	session    *debug.Session
+	launched   bool
+	configured bool
+	running    bool
+
+	// Set while the program is paused, the VM waits on resume ---
+	mu      sync.Mutex
+	paused  *vm.VM
+	resume  chan struct{}
+	handles []func() []variable // variablesReference n is handles[n-1] ---
+
+	finished chan struct{}
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		reader:   bufio.NewReader(in),
+		writer:   out,
+		resume:   make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+}
+
+// Serve handles requests until the client disconnects or in ends.
+func (s *Server) Serve() error {
+	for {
+		msg, err := readMessage(s.reader)
+		if errors.Is(err, io.EOF) {
+			s.stop()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Type != "request" {
+			continue
+		}
+
+		body, err := s.handle(msg)
+		if err != nil {
+			s.send(&response{Type: "response", RequestSeq: msg.Seq, Command: msg.Command, Message: err.Error()})
+			continue
+		}
+
+		s.send(&response{Type: "response", RequestSeq: msg.Seq, Success: true, Command: msg.Command, Body: body})
+
+		switch msg.Command {
+		case "launch":
+			// Breakpoints need the compiled program, so configuration waits for launch ---
+			s.sendEvent("initialized", nil)
+			s.start()
+		case "configurationDone":
+			s.start()
+		case "disconnect":
+			// Let the program report its exit first ---
+			if s.running {
+				<-s.finished
+			}
+			return nil
+		}
+	}
+}
+
+func (s *Server) handle(msg *request) (any, error) {
+	switch msg.Command {
+	case "initialize":
+		return map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+		}, nil
+
+	case "launch":
+		return nil, s.launch(msg.Arguments)
+	case "setBreakpoints":
+		return s.setBreakpoints(msg.Arguments)
+	case "setExceptionBreakpoints":
+		return nil, nil
+	case "configurationDone":
+		s.configured = true
+		return nil, nil
+
+	case "threads":
+		return map[string]any{"threads": []thread{{ID: THREAD_ID, Name: "main"}}}, nil
+	case "stackTrace":
+		return s.stackTrace()
+	case "scopes":
+		return s.scopes(msg.Arguments)
+	case "variables":
+		return s.variables(msg.Arguments)
+	case "evaluate":
+		return s.evaluate(msg.Arguments)
+
+	case "continue":
+		return map[string]any{"allThreadsContinued": true}, s.step(debug.Continue)
+	case "next":
+		return nil, s.step(debug.StepOver)
+	case "stepIn":
+		return nil, s.step(debug.StepIn)
+	case "stepOut":
+		return nil, s.step(debug.StepOut)
+
+	case "disconnect", "terminate":
+		s.stop()
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("Unsupported command '%s'", msg.Command)
+	}
+}
+
+func (s *Server) launch(raw json.RawMessage) error {
+	var args launchArguments
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return err
+	}
+
+	session, err := debug.Load(args.Program)
+	if err != nil {
+		return err
+	}
+
+	if !args.StopOnEntry {
+		session.Resume(debug.Continue)
+	}
+	session.OnPause = s.pause
+
+	s.session = session
+	s.launched = true
+	return nil
+}
+
+// The program starts once it's launched and the client is done configuring ---
+func (s *Server) start() {
+	if !s.launched || !s.configured || s.session == nil {
+		return
+	}
+	s.launched = false
+	s.running = true
+
+	stdout := &outputWriter{server: s}
+	machine := s.session.NewVM()
+	machine.Stdout = stdout
+	// The protocol owns our stdin ---
+	machine.Stdin = strings.NewReader("")
+
+	go func() {
+		defer close(s.finished)
+
+		exitCode := 0
+		err := machine.Run()
+		stdout.Flush()
+
+		if err != nil && !errors.Is(err, vm.ErrStopped) {
+			s.sendEvent("output", map[string]any{"category": "stderr", "output": run.FormatVMError(err)})
+			exitCode = 1
+		}
+
+		s.sendEvent("exited", map[string]any{"exitCode": exitCode})
+		s.sendEvent("terminated", nil)
+	}()
+}
+
+// pause runs on the VM's goroutine and blocks it until a step request ---
+func (s *Server) pause(machine *vm.VM, reason debug.StopReason) error {
+	s.mu.Lock()
+	s.paused = machine
+	s.handles = nil
+	s.mu.Unlock()
+
+	s.sendEvent("stopped", map[string]any{
+		"reason":            string(reason),
+		"threadId":          THREAD_ID,
+		"allThreadsStopped": true,
+	})
+
+	<-s.resume
+	return nil
+}
+
+func (s *Server) step(mode debug.StepMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.paused == nil {
+		return errors.New("The program is not paused")
+	}
+
+	s.session.Resume(mode)
+	s.paused = nil
+	s.resume <- struct{}{}
+	return nil
+}
+
+func (s *Server) stop() {
+	if s.session == nil {
+		return
+	}
+
+	s.session.Stop()
+
+	s.mu.Lock()
+	if s.paused != nil {
+		s.paused = nil
+		s.resume <- struct{}{}
+	}
+	s.mu.Unlock()
+}
+
+func (s *Server) setBreakpoints(raw json.RawMessage) (any, error) {
+	var args setBreakpointsArguments
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+
+	if s.session == nil {
+		return nil, errors.New("Breakpoints can only be set after launch")
+	}
+
+	lines := make([]int, 0, len(args.Breakpoints))
+	breakpoints := make([]breakpoint, 0, len(args.Breakpoints))
+
+	for _, bp := range args.Breakpoints {
+		if !s.session.HasCode(bp.Line) {
+			breakpoints = append(breakpoints, breakpoint{Line: bp.Line, Message: "No code on this line"})
+			continue
+		}
+
+		lines = append(lines, bp.Line)
+		breakpoints = append(breakpoints, breakpoint{Verified: true, Line: bp.Line})
+	}
+
+	s.session.SetBreakpoints(lines)
+	return map[string]any{"breakpoints": breakpoints}, nil
+}
+
+// pausedFrames must be called with mu held ---
+func (s *Server) pausedFrames() ([]vm.DebugFrame, error) {
+	if s.paused == nil {
+		return nil, errors.New("The program is not paused")
+	}
+
+	return s.paused.DebugFrames(), nil
+}
+
+func (s *Server) frame(id int) (vm.DebugFrame, error) {
+	frames, err := s.pausedFrames()
+	if err != nil {
+		return vm.DebugFrame{}, err
+	}
+
+	if id < 0 || id >= len(frames) {
+		return vm.DebugFrame{}, fmt.Errorf("Unknown frame %d", id)
+	}
+
+	return frames[id], nil
+}
+
+func (s *Server) stackTrace() (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frames, err := s.pausedFrames()
+	if err != nil {
+		return nil, err
+	}
+
+	stackFrames := make([]stackFrame, len(frames))
+	for i, frame := range frames {
+		stackFrames[i] = stackFrame{
+			ID:     i,
+			Name:   debug.FrameName(frame),
+			Source: s.source(),
+			Line:   frame.Line,
+			Column: frame.Column,
+		}
+	}
+
+	return map[string]any{"stackFrames": stackFrames, "totalFrames": len(frames)}, nil
+}
+
+func (s *Server) source() source {
+	path, err := filepath.Abs(s.session.File)
+	if err != nil {
+		path = s.session.File
+	}
+
+	return source{Name: filepath.Base(path), Path: path}
+}
+
+func (s *Server) scopes(raw json.RawMessage) (any, error) {
+	var args frameArguments
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frame, err := s.frame(args.FrameID)
+	if err != nil {
+		return nil, err
+	}
+
+	machine := s.paused
+	locals := s.addHandle(func() []variable {
+		return s.toVariables(s.session.Locals(frame))
+	})
+	globals := s.addHandle(func() []variable {
+		return s.toVariables(s.session.Globals(machine))
+	})
+
+	return map[string]any{"scopes": []scope{
+		{Name: "Locals", VariablesReference: locals},
+		{Name: "Globals", VariablesReference: globals},
+	}}, nil
+}
+
+func (s *Server) variables(raw json.RawMessage) (any, error) {
+	var args variablesArguments
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.paused == nil {
+		return nil, errors.New("The program is not paused")
+	}
+
+	ref := args.VariablesReference
+	if ref < 1 || ref > len(s.handles) {
+		return nil, fmt.Errorf("Unknown variables reference %d", ref)
+	}
+
+	return map[string]any{"variables": s.handles[ref-1]()}, nil
+}
+
+func (s *Server) evaluate(raw json.RawMessage) (any, error) {
+	var args evaluateArguments
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frameID := 0
+	if args.FrameID != nil {
+		frameID = *args.FrameID
+	}
+
+	frame, err := s.frame(frameID)
+	if err != nil {
+		return nil, err
+	}
+
+	var printed strings.Builder
+	result, err := s.session.Evaluate(s.paused, frame, args.Expression, &printed)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"result":             printed.String() + result.Inspect(),
+		"type":               string(result.Type()),
+		"variablesReference": s.childHandle(result),
+	}, nil
+}
+
+// Handles live until the program resumes, like the DAP spec suggests ---
+func (s *Server) addHandle(children func() []variable) int {
+	s.handles = append(s.handles, children)
+	return len(s.handles)
+}
+
+// Arrays and hashes can be expanded ---
+func (s *Server) childHandle(value object.Object) int {
+	switch value := value.(type) {
+	case *object.Array:
+		return s.addHandle(func() []variable {
+			names := make([]string, len(value.Elements))
+			for i := range value.Elements {
+				names[i] = fmt.Sprint(i)
+			}
+			return s.toVariables(names, value.Elements)
+		})
+
+	case *object.Hash:
+		return s.addHandle(func() []variable {
+			names := make([]string, 0, len(value.Pairs))
+			values := make([]object.Object, 0, len(value.Pairs))
+			for _, pair := range value.Pairs {
+				names = append(names, pair.Key.Inspect())
+				values = append(values, pair.Value)
+			}
+			return s.toVariables(names, values)
+		})
+
+	default:
+		return 0
+	}
+}
+
+func (s *Server) toVariables(names []string, values []object.Object) []variable {
+	variables := make([]variable, len(names))
+
+	for i, name := range names {
+		variables[i] = variable{
+			Name:               name,
+			Value:              values[i].Inspect(),
+			Type:               string(values[i].Type()),
+			VariablesReference: s.childHandle(values[i]),
+		}
+	}
+
+	return variables
+}
+
+func (s *Server) send(msg *response) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.seq++
+	msg.Seq = s.seq
+	writeMessage(s.writer, msg)
+}
+
+func (s *Server) sendEvent(name string, body any) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.seq++
+	writeMessage(s.writer, &event{Seq: s.seq, Type: "event", Event: name, Body: body})
+}
+
+// outputWriter turns the program's print() output into output events, ---
+// a line at a time since print writes each argument separately ---
+type outputWriter struct {
+	server  *Server
+	pending []byte
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+
+	if i := bytes.LastIndexByte(w.pending, '\n'); i >= 0 {
+		w.server.sendEvent("output", map[string]any{"category": "stdout", "output": string(w.pending[:i+1])})
+		w.pending = w.pending[i+1:]
+	}
+
+	return len(p), nil
+}
+
+func (w *outputWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.server.sendEvent("output", map[string]any{"category": "stdout", "output": string(w.pending)})
+		w.pending = nil
+	}
+}
@@ -0,0 +1,308 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const debuggeeSource = `var total = 0;
+fn add(a, b) {
+  var sum = a + b;
+  return sum;
+}
+total = add(2, 3);
+print(total);
+`
+
+// message is any DAP message the server sends, decoded loosely ---
+type message struct {
+	Type       string          `json:"type"`
+	Command    string          `json:"command"`
+	Event      string          `json:"event"`
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// key is "response <command>" or "event <event>" ---
+func (m *message) key() string {
+	if m.Type == "event" {
+		return "event " + m.Event
+	}
+	return m.Type + " " + m.Command
+}
+
+type client struct {
+	t        *testing.T
+	in       *io.PipeWriter
+	messages chan *message
+	done     chan error
+}
+
+func startServer(t *testing.T) *client {
+	t.Helper()
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	c := &client{
+		t:        t,
+		in:       inWriter,
+		messages: make(chan *message, 64),
+		done:     make(chan error, 1),
+	}
+
+	go func() {
+		c.done <- NewServer(inReader, outWriter).Serve()
+		outWriter.Close()
+	}()
+
+	go func() {
+		defer close(c.messages)
+
+		reader := bufio.NewReader(outReader)
+		for {
+			headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+			if err != nil {
+				return
+			}
+
+			length, _ := strconv.Atoi(headers.Get("Content-Length"))
+			body := make([]byte, length)
+			if _, err := io.ReadFull(reader, body); err != nil {
+				return
+			}
+
+			msg := &message{}
+			if err := json.Unmarshal(body, msg); err != nil {
+				t.Errorf("invalid message %s: %s", body, err)
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+
+	t.Cleanup(func() { inWriter.Close() })
+	return c
+}
+
+func (c *client) send(raw string) {
+	c.t.Helper()
+
+	_, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(raw), raw)
+	if err != nil {
+		c.t.Fatalf("writing request: %s", err)
+	}
+}
+
+// expect reads messages until every key was seen, events arrive from the VM's ---
+// goroutine so they can interleave with responses ---
+func (c *client) expect(keys ...string) map[string]*message {
+	c.t.Helper()
+
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+
+	got := make(map[string]*message, len(keys))
+	timeout := time.After(5 * time.Second)
+
+	for len(got) < len(wanted) {
+		select {
+		case msg, ok := <-c.messages:
+			if !ok {
+				c.t.Fatalf("server closed the stream, still waiting for %v", missing(wanted, got))
+			}
+			if !wanted[msg.key()] {
+				c.t.Fatalf("unexpected %s (%s) while waiting for %v", msg.key(), msg.Body, missing(wanted, got))
+			}
+			if msg.Type == "response" && !msg.Success {
+				c.t.Fatalf("%s failed: %s", msg.key(), msg.Message)
+			}
+			got[msg.key()] = msg
+
+		case <-timeout:
+			c.t.Fatalf("timed out waiting for %v", missing(wanted, got))
+		}
+	}
+
+	return got
+}
+
+func missing(wanted map[string]bool, got map[string]*message) []string {
+	keys := make([]string, 0)
+	for key := range wanted {
+		if got[key] == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func decodeBody(t *testing.T, msg *message, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(msg.Body, v); err != nil {
+		t.Fatalf("invalid %s body %s: %s", msg.key(), msg.Body, err)
+	}
+}
+
+func TestServerSession(t *testing.T) {
+	program := filepath.Join(t.TempDir(), "debuggee.mn")
+	if err := os.WriteFile(program, []byte(debuggeeSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	quoted, _ := json.Marshal(program)
+
+	c := startServer(t)
+
+	c.send(`{"seq":1,"type":"request","command":"initialize","arguments":{"adapterID":"monkey"}}`)
+	got := c.expect("response initialize")
+
+	var capabilities map[string]bool
+	decodeBody(t, got["response initialize"], &capabilities)
+	if !capabilities["supportsConfigurationDoneRequest"] {
+		t.Errorf("expected supportsConfigurationDoneRequest, got %s", got["response initialize"].Body)
+	}
+
+	c.send(fmt.Sprintf(`{"seq":2,"type":"request","command":"launch","arguments":{"program":%s,"stopOnEntry":false}}`, quoted))
+	c.expect("response launch", "event initialized")
+
+	c.send(fmt.Sprintf(`{"seq":3,"type":"request","command":"setBreakpoints","arguments":{"source":{"path":%s},"breakpoints":[{"line":4},{"line":5}]}}`, quoted))
+	got = c.expect("response setBreakpoints")
+
+	var breakpoints struct{ Breakpoints []breakpoint }
+	decodeBody(t, got["response setBreakpoints"], &breakpoints)
+	expectedBreakpoints := []breakpoint{{Verified: true, Line: 4}, {Line: 5, Message: "No code on this line"}}
+	if fmt.Sprint(breakpoints.Breakpoints) != fmt.Sprint(expectedBreakpoints) {
+		t.Errorf("wrong breakpoints\nexpected: %v\ngot:      %v", expectedBreakpoints, breakpoints.Breakpoints)
+	}
+
+	c.send(`{"seq":4,"type":"request","command":"configurationDone"}`)
+	got = c.expect("response configurationDone", "event stopped")
+
+	var stopped struct {
+		Reason   string
+		ThreadID int
+	}
+	decodeBody(t, got["event stopped"], &stopped)
+	if stopped.Reason != "breakpoint" || stopped.ThreadID != THREAD_ID {
+		t.Errorf("wrong stopped event: %s", got["event stopped"].Body)
+	}
+
+	c.send(`{"seq":5,"type":"request","command":"stackTrace","arguments":{"threadId":1}}`)
+	got = c.expect("response stackTrace")
+
+	var trace struct {
+		StackFrames []stackFrame
+		TotalFrames int
+	}
+	decodeBody(t, got["response stackTrace"], &trace)
+	if trace.TotalFrames != 2 || len(trace.StackFrames) != 2 {
+		t.Fatalf("expected 2 frames, got %s", got["response stackTrace"].Body)
+	}
+	for i, expected := range []struct {
+		name string
+		line int
+	}{{"add", 4}, {"<main>", 6}} {
+		frame := trace.StackFrames[i]
+		if frame.ID != i || frame.Name != expected.name || frame.Line != expected.line {
+			t.Errorf("frame %d: expected %s at line %d, got %+v", i, expected.name, expected.line, frame)
+		}
+		if frame.Source.Name != "debuggee.mn" {
+			t.Errorf("frame %d: wrong source %+v", i, frame.Source)
+		}
+	}
+
+	c.send(`{"seq":6,"type":"request","command":"scopes","arguments":{"frameId":0}}`)
+	got = c.expect("response scopes")
+
+	var scopes struct{ Scopes []scope }
+	decodeBody(t, got["response scopes"], &scopes)
+	expectedScopes := []scope{{Name: "Locals", VariablesReference: 1}, {Name: "Globals", VariablesReference: 2}}
+	if fmt.Sprint(scopes.Scopes) != fmt.Sprint(expectedScopes) {
+		t.Errorf("wrong scopes\nexpected: %v\ngot:      %v", expectedScopes, scopes.Scopes)
+	}
+
+	c.send(`{"seq":7,"type":"request","command":"variables","arguments":{"variablesReference":1}}`)
+	got = c.expect("response variables")
+
+	var locals struct{ Variables []variable }
+	decodeBody(t, got["response variables"], &locals)
+	values := make(map[string]string)
+	for _, v := range locals.Variables {
+		values[v.Name] = v.Value
+	}
+	expectedLocals := map[string]string{"a": "2", "b": "3", "sum": "5"}
+	if fmt.Sprint(values) != fmt.Sprint(expectedLocals) {
+		t.Errorf("wrong locals\nexpected: %v\ngot:      %v", expectedLocals, values)
+	}
+
+	c.send(`{"seq":8,"type":"request","command":"continue","arguments":{"threadId":1}}`)
+	got = c.expect("response continue", "event output", "event exited", "event terminated")
+
+	var output struct{ Category, Output string }
+	decodeBody(t, got["event output"], &output)
+	if output.Category != "stdout" || output.Output != "5\n" {
+		t.Errorf("wrong output event: %s", got["event output"].Body)
+	}
+
+	var exited struct{ ExitCode int }
+	decodeBody(t, got["event exited"], &exited)
+	if exited.ExitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exited.ExitCode)
+	}
+
+	c.send(`{"seq":9,"type":"request","command":"disconnect"}`)
+	got = c.expect("response disconnect")
+	if got["response disconnect"].RequestSeq != 9 {
+		t.Errorf("expected request_seq 9, got %d", got["response disconnect"].RequestSeq)
+	}
+
+	if err := <-c.done; err != nil {
+		t.Errorf("Serve returned %s", err)
+	}
+}
+
+func TestServerRequestsOutOfOrder(t *testing.T) {
+	c := startServer(t)
+
+	tests := []struct {
+		request string
+		key     string
+		message string
+	}{
+		{`{"seq":1,"type":"request","command":"setBreakpoints","arguments":{"source":{},"breakpoints":[{"line":1}]}}`, "response setBreakpoints", "Breakpoints can only be set after launch"},
+		{`{"seq":2,"type":"request","command":"stackTrace","arguments":{"threadId":1}}`, "response stackTrace", "The program is not paused"},
+		{`{"seq":3,"type":"request","command":"continue","arguments":{"threadId":1}}`, "response continue", "The program is not paused"},
+		{`{"seq":4,"type":"request","command":"restartFrame"}`, "response restartFrame", "Unsupported command 'restartFrame'"},
+	}
+
+	for _, tt := range tests {
+		c.send(tt.request)
+
+		select {
+		case msg := <-c.messages:
+			if msg.key() != tt.key || msg.Success || msg.Message != tt.message {
+				t.Errorf("expected failed %s %q, got %s success=%t %q", tt.key, tt.message, msg.key(), msg.Success, msg.Message)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", tt.key)
+		}
+	}
+
+	c.in.Close()
+	if err := <-c.done; err != nil {
+		t.Errorf("Serve returned %s", err)
+	}
+}
@@ -0,0 +1,264 @@
+// Package debug is the interactive debugger behind `monkey debug file.mn`,
+// it drives the VM through vm.SetHook.
+package debug
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/run"
+	"github.com/caelondev/monkey-compiler-go/src/vm"
+)
+
+const HELP = `Commands:
+  c, continue       run until the next breakpoint
+  s, step           step to the next line, entering calls
+  n, next           step to the next line, over calls
+  o, out            run until the current function returns
+  b, break <line>   set a breakpoint, without a line lists them
+  d, delete <line>  remove a breakpoint
+  bt, stack         show the call stack
+  l, locals         show the current function's variables
+  g, globals        show the global variables
+  p, print <expr>   evaluate an expression in the paused context
+  w, watch <expr>   add an expression shown at every pause
+  unwatch <n>       remove watch number n
+  list              show the source around the current line
+  q, quit           stop the program
+`
+
+type Debugger struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	session *Session
+	watches []string
+}
+
+// Start runs the script at path under the debugger, paused before its first line.
+func Start(path string, in io.Reader, out io.Writer) error {
+	session, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	d := &Debugger{
+		in:      bufio.NewScanner(in),
+		out:     out,
+		session: session,
+	}
+	session.OnPause = d.pause
+
+	machine := session.NewVM()
+	machine.Stdout = out
+
+	fmt.Fprintf(out, "Debugging %s, type 'help' for commands\n", path)
+
+	err = machine.Run()
+	switch {
+	case errors.Is(err, vm.ErrStopped):
+		fmt.Fprintln(out, "Program stopped")
+	case err != nil:
+		fmt.Fprint(out, run.FormatVMError(err))
+	default:
+		fmt.Fprintln(out, "Program finished")
+	}
+
+	return nil
+}
+
+// pause reads commands until one resumes the program ---
+func (d *Debugger) pause(machine *vm.VM, reason StopReason) error {
+	line := d.session.Line()
+
+	fmt.Fprintf(d.out, "Stopped at %s:%d (%s)\n", d.session.File, line, reason)
+	d.printSource(line, line)
+	d.printWatches(machine)
+
+	for {
+		fmt.Fprint(d.out, "(debug) ")
+		if !d.in.Scan() {
+			return vm.ErrStopped
+		}
+
+		command, arg, _ := strings.Cut(strings.TrimSpace(d.in.Text()), " ")
+		arg = strings.TrimSpace(arg)
+
+		switch command {
+		case "c", "continue":
+			d.session.Resume(Continue)
+			return nil
+		case "s", "step":
+			d.session.Resume(StepIn)
+			return nil
+		case "n", "next":
+			d.session.Resume(StepOver)
+			return nil
+		case "o", "out":
+			d.session.Resume(StepOut)
+			return nil
+
+		case "b", "break":
+			d.setBreakpoint(arg)
+		case "d", "delete":
+			d.deleteBreakpoint(arg)
+
+		case "bt", "stack":
+			d.printStack(machine)
+		case "l", "locals":
+			d.printLocals(machine)
+		case "g", "globals":
+			d.printGlobals(machine)
+
+		case "p", "print":
+			d.printExpression(machine, arg)
+		case "w", "watch":
+			d.addWatch(machine, arg)
+		case "unwatch":
+			d.removeWatch(arg)
+
+		case "list":
+			d.printSource(line-3, line+3)
+		case "q", "quit":
+			return vm.ErrStopped
+		case "h", "help":
+			io.WriteString(d.out, HELP)
+		case "":
+
+		default:
+			fmt.Fprintf(d.out, "Unknown command '%s', type 'help' for commands\n", command)
+		}
+	}
+}
+
+func (d *Debugger) setBreakpoint(arg string) {
+	if arg == "" {
+		lines := d.session.Breakpoints()
+		if len(lines) == 0 {
+			fmt.Fprintln(d.out, "No breakpoints")
+		}
+		for _, line := range lines {
+			fmt.Fprintf(d.out, "Breakpoint at %s:%d\n", d.session.File, line)
+		}
+		return
+	}
+
+	line, ok := d.parseLine(arg)
+	if !ok {
+		return
+	}
+
+	d.session.SetBreakpoint(line)
+	fmt.Fprintf(d.out, "Breakpoint set at %s:%d\n", d.session.File, line)
+}
+
+func (d *Debugger) deleteBreakpoint(arg string) {
+	line, ok := d.parseLine(arg)
+	if !ok {
+		return
+	}
+
+	if !d.session.ClearBreakpoint(line) {
+		fmt.Fprintf(d.out, "No breakpoint at line %d\n", line)
+		return
+	}
+
+	fmt.Fprintf(d.out, "Breakpoint removed from %s:%d\n", d.session.File, line)
+}
+
+func (d *Debugger) parseLine(arg string) (int, bool) {
+	line, err := strconv.Atoi(arg)
+	if err != nil || line < 1 || line > len(d.session.Source) {
+		fmt.Fprintf(d.out, "Expected a line number between 1 and %d\n", len(d.session.Source))
+		return 0, false
+	}
+
+	return line, true
+}
+
+func (d *Debugger) printSource(from, to int) {
+	for line := max(from, 1); line <= min(to, len(d.session.Source)); line++ {
+		marker := "  "
+		if line == d.session.Line() {
+			marker = "->"
+		} else if d.session.HasBreakpoint(line) {
+			marker = " *"
+		}
+
+		fmt.Fprintf(d.out, "%s %4d | %s\n", marker, line, d.session.Source[line-1])
+	}
+}
+
+func (d *Debugger) printStack(machine *vm.VM) {
+	for i, frame := range machine.DebugFrames() {
+		fmt.Fprintf(d.out, "#%d %s at %s:%d:%d\n", i, FrameName(frame), d.session.File, frame.Line, frame.Column)
+	}
+}
+
+func (d *Debugger) printLocals(machine *vm.VM) {
+	names, values := d.session.Locals(machine.DebugFrames()[0])
+	if len(names) == 0 {
+		fmt.Fprintln(d.out, "No local variables")
+	}
+
+	for i, name := range names {
+		fmt.Fprintf(d.out, "%s = %s\n", name, values[i].Inspect())
+	}
+}
+
+func (d *Debugger) printGlobals(machine *vm.VM) {
+	names, values := d.session.Globals(machine)
+	if len(names) == 0 {
+		fmt.Fprintln(d.out, "No global variables")
+	}
+
+	for i, name := range names {
+		fmt.Fprintf(d.out, "%s = %s\n", name, values[i].Inspect())
+	}
+}
+
+func (d *Debugger) printExpression(machine *vm.VM, source string) {
+	result, err := d.session.Evaluate(machine, machine.DebugFrames()[0], source, d.out)
+	if err != nil {
+		fmt.Fprintf(d.out, "Error: %s\n", err)
+		return
+	}
+
+	fmt.Fprintln(d.out, result.Inspect())
+}
+
+func (d *Debugger) addWatch(machine *vm.VM, source string) {
+	if source == "" {
+		d.printWatches(machine)
+		return
+	}
+
+	d.watches = append(d.watches, source)
+	fmt.Fprintf(d.out, "Watch %d: %s\n", len(d.watches), source)
+}
+
+func (d *Debugger) removeWatch(arg string) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(d.watches) {
+		fmt.Fprintf(d.out, "Expected a watch number between 1 and %d\n", len(d.watches))
+		return
+	}
+
+	d.watches = append(d.watches[:n-1], d.watches[n:]...)
+}
+
+func (d *Debugger) printWatches(machine *vm.VM) {
+	for i, source := range d.watches {
+		result, err := d.session.Evaluate(machine, machine.DebugFrames()[0], source, d.out)
+		if err != nil {
+			fmt.Fprintf(d.out, "  %d: %s = <error: %s>\n", i+1, source, err)
+			continue
+		}
+
+		fmt.Fprintf(d.out, "  %d: %s = %s\n", i+1, source, result.Inspect())
+	}
+}
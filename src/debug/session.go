@@ -0,0 +1,345 @@
+package debug
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/evaluation"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
+	"github.com/caelondev/monkey-compiler-go/src/vm"
+)
+
+type StepMode int
+
+const (
+	Continue StepMode = iota
+	StepIn
+	StepOver
+	StepOut
+)
+
+// StopReason names match the Debug Adapter Protocol's ---
+type StopReason string
+
+const (
+	StopEntry      StopReason = "entry"
+	StopStep       StopReason = "step"
+	StopBreakpoint StopReason = "breakpoint"
+)
+
+// Session is one program under a debugger, front ends (the CLI, DAP) ---
+// drive it through Resume and react to pauses in OnPause ---
+type Session struct {
+	File     string
+	Source   []string
+	Compiler *compiler.Compiler
+
+	// Called on the VM's goroutine whenever execution stops, ---
+	// the program continues once it returns (or ends on vm.ErrStopped) ---
+	OnPause func(machine *vm.VM, reason StopReason) error
+
+	mode      StepMode
+	stepDepth int
+	stepLine  int
+	started   bool
+
+	// Position of the previous instruction, we only stop ---
+	// when execution moves to another line or call ---
+	prevDepth int
+	prevLine  int
+
+	stopped atomic.Bool
+
+	// Front ends may change breakpoints while the program runs ---
+	mu          sync.Mutex
+	breakpoints map[int]bool
+}
+
+// Load parses and compiles the script at path, paused on entry by default.
+func Load(path string) (*Session, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(strings.Join(p.Errors(), "\n"))
+	}
+
+	program, expandErr := evaluation.Expand(program)
+	if expandErr != nil {
+		return nil, errors.New(expandErr.Inspect())
+	}
+
+	comp := compiler.New()
+	comp.File = path
+	err = comp.Compile(program)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		File:        path,
+		Source:      strings.Split(string(source), "\n"),
+		Compiler:    comp,
+		breakpoints: make(map[int]bool),
+		mode:        StepIn,
+	}, nil
+}
+
+// NewVM creates a VM for the program with the session's hook installed.
+func (s *Session) NewVM() *vm.VM {
+	machine := vm.New(s.Compiler.Bytecode())
+	machine.SetHook(s.hook)
+	return machine
+}
+
+// Resume sets how far the program runs before it's paused again, ---
+// relative to where it last stopped ---
+func (s *Session) Resume(mode StepMode) {
+	s.mode = mode
+	s.stepDepth, s.stepLine = s.prevDepth, s.prevLine
+}
+
+// Stop ends the program at its next instruction, safe to call from any goroutine.
+func (s *Session) Stop() {
+	s.stopped.Store(true)
+}
+
+// Line is where the program last stopped ---
+func (s *Session) Line() int {
+	return s.prevLine
+}
+
+func (s *Session) SetBreakpoint(line int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.breakpoints[line] = true
+}
+
+// ClearBreakpoint reports whether there was a breakpoint on line ---
+func (s *Session) ClearBreakpoint(line int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists := s.breakpoints[line]
+	delete(s.breakpoints, line)
+	return exists
+}
+
+// SetBreakpoints replaces every breakpoint with lines ---
+func (s *Session) SetBreakpoints(lines []int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.breakpoints)
+	for _, line := range lines {
+		s.breakpoints[line] = true
+	}
+}
+
+func (s *Session) HasBreakpoint(line int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.breakpoints[line]
+}
+
+// Breakpoints returns the breakpoint lines in order ---
+func (s *Session) Breakpoints() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := make([]int, 0, len(s.breakpoints))
+	for line := range s.breakpoints {
+		lines = append(lines, line)
+	}
+
+	sort.Ints(lines)
+	return lines
+}
+
+// HasCode reports whether any instruction was compiled from line ---
+func (s *Session) HasCode(line int) bool {
+	bytecode := s.Compiler.Bytecode()
+	if hasLine(bytecode.Lines, line) {
+		return true
+	}
+
+	for _, constant := range bytecode.Constants {
+		if fn, ok := constant.(*object.CompiledFunction); ok && hasLine(fn.Lines, line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasLine(lines []code.SourcePos, line int) bool {
+	for _, pos := range lines {
+		if pos.Line == line {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Session) hook(machine *vm.VM) error {
+	if s.stopped.Load() {
+		return vm.ErrStopped
+	}
+
+	depth, line := machine.Depth(), machine.Line()
+
+	moved := depth != s.prevDepth || line != s.prevLine
+	s.prevDepth, s.prevLine = depth, line
+
+	if line == 0 || !moved {
+		return nil
+	}
+
+	reason, stop := s.shouldStop(depth, line)
+	if !stop || s.OnPause == nil {
+		return nil
+	}
+
+	err := s.OnPause(machine, reason)
+	if err == nil && s.stopped.Load() {
+		return vm.ErrStopped
+	}
+
+	return err
+}
+
+func (s *Session) shouldStop(depth, line int) (StopReason, bool) {
+	if !s.started {
+		s.started = true
+		if s.mode == StepIn {
+			return StopEntry, true
+		}
+	}
+
+	if s.HasBreakpoint(line) {
+		return StopBreakpoint, true
+	}
+
+	switch s.mode {
+	case StepIn:
+		return StopStep, depth != s.stepDepth || line != s.stepLine
+	case StepOver:
+		return StopStep, depth < s.stepDepth || (depth == s.stepDepth && line != s.stepLine)
+	case StepOut:
+		return StopStep, depth < s.stepDepth
+	}
+
+	return "", false
+}
+
+// Locals pairs the frame's slots with the names from its symbol table ---
+func (s *Session) Locals(frame vm.DebugFrame) ([]string, []object.Object) {
+	names := make([]string, 0)
+	values := make([]object.Object, 0)
+
+	table, ok := s.Compiler.FunctionSymbols(frame.Function)
+	if frame.IsMain || !ok {
+		return names, values
+	}
+
+	for slot, name := range table.DefinedNames() {
+		if name == "" || slot >= len(frame.Locals) || frame.Locals[slot] == nil {
+			continue
+		}
+
+		names = append(names, name)
+		values = append(values, frame.Locals[slot])
+	}
+
+	for i, symbol := range table.FreeSymbols {
+		if i < len(frame.Free) {
+			names = append(names, symbol.Name)
+			values = append(values, frame.Free[i])
+		}
+	}
+
+	return names, values
+}
+
+// Globals leaves out the ones not assigned yet ---
+func (s *Session) Globals(machine *vm.VM) ([]string, []object.Object) {
+	names := make([]string, 0)
+	values := make([]object.Object, 0)
+
+	for slot, name := range s.Compiler.SymbolTable().DefinedNames() {
+		value := machine.GetGlobal(slot)
+		if name == "" || value == nil {
+			continue
+		}
+
+		names = append(names, name)
+		values = append(values, value)
+	}
+
+	return names, values
+}
+
+// Evaluate runs source on the tree-walking evaluator over a copy of ---
+// the variables visible in frame, script functions can't be called from it ---
+func (s *Session) Evaluate(machine *vm.VM, frame vm.DebugFrame, source string, stdout io.Writer) (object.Object, error) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(p.Errors()[0])
+	}
+
+	evaluator := evaluation.New()
+	evaluator.Stdout = stdout
+
+	globalEnv := object.NewEnvironment(nil)
+	evaluator.InitializeNativeFunctions(globalEnv)
+
+	names, values := s.Globals(machine)
+	for i, name := range names {
+		globalEnv.Declare(name, values[i])
+	}
+
+	env := object.NewEnvironment(globalEnv)
+	names, values = s.Locals(frame)
+	for i, name := range names {
+		env.Declare(name, values[i])
+	}
+
+	result := evaluator.Evaluate(program, env)
+	if errObj, ok := result.(*object.Error); ok {
+		return nil, errors.New(errObj.Message)
+	}
+
+	if result == nil {
+		return object.NIL, nil
+	}
+
+	return result, nil
+}
+
+func FrameName(frame vm.DebugFrame) string {
+	switch {
+	case frame.IsMain:
+		return "<main>"
+	case frame.Function.Name == "":
+		return "<anonymous>"
+	default:
+		return frame.Function.Name
+	}
+}
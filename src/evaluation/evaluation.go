@@ -1,8 +1,13 @@
 package evaluation
 
 import (
+	"bufio"
+	"io"
+	"os"
+
 	"github.com/caelondev/monkey-compiler-go/src/ast"
 	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/scheduler"
 )
 
 type Evaluator struct {
@@ -10,11 +15,40 @@ type Evaluator struct {
 	column       uint
 	callDepth    int
 	MaxCallDepth int
+
+	// Stops a run after this many function calls and loop iterations, ---
+	// 0 is no limit ---
+	MaxSteps int
+	steps    int
+
+	// Script name recorded in stack traces ---
+	File string
+
+	// Where print() writes and prompt() reads ---
+	Stdout io.Writer
+	Stdin  io.Reader
+
+	// Functions registered by a Go host ---
+	Registry *object.Registry
+
+	// Runs spawned tasks, one per Evaluator ---
+	tasks *scheduler.Scheduler
+
+	generators     []*generatorRun // Generators currently running, innermost last ---
+	liveGenerators map[*object.Generator]struct{}
+
+	stdinScanner *bufio.Scanner
+	scannerInput io.Reader
 }
 
 func New() Evaluator {
 	return Evaluator{
 		MaxCallDepth: 10_000,
+		Stdout:       os.Stdout,
+		Stdin:        os.Stdin,
+		tasks:        scheduler.New(),
+
+		liveGenerators: make(map[*object.Generator]struct{}),
 	}
 }
 
@@ -28,9 +62,19 @@ func (e *Evaluator) Evaluate(node ast.Node, env *object.Environment) object.Obje
 
 	switch node := node.(type) {
 	case *ast.Program:
-		return e.evaluateProgram(node.Statements, env)
+		result := e.evaluateProgram(node.Statements, env)
+		// Tasks still alive when the program ends are aborted, like goroutines ---
+		e.tasks.Shutdown()
+		e.closeGenerators()
+		return result
 	case *ast.NumberLiteral:
 		return &object.Number{Value: node.Value}
+	case *ast.IntegerLiteral:
+		return &object.Integer{Value: node.Value}
+	case *ast.BigIntLiteral:
+		return &object.BigInt{Value: node.Value}
+	case *ast.DecimalLiteral:
+		return &object.Decimal{Unscaled: node.Unscaled, Scale: node.Scale}
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 	case *ast.NilLiteral:
@@ -64,13 +108,24 @@ func (e *Evaluator) Evaluate(node ast.Node, env *object.Environment) object.Obje
 	case *ast.BatchAssignmentStatement:
 		return e.evaluateBatchAssignmentStatement(node, env)
 	case *ast.FunctionLiteral:
-		return &object.Function{Parameters: node.Parameters, Body: node.Body, Scope: env}
+		if err := e.checkDuplicates(ast.ParameterNames(node.Parameters, node.Patterns)); err != nil {
+			return err
+		}
+		return &object.Function{Parameters: node.Parameters, Patterns: node.Patterns, Body: node.Body, Scope: env, IsGenerator: node.IsGenerator}
 	case *ast.FunctionDeclarationStatement:
 		return e.evaluateFunctionDeclaration(node, env)
+	case *ast.MacroLiteral:
+		return e.throwErr(
+			node,
+			"Macros are expanded before the program runs, define them with `var <name> = macro(...) { ... };` at the top level",
+			"Macros can only be defined at the top level",
+		)
 	case *ast.CallExpression:
 		return e.evaluateCallExpression(node, env)
 	case *ast.ArrayLiteral:
 		return e.evaluateArrayLiteral(node, env)
+	case *ast.InterpolatedString:
+		return e.evaluateInterpolatedString(node, env)
 	case *ast.AbsoluteExpression:
 		return e.evaluateAbsoluteExpression(node, env)
 	case *ast.IndexExpression:
@@ -81,6 +136,18 @@ func (e *Evaluator) Evaluate(node ast.Node, env *object.Environment) object.Obje
 		return e.evaluateIndexSliceExpression(node, env)
 	case *ast.HashLiteral:
 		return e.evaluateHashLiteral(node, env)
+	case *ast.SpawnExpression:
+		return e.evaluateSpawnExpression(node, env)
+	case *ast.AwaitExpression:
+		return e.evaluateAwaitExpression(node, env)
+	case *ast.YieldExpression:
+		return e.evaluateYieldExpression(node, env)
+	case *ast.ForInStatement:
+		return e.evaluateForInStatement(node, env)
+	case *ast.TryStatement:
+		return e.evaluateTryStatement(node, env)
+	case *ast.ThrowStatement:
+		return e.evaluateThrowStatement(node, env)
 
 	default:
 		return e.throwErr(
@@ -102,7 +169,7 @@ func (e *Evaluator) evaluateProgram(statements []ast.Statement, env *object.Envi
 		case *object.ReturnValue:
 			return result.Value
 		case *object.Error:
-			return result
+			return e.traceError(result, env)
 		}
 	}
 
@@ -120,11 +187,27 @@ func (e *Evaluator) InitializeNativeFunctions(env *object.Environment) {
 	e.registerNativeFn(env, "time", e.NATIVE_TIME_FUNCTION)
 	e.registerNativeFn(env, "to_string", e.NATIVE_TO_STRING_FUNCTION)
 	e.registerNativeFn(env, "to_number", e.NATIVE_TO_NUMBER_FUNCTION)
+	e.registerNativeFn(env, "to_integer", e.NATIVE_TO_INTEGER_FUNCTION)
+	e.registerNativeFn(env, "bigint", e.NATIVE_BIGINT_FUNCTION)
+	e.registerNativeFn(env, "decimal", e.NATIVE_DECIMAL_FUNCTION)
 	e.registerNativeFn(env, "type", e.NATIVE_TYPE_FUNCTION)
 	e.registerNativeFn(env, "is_NaN", e.NATIVE_IS_NAN_FUNCTION)
 	e.registerNativeFn(env, "is_Inf", e.NATIVE_IS_INF_FUNCTION)
 	e.registerNativeFn(env, "is_nil", e.NATIVE_IS_NIL_FUNCTION)
 	e.registerNativeFn(env, "random", e.NATIVE_RANDOM_FUNCTION)
+	e.registerNativeFn(env, "next", e.NATIVE_NEXT_FUNCTION)
+	e.registerNativeFn(env, "is_done", e.NATIVE_IS_DONE_FUNCTION)
+	e.registerNativeFn(env, "div", e.NATIVE_DIV_FUNCTION)
+	e.registerNativeFn(env, "mod", e.NATIVE_MOD_FUNCTION)
+
+	for name, fn := range e.tasks.Builtins() {
+		e.registerSchedulerFn(env, name, fn)
+	}
+
+	for _, name := range e.Registry.Names() {
+		fn, _ := e.Registry.Lookup(name)
+		env.Declare(name, fn)
+	}
 }
 
 func (e *Evaluator) registerNativeFn(env *object.Environment, name string, fn object.NativeFunctionFn) {
@@ -1,7 +1,8 @@
 package evaluation
 
 import (
-	"math"
+	"fmt"
+	"strings"
 
 	"github.com/caelondev/monkey-compiler-go/src/ast"
 	"github.com/caelondev/monkey-compiler-go/src/object"
@@ -54,6 +55,12 @@ func (e *Evaluator) evaluateUnaryExpression(node *ast.UnaryExpression, env *obje
 		return e.evaluateNotExpression(right)
 	case token.MINUS:
 		return e.evaluateNegationExpression(node, right)
+	case token.TILDE:
+		result, err := object.BitwiseNot(right)
+		if err != nil {
+			return e.throwErr(node, integerOperatorHint, "%s", err)
+		}
+		return result
 	default:
 		return e.throwErr(
 			node,
@@ -67,9 +74,17 @@ func (e *Evaluator) evaluateUnaryExpression(node *ast.UnaryExpression, env *obje
 func (e *Evaluator) evaluateNegationExpression(node *ast.UnaryExpression, right object.Object) object.Object {
 	switch obj := right.(type) {
 	case *object.Infinity:
-		return infinityWithSign(-obj.Sign)
+		return object.InfinityWithSign(-obj.Sign)
 	case *object.Number:
 		return &object.Number{Value: -obj.Value}
+	case *object.Integer:
+		negated, err := object.Negate(obj.Value)
+		if err != nil {
+			return e.throwErr(node, integerOverflowHint, "%s", err)
+		}
+		return negated
+	case *object.BigInt, *object.Decimal:
+		return object.NegateBig(obj)
 	case *object.NaN:
 		return object.NAN
 	default:
@@ -82,12 +97,33 @@ func (e *Evaluator) evaluateNegationExpression(node *ast.UnaryExpression, right
 }
 
 func (e *Evaluator) evaluateAssignmentExpression(node *ast.AssignmentExpression, env *object.Environment) object.Object {
-	newValue := e.Evaluate(node.NewValue, env)
 	assignee := node.Assignee.TokenLiteral()
 
-	if _, ok := env.Get(assignee); ok {
-		value, _ := env.Set(assignee, newValue)
-		return value
+	// x += v reads x before v is evaluated ---
+	var current object.Object
+	if node.Operator.Type != "" {
+		current = e.Evaluate(node.Assignee, env)
+		if isError(current) {
+			return current
+		}
+	}
+
+	newValue := e.Evaluate(node.NewValue, env)
+	if isError(newValue) {
+		return newValue
+	}
+
+	newValue = e.evaluateCompound(node.Token, node.Operator, node.Assignee, node.NewValue, current, newValue)
+	if isError(newValue) {
+		return newValue
+	}
+
+	if env.Assign(assignee, newValue) {
+		// x++ and x-- are postfix, they give the value before ---
+		if ast.IsIncrement(node.Token.Type) {
+			return current
+		}
+		return newValue
 	}
 
 	return e.throwErr(
@@ -97,6 +133,21 @@ func (e *Evaluator) evaluateAssignmentExpression(node *ast.AssignmentExpression,
 	)
 }
 
+// evaluateCompound is `current operator value` for a compound ---
+// assignment, the value itself for a plain = ---
+func (e *Evaluator) evaluateCompound(
+	tok, operator token.Token,
+	assignee, valueNode ast.Expression,
+	current, value object.Object,
+) object.Object {
+	if operator.Type == "" {
+		return value
+	}
+
+	node := &ast.BinaryExpression{Token: tok, Operator: operator, Left: assignee, Right: valueNode}
+	return e.unwrapReturnValue(e.evaluateBinaryOperation(node, current, value))
+}
+
 func (e *Evaluator) evaluateTernaryExpression(node *ast.TernaryExpression, env *object.Environment) object.Object {
 	condition := e.Evaluate(node.Condition, env)
 	if isError(condition) {
@@ -135,30 +186,45 @@ func (e *Evaluator) evaluateBinaryExpression(node *ast.BinaryExpression, env *ob
 		return right
 	}
 
-	// Handle NaN operands ---
-	if left.Type() == object.NAN_OBJECT || right.Type() == object.NAN_OBJECT {
-		switch node.Operator.Type {
-		case token.EQUAL, token.LESS, token.GREATER, token.LESS_EQUAL, token.GREATER_EQUAL:
-			return object.FALSE
-		case token.NOT_EQUAL:
-			return object.TRUE
-		default:
-			return object.NAN
+	return e.evaluateBinaryOperation(node, left, right)
+}
+
+// evaluateBinaryOperation works out node's operator on operands ---
+// that are evaluated already ---
+func (e *Evaluator) evaluateBinaryOperation(node *ast.BinaryExpression, left, right object.Object) object.Object {
+	// These define NaN, Inf and every other operand themselves ---
+	if object.IsIntegerOperator(node.Operator.Type) {
+		result, err := object.IntegerOperation(node.Operator.Type, left, right)
+		if err != nil {
+			return e.throwErr(node, integerOperatorHint, "%s", err)
 		}
+		return result
 	}
 
-	switch {
-	case left.Type() == object.INFINITY_OBJECT && right.Type() == object.INFINITY_OBJECT:
-		return evalInfInf(node.Operator.Type, left.(*object.Infinity), right.(*object.Infinity))
+	// NaN swallows every operand, see object.FloatOperation ---
+	if left.Type() == object.NAN_OBJECT || right.Type() == object.NAN_OBJECT {
+		return object.FloatOperation(node.Operator.Type, left, right)
+	}
 
-	case left.Type() == object.INFINITY_OBJECT && right.Type() == object.NUMBER_OBJECT:
-		return evalInfNum(node.Operator.Type, left.(*object.Infinity), right.(*object.Number))
+	if object.IsBig(left) || object.IsBig(right) {
+		if result := e.evaluateBigBinaryExpression(node, left, right); result != nil {
+			return result
+		}
+	}
 
-	case left.Type() == object.NUMBER_OBJECT && right.Type() == object.INFINITY_OBJECT:
-		return evalNumInf(node.Operator.Type, left.(*object.Number), right.(*object.Infinity))
+	if left.Type() == object.INTEGER_OBJECT || right.Type() == object.INTEGER_OBJECT {
+		if result := e.evaluateIntegerBinaryExpression(node, left, right); result != nil {
+			return result
+		}
+		left, right = promoteInteger(left, right), promoteInteger(right, left)
+	}
 
-	case left.Type() == object.NUMBER_OBJECT && right.Type() == object.NUMBER_OBJECT:
-		return e.evaluateNumericBinaryExpression(node, left, right)
+	// Numbers, Infinity and NaN behave the same in the VM ---
+	if result := object.FloatOperation(node.Operator.Type, left, right); result != nil {
+		return result
+	}
+
+	switch {
 	case left.Type() == object.STRING_OBJECT && right.Type() == object.STRING_OBJECT:
 		return e.evaluateStringBinaryExpression(node, left, right)
 	case left.Type() == object.BOOLEAN_OBJECT && right.Type() == object.BOOLEAN_OBJECT:
@@ -175,6 +241,100 @@ func (e *Evaluator) evaluateBinaryExpression(node *ast.BinaryExpression, env *ob
 	)
 }
 
+const integerOverflowHint = "This error occurs when an integer result is too large for 64 bits.\nMake one operand a BigInt, like 2n, to keep it exact, or a float, like 2.0, to work with floats instead"
+
+const integerOperatorHint = "This error occurs when %, ~/ or a bitwise operator gets an operand it isn't defined for, or << overflows.\nBitwise operators take integers, BigInts, whole floats and (except << and >>) booleans, 1n << 64 stays exact"
+
+const bigNumberHint = "This error occurs when a BigInt or a Decimal can't be used this way.\nBigInts and Decimals only mix with integers, convert floats with bigint(), decimal() or to_number()"
+
+// evaluateBigBinaryExpression handles BigInts and Decimals, it's nil ---
+// when the other side isn't a number at all ---
+func (e *Evaluator) evaluateBigBinaryExpression(node *ast.BinaryExpression, left, right object.Object) object.Object {
+	switch node.Operator.Type {
+	case token.EQUAL, token.NOT_EQUAL, token.LESS, token.GREATER, token.LESS_EQUAL, token.GREATER_EQUAL:
+		order, ok := object.CompareNumbers(left, right)
+		if !ok {
+			return nil
+		}
+		return e.evaluateToObjectBoolean(compareResult(node.Operator.Type, order))
+	}
+
+	if !object.IsNumber(left) || !object.IsNumber(right) {
+		return nil
+	}
+
+	result, err := object.BigArithmetic(node.Operator.Type, left, right)
+	if err != nil {
+		return e.throwErr(node, bigNumberHint, "%s", err)
+	}
+
+	return result
+}
+
+// evaluateIntegerBinaryExpression handles integers between themselves ---
+// and compares them to floats exactly. It's nil when the operation ---
+// has to be done with floats instead ---
+func (e *Evaluator) evaluateIntegerBinaryExpression(node *ast.BinaryExpression, left, right object.Object) object.Object {
+	switch node.Operator.Type {
+	case token.EQUAL, token.NOT_EQUAL, token.LESS, token.GREATER, token.LESS_EQUAL, token.GREATER_EQUAL:
+		order, ok := object.CompareNumbers(left, right)
+		if !ok {
+			return nil
+		}
+		return e.evaluateToObjectBoolean(compareResult(node.Operator.Type, order))
+	}
+
+	l, lok := left.(*object.Integer)
+	r, rok := right.(*object.Integer)
+	if !lok || !rok {
+		return nil
+	}
+
+	result, err := object.IntegerArithmetic(node.Operator.Type, l.Value, r.Value)
+	if err != nil {
+		return e.throwErr(node, integerOverflowHint, "%s", err)
+	}
+	if result == nil {
+		return nil
+	}
+
+	return result
+}
+
+// compareResult applies a comparison operator to a CompareNumbers order ---
+func compareResult(operator token.TokenType, order int) bool {
+	switch operator {
+	case token.EQUAL:
+		return order == 0
+	case token.NOT_EQUAL:
+		return order != 0
+	case token.LESS:
+		return order < 0
+	case token.GREATER:
+		return order > 0
+	case token.LESS_EQUAL:
+		return order <= 0
+	default:
+		return order >= 0
+	}
+}
+
+// promoteInteger turns obj into a float when it's an Integer next ---
+// to one of the other numeric types ---
+func promoteInteger(obj, other object.Object) object.Object {
+	integer, ok := obj.(*object.Integer)
+	if !ok {
+		return obj
+	}
+
+	switch other.Type() {
+	case object.INTEGER_OBJECT, object.NUMBER_OBJECT, object.INFINITY_OBJECT, object.NAN_OBJECT:
+		return &object.Number{Value: float64(integer.Value)}
+	}
+
+	return obj
+}
+
 func (e *Evaluator) evaluateBooleanBinaryExpression(node *ast.BinaryExpression, left, right object.Object) object.Object {
 	switch node.Operator.Type {
 	case token.EQUAL:
@@ -229,62 +389,22 @@ func (e *Evaluator) evaluateStringBinaryExpression(node *ast.BinaryExpression, l
 	return &object.String{Value: result}
 }
 
-func (e *Evaluator) evaluateNumericBinaryExpression(
-	node *ast.BinaryExpression,
-	left, right object.Object,
-) object.Object {
-	l := left.(*object.Number).Value
-	r := right.(*object.Number).Value
-	var result float64
-
-	switch node.Operator.Type {
-	case token.PLUS:
-		result = l + r
-	case token.MINUS:
-		result = l - r
-	case token.STAR:
-		result = l * r
-	case token.SLASH:
-		result = l / r
-	case token.CARET:
-		result = math.Pow(l, r)
-
-	case token.LESS:
-		return e.evaluateToObjectBoolean(l < r)
-	case token.GREATER:
-		return e.evaluateToObjectBoolean(l > r)
-	case token.LESS_EQUAL:
-		return e.evaluateToObjectBoolean(l <= r)
-	case token.GREATER_EQUAL:
-		return e.evaluateToObjectBoolean(l >= r)
-	case token.EQUAL:
-		return e.evaluateToObjectBoolean(l == r)
-	case token.NOT_EQUAL:
-		return e.evaluateToObjectBoolean(l != r)
-
-	default:
-		return e.throwErr(
-			node,
-			"This error occurs when an unregistered binary operator is used.\nThis should only appear during language development.",
-			"Unknown binary operator: '%v'",
-			node.Operator.Type,
-		)
+func (e *Evaluator) evaluateCallExpression(node *ast.CallExpression, env *object.Environment) object.Object {
+	if IsCallTo(node, "quote") {
+		return e.quote(node, env)
 	}
 
-	if math.IsNaN(result) {
-		return object.NAN
-	}
-	if math.IsInf(result, 1) {
-		return object.INFINITY
-	}
-	if math.IsInf(result, -1) {
-		return object.NEG_INFINITY
+	fn, args := e.resolveCall(node, env)
+	if fn == nil {
+		return args[0]
 	}
 
-	return &object.Number{Value: result}
+	return e.applyFunction(node.Function, node, fn, args, env.CallFrame())
 }
 
-func (e *Evaluator) evaluateCallExpression(node *ast.CallExpression, env *object.Environment) object.Object {
+// resolveCall evaluates the callee and arguments of a call, on error ---
+// fn is nil and the error is the only element of args ---
+func (e *Evaluator) resolveCall(node *ast.CallExpression, env *object.Environment) (object.Object, []object.Object) {
 	var fn object.Object
 
 	if ident, ok := node.Function.(*ast.Identifier); ok {
@@ -293,38 +413,31 @@ func (e *Evaluator) evaluateCallExpression(node *ast.CallExpression, env *object
 		foundFn, exists := env.Get(fnName)
 
 		if !exists {
-			return e.throwErr(
+			return nil, []object.Object{e.throwErr(
 				node.Function,
 				"This error occurs when an undeclared function was called",
 				"Cannot call function '%s', as it is undefined",
 				fnName,
-			)
+			)}
 		}
 
 		fn = foundFn
 	} else if fnLit, ok := node.Function.(*ast.FunctionLiteral); ok {
-		args := e.evaluateExpressions(node.Arguments, env)
-
-		if len(args) == 1 && isError(args[0]) {
-			return args[0]
-		}
-
-		fnLitObj := e.Evaluate(fnLit, env)
-		fn = fnLitObj
+		fn = e.Evaluate(fnLit, env)
 	} else {
-		return e.throwErr(
+		return nil, []object.Object{e.throwErr(
 			node.Function,
 			"This error occurs when trying to call an invalid expression as a function",
 			"Unexpected call to an invalid expression",
-		)
+		)}
 	}
 
 	args := e.evaluateExpressions(node.Arguments, env)
 	if len(args) == 1 && isError(args[0]) {
-		return args[0]
+		return nil, args
 	}
 
-	return e.applyFunction(node.Function, node, fn, args)
+	return fn, args
 }
 
 func (e *Evaluator) applyFunction(
@@ -332,9 +445,14 @@ func (e *Evaluator) applyFunction(
 	callNode *ast.CallExpression,
 	function object.Object,
 	args []object.Object,
+	caller *object.CallFrame,
 ) object.Object {
 	switch fn := function.(type) {
 	case *object.Function:
+		if err := e.step(callNode); err != nil {
+			return err
+		}
+
 		if e.callDepth >= e.MaxCallDepth {
 			return e.throwErr(
 				fnNode,
@@ -354,12 +472,24 @@ func (e *Evaluator) applyFunction(
 			)
 		}
 
+		frame := &object.CallFrame{
+			Function: functionName(fn),
+			Line:     callNode.GetLine(),
+			Column:   callNode.GetColumn(),
+			Caller:   caller,
+		}
+
+		// The body only runs once the generator is resumed ---
+		if fn.IsGenerator {
+			return e.newGenerator(fn, args, frame)
+		}
+
 		e.callDepth++
 		defer func() { e.callDepth-- }()
 
-		extendedEnv := e.extendFunctionEnv(fn, args)
-		evaluated := e.Evaluate(fn.Body, extendedEnv)
-		return e.unwrapReturnValue(evaluated)
+		extendedEnv := e.extendFunctionEnv(fn, args, frame)
+		evaluated := e.runBody(fn, extendedEnv)
+		return e.traceError(e.unwrapReturnValue(evaluated), extendedEnv)
 
 	case *object.NativeFunction:
 		// Native functions still contributes to call stack depth
@@ -369,6 +499,23 @@ func (e *Evaluator) applyFunction(
 
 		return fn.Fn(callNode, args)
 
+	case *object.HostFunction:
+		e.callDepth++
+		defer func() { e.callDepth-- }()
+
+		result, err := fn.Call(args...)
+		if err != nil {
+			return e.throwErr(
+				callNode,
+				"This error occurs when a function provided by the host program fails",
+				"%s: %s",
+				fn.Name,
+				err,
+			)
+		}
+
+		return result
+
 	default:
 		return e.throwErr(
 			fnNode,
@@ -379,9 +526,39 @@ func (e *Evaluator) applyFunction(
 	}
 }
 
-func (e *Evaluator) extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+// CallFunction invokes a function object from outside the tree walk (e.g. a Go host).
+// A synthetic call node is built so errors still carry a readable position ---
+func (e *Evaluator) CallFunction(function object.Object, args []object.Object) object.Object {
+	name := "<host>"
+	if fn, ok := function.(*object.Function); ok && fn.Name != nil {
+		name = fn.Name.Value
+	}
+
+	callee := &ast.Identifier{Token: token.Token{Type: token.IDENTIFIER, Literal: name}, Value: name}
+	callNode := &ast.CallExpression{
+		Token:     token.Token{Type: token.LEFT_PARENTHESIS, Literal: "("},
+		Function:  callee,
+		Arguments: make([]ast.Expression, len(args)),
+	}
+
+	// Natives point errors at their argument nodes ---
+	for i := range args {
+		argName := fmt.Sprintf("arg%d", i)
+		callNode.Arguments[i] = &ast.Identifier{Token: token.Token{Type: token.IDENTIFIER, Literal: argName}, Value: argName}
+	}
+
+	result := e.applyFunction(callee, callNode, function, args, nil)
+	if result == nil {
+		return object.NIL
+	}
+
+	return result
+}
+
+func (e *Evaluator) extendFunctionEnv(fn *object.Function, args []object.Object, frame *object.CallFrame) *object.Environment {
 	// fn env is the outer env (for closure) ---
 	env := object.NewEnvironment(fn.Scope)
+	env.SetCallFrame(frame)
 
 	for idx, param := range fn.Parameters {
 		// Assign args to params
@@ -396,6 +573,22 @@ func (e *Evaluator) evaluateArrayLiteral(node *ast.ArrayLiteral, env *object.Env
 	return &object.Array{Elements: exprs}
 }
 
+// Each part shows the way print would show it ---
+func (e *Evaluator) evaluateInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	for _, part := range node.Parts {
+		value := e.Evaluate(part, env)
+		if isError(value) {
+			return value
+		}
+
+		out.WriteString(object.Display(value))
+	}
+
+	return &object.String{Value: out.String()}
+}
+
 func (e *Evaluator) evaluateIndexExpression(node *ast.IndexExpression, env *object.Environment) object.Object {
 	target := e.Evaluate(node.Target, env)
 	if isError(target) {
@@ -407,11 +600,27 @@ func (e *Evaluator) evaluateIndexExpression(node *ast.IndexExpression, env *obje
 		return index
 	}
 
+	return e.indexValue(node, target, index)
+}
+
+// indexValue is target[index], both evaluated already ---
+func (e *Evaluator) indexValue(node *ast.IndexExpression, target, index object.Object) object.Object {
 	switch {
-	case target.Type() == object.ARRAY_OBJECT && index.Type() == object.NUMBER_OBJECT:
+	case target.Type() == object.ARRAY_OBJECT && (index.Type() == object.INTEGER_OBJECT || index.Type() == object.NUMBER_OBJECT):
 		return e.evaluateArrayIndexExpression(node, target, index)
 	case target.Type() == object.HASH_OBJECT:
 		return e.evaluateHashIndexExpreesion(node, target, index)
+	case target.Type() == object.EXCEPTION_OBJECT && index.Type() == object.STRING_OBJECT:
+		field, ok := target.(*object.Exception).Err.Field(index.(*object.String).Value)
+		if !ok {
+			return e.throwErr(
+				node.Index,
+				"Exceptions have the fields 'message', 'hint', 'line', 'column' and 'value'",
+				"Exception has no field %s",
+				index.Inspect(),
+			)
+		}
+		return field
 
 	default:
 		return e.throwErr(
@@ -447,7 +656,7 @@ func (e *Evaluator) evaluateHashIndexExpreesion(node *ast.IndexExpression, targe
 
 func (e *Evaluator) evaluateArrayIndexExpression(node *ast.IndexExpression, target object.Object, index object.Object) object.Object {
 	t := target.(*object.Array).Elements
-	i := int(index.(*object.Number).Value)
+	i, _ := object.ToInt(index)
 	maxLen := len(t) - 1
 
 	if i < 0 || i > maxLen {
@@ -468,13 +677,7 @@ func (e *Evaluator) evaluateIndexAssignmentExpression(node *ast.IndexAssignmentE
 		return target
 	}
 
-	switch target.Type() {
-	case object.ARRAY_OBJECT:
-		return e.evaluateArrayIndexAssignmentExpression(node, target, env)
-	case object.HASH_OBJECT:
-		return e.evaluateHashIndexAssignmentExpression(node, target, env)
-
-	default:
+	if target.Type() != object.ARRAY_OBJECT && target.Type() != object.HASH_OBJECT {
 		return e.throwErr(
 			node.Target,
 			"This error occurs when trying to assign a non-indexable expression",
@@ -482,15 +685,50 @@ func (e *Evaluator) evaluateIndexAssignmentExpression(node *ast.IndexAssignmentE
 			target.Type(),
 		)
 	}
-}
 
-func (e *Evaluator) evaluateHashIndexAssignmentExpression(node *ast.IndexAssignmentExpression, target object.Object, env *object.Environment) object.Object {
-	hash := target.(*object.Hash).Pairs
 	index := e.Evaluate(node.Index, env)
 	if isError(index) {
 		return index
 	}
 
+	// a[i] += v reads a[i] before v is evaluated, the target and ---
+	// index are only evaluated once ---
+	var current object.Object
+	element := &ast.IndexExpression{Token: node.Token, Target: node.Target, Index: node.Index}
+	if node.Operator.Type != "" {
+		current = e.indexValue(element, target, index)
+		if isError(current) {
+			return current
+		}
+	}
+
+	newValue := e.Evaluate(node.NewValue, env)
+	if isError(newValue) {
+		return newValue
+	}
+
+	newValue = e.evaluateCompound(node.Token, node.Operator, element, node.NewValue, current, newValue)
+	if isError(newValue) {
+		return newValue
+	}
+
+	var result object.Object
+	if target.Type() == object.ARRAY_OBJECT {
+		result = e.assignArrayIndex(node, target, index, newValue)
+	} else {
+		result = e.assignHashIndex(node, target, index, newValue)
+	}
+
+	// Postfix, like x++ ---
+	if ast.IsIncrement(node.Token.Type) && !isError(result) {
+		return current
+	}
+	return result
+}
+
+func (e *Evaluator) assignHashIndex(node *ast.IndexAssignmentExpression, target, index, newValue object.Object) object.Object {
+	hash := target.(*object.Hash).Pairs
+
 	key, ok := index.(object.Hashable)
 	if !ok {
 		return e.throwErr(
@@ -501,26 +739,15 @@ func (e *Evaluator) evaluateHashIndexAssignmentExpression(node *ast.IndexAssignm
 		)
 	}
 
-	newValue := e.Evaluate(node.NewValue, env)
-
 	hash[key.HashKey()] = object.HashPair{Key: index, Value: newValue}
 
 	return newValue
 }
 
-func (e *Evaluator) evaluateArrayIndexAssignmentExpression(node *ast.IndexAssignmentExpression, target object.Object, env *object.Environment) object.Object {
+func (e *Evaluator) assignArrayIndex(node *ast.IndexAssignmentExpression, target, i, newValue object.Object) object.Object {
 	array := target.(*object.Array)
-	i := e.Evaluate(node.Index, env)
-	if isError(i) {
-		return i
-	}
 
-	newValue := e.Evaluate(node.NewValue, env)
-	if isError(newValue) {
-		return newValue
-	}
-
-	index, ok := i.(*object.Number)
+	index, ok := object.ToInt(i)
 	if !ok {
 		return e.throwErr(
 			node.Index,
@@ -530,14 +757,26 @@ func (e *Evaluator) evaluateArrayIndexAssignmentExpression(node *ast.IndexAssign
 		)
 	}
 
-	array.Elements[int(index.Value)] = newValue
+	if index < 0 || index > len(array.Elements)-1 {
+		return e.throwErr(
+			node.Index,
+			"This error occurs when trying to index an array smaller or bigger than its current length",
+			"Array index '%d' out-of-bounds",
+			index,
+		)
+	}
+
+	array.Elements[index] = newValue
 	return newValue
 }
 
 func (e *Evaluator) evaluateHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
 	pairs := make(map[object.HashKey]object.HashPair)
 
-	for keyNode, valueNode := range node.Pairs {
+	// Pairs is a map, evaluate it in source order so a repeated key's last value wins ---
+	for _, keyNode := range ast.SortedKeys(node) {
+		valueNode := node.Pairs[keyNode]
+
 		key := e.Evaluate(keyNode, env)
 		if isError(key) {
 			return key
@@ -566,7 +805,28 @@ func (e *Evaluator) evaluateHashLiteral(node *ast.HashLiteral, env *object.Envir
 }
 
 func (e *Evaluator) evaluateAbsoluteExpression(node *ast.AbsoluteExpression, env *object.Environment) object.Object {
-	num := e.Evaluate(node.Value, env).(*object.Number)
+	value := e.Evaluate(node.Value, env)
+
+	if integer, ok := value.(*object.Integer); ok {
+		if integer.Value >= 0 {
+			return integer
+		}
+
+		absolute, err := object.Negate(integer.Value)
+		if err != nil {
+			return e.throwErr(node, integerOverflowHint, "%s", err)
+		}
+		return absolute
+	}
+
+	if object.IsBig(value) {
+		if object.SignOf(value) >= 0 {
+			return value
+		}
+		return object.NegateBig(value)
+	}
+
+	num := value.(*object.Number)
 
 	if num.Value >= 0 {
 		return num
@@ -596,7 +856,7 @@ func (e *Evaluator) evaluateStringSlice(str *object.String, node *ast.IndexSlice
 
 	if node.Start != nil {
 		startObj := e.Evaluate(node.Start, env)
-		startNum, ok := startObj.(*object.Number)
+		startNum, ok := object.ToInt(startObj)
 		if !ok {
 			e.throwErr(
 				node.Start,
@@ -606,14 +866,14 @@ func (e *Evaluator) evaluateStringSlice(str *object.String, node *ast.IndexSlice
 			)
 			return nil
 		}
-		start = int(startNum.Value)
+		start = startNum
 	} else {
 		start = 0
 	}
 
 	if node.End != nil {
 		endObj := e.Evaluate(node.End, env)
-		endNum, ok := endObj.(*object.Number)
+		endNum, ok := object.ToInt(endObj)
 		if !ok {
 			e.throwErr(
 				node.End,
@@ -623,7 +883,7 @@ func (e *Evaluator) evaluateStringSlice(str *object.String, node *ast.IndexSlice
 			)
 			return nil
 		}
-		end = int(endNum.Value)
+		end = endNum
 	} else {
 		end = len(str.Value)
 	}
@@ -648,7 +908,7 @@ func (e *Evaluator) evaluateArraySlice(arr *object.Array, node *ast.IndexSliceEx
 
 	if node.Start != nil {
 		startObj := e.Evaluate(node.Start, env)
-		startNum, ok := startObj.(*object.Number)
+		startNum, ok := object.ToInt(startObj)
 		if !ok {
 			e.throwErr(
 				node.Start,
@@ -658,14 +918,14 @@ func (e *Evaluator) evaluateArraySlice(arr *object.Array, node *ast.IndexSliceEx
 			)
 			return nil
 		}
-		start = int(startNum.Value)
+		start = startNum
 	} else {
 		start = 0
 	}
 
 	if node.End != nil {
 		endObj := e.Evaluate(node.End, env)
-		endNum, ok := endObj.(*object.Number)
+		endNum, ok := object.ToInt(endObj)
 		if !ok {
 			e.throwErr(
 				node.End,
@@ -675,7 +935,7 @@ func (e *Evaluator) evaluateArraySlice(arr *object.Array, node *ast.IndexSliceEx
 			)
 			return nil
 		}
-		end = int(endNum.Value)
+		end = endNum
 	} else {
 		end = len(arr.Elements)
 	}
@@ -0,0 +1,277 @@
+package evaluation
+
+import (
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/scheduler"
+)
+
+// A generator body runs on its own goroutine, handing control back ---
+// and forth over channels, so only one side ever runs at a time ---
+type generatorRun struct {
+	resume  chan generatorResume
+	output  chan generatorOutput
+	started bool
+	closing bool
+}
+
+type generatorResume struct {
+	sent  object.Object
+	close bool
+}
+
+type generatorOutput struct {
+	value object.Object
+	done  bool
+}
+
+func (e *Evaluator) newGenerator(fn *object.Function, args []object.Object, frame *object.CallFrame) object.Object {
+	run := &generatorRun{
+		resume: make(chan generatorResume),
+		output: make(chan generatorOutput),
+	}
+
+	name := ""
+	if fn.Name != nil {
+		name = fn.Name.Value
+	}
+
+	var generator *object.Generator
+
+	resume := func(sent object.Object) (object.Object, bool, error) {
+		if !run.started {
+			run.started = true
+			e.liveGenerators[generator] = struct{}{}
+			go e.runGenerator(run, fn, args, frame)
+		}
+
+		e.generators = append(e.generators, run)
+		run.resume <- generatorResume{sent: sent}
+		out := <-run.output
+		e.generators = e.generators[:len(e.generators)-1]
+
+		if out.done {
+			delete(e.liveGenerators, generator)
+		}
+
+		return out.value, out.done, nil
+	}
+
+	stop := func() {
+		if !run.started {
+			return
+		}
+
+		delete(e.liveGenerators, generator)
+		e.generators = append(e.generators, run)
+		run.resume <- generatorResume{close: true}
+		<-run.output
+		e.generators = e.generators[:len(e.generators)-1]
+	}
+
+	generator = object.NewGenerator(name, resume, stop)
+	return generator
+}
+
+func (e *Evaluator) runGenerator(run *generatorRun, fn *object.Function, args []object.Object, frame *object.CallFrame) {
+	var result object.Object
+
+	// The first next() only starts the body ---
+	if msg := <-run.resume; msg.close {
+		run.output <- generatorOutput{value: object.NIL, done: true}
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result = e.throwErr(fn.Body, "", "%v", r)
+		}
+		run.output <- generatorOutput{value: result, done: true}
+	}()
+
+	extendedEnv := e.extendFunctionEnv(fn, args, frame)
+	result = e.traceError(e.unwrapReturnValue(e.runBody(fn, extendedEnv)), extendedEnv)
+}
+
+func (e *Evaluator) evaluateYieldExpression(node *ast.YieldExpression, env *object.Environment) object.Object {
+	var value object.Object = object.NIL
+	if node.Value != nil {
+		value = e.Evaluate(node.Value, env)
+		if isError(value) {
+			return value
+		}
+	}
+
+	run := e.generators[len(e.generators)-1]
+	if run.closing {
+		return e.generatorClosed(node)
+	}
+
+	run.output <- generatorOutput{value: value}
+	msg := <-run.resume
+
+	if msg.close {
+		run.closing = true
+		return e.generatorClosed(node)
+	}
+
+	return msg.sent
+}
+
+// Unwinds a closed generator's body, it can't be resumed again ---
+func (e *Evaluator) generatorClosed(node ast.Node) object.Object {
+	err := e.throwErr(
+		node,
+		"This error occurs when a generator is closed while suspended",
+		"Generator was closed",
+	)
+	err.Fatal = true
+	return err
+}
+
+// Generators still suspended when the program ends are closed ---
+func (e *Evaluator) closeGenerators() {
+	for generator := range e.liveGenerators {
+		generator.Close()
+	}
+}
+
+func (e *Evaluator) evaluateForInStatement(node *ast.ForInStatement, env *object.Environment) object.Object {
+	iterable := e.Evaluate(node.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	next, err := e.iterate(node.Iterable, iterable)
+	if err != nil {
+		return err
+	}
+
+	// Leaving the loop early (return or error) closes the generator ---
+	// right away, so its finally blocks run here ---
+	if generator, ok := iterable.(*object.Generator); ok {
+		defer generator.Close()
+	}
+
+	// The body's declarations rebind the same variables every iteration, ---
+	// like the VM's slots, they're only new ones on the first ---
+	declared := make([]string, 0)
+	for _, name := range bodyDeclarations(node.Body) {
+		if !env.DoesExist(name) {
+			declared = append(declared, name)
+		}
+	}
+
+	for iteration := 0; ; iteration++ {
+		value, ok := next()
+		if !ok {
+			return object.NIL
+		}
+
+		if isError(value) {
+			return value
+		}
+
+		if err := e.step(node); err != nil {
+			return err
+		}
+
+		if iteration > 0 {
+			for _, name := range declared {
+				env.Delete(name)
+			}
+		}
+
+		env.Set(node.Variable.Value, value)
+
+		result := e.Evaluate(node.Body, env)
+		if result != nil && (result.Type() == object.RETURN_VALUE_OBJECT || result.Type() == object.ERROR_OBJECT) {
+			return result
+		}
+	}
+}
+
+// bodyDeclarations lists the names body declares with var in its own ---
+// scope, nested functions declare theirs in their own environments ---
+func bodyDeclarations(body ast.Node) []string {
+	names := make([]string, 0)
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FunctionLiteral, *ast.FunctionDeclarationStatement:
+			return false
+		case *ast.VarStatement:
+			declared := node.Names
+			if node.Pattern != nil {
+				declared = ast.PatternNames(node.Pattern)
+			}
+			for _, name := range declared {
+				names = append(names, name.Value)
+			}
+		}
+		return true
+	})
+
+	return names
+}
+
+// iterate returns a function producing the elements of iterable one at a time ---
+func (e *Evaluator) iterate(node ast.Node, iterable object.Object) (func() (object.Object, bool), object.Object) {
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		elements := append([]object.Object{}, iterable.Elements...)
+		index := 0
+		return func() (object.Object, bool) {
+			if index >= len(elements) {
+				return nil, false
+			}
+			index++
+			return elements[index-1], true
+		}, nil
+
+	case *object.String:
+		chars := []rune(iterable.Value)
+		index := 0
+		return func() (object.Object, bool) {
+			if index >= len(chars) {
+				return nil, false
+			}
+			index++
+			return &object.String{Value: string(chars[index-1])}, true
+		}, nil
+
+	case *object.Generator:
+		return func() (object.Object, bool) {
+			value, err := iterable.Next(object.NIL)
+			if err != nil {
+				return e.throwErr(node, "", "%s", err), true
+			}
+			if iterable.Done {
+				// The generator's return value isn't part of the sequence ---
+				if isError(value) {
+					return value, true
+				}
+				return nil, false
+			}
+			return value, true
+		}, nil
+
+	case *scheduler.Channel:
+		// Receives until the channel is closed and drained ---
+		return func() (object.Object, bool) {
+			value, ok, err := e.tasks.Receive(iterable)
+			if err != nil {
+				return e.schedulerErr(node, "", err), true
+			}
+			return value, ok
+		}, nil
+
+	default:
+		return nil, e.throwErr(
+			node,
+			"Only arrays, strings, generators and channels can be iterated with 'for ... in'",
+			"Cannot iterate over type '%s'",
+			iterable.Type(),
+		)
+	}
+}
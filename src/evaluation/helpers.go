@@ -1,6 +1,7 @@
 package evaluation
 
 import (
+	"bufio"
 	"fmt"
 
 	"github.com/caelondev/monkey-compiler-go/src/ast"
@@ -25,6 +26,12 @@ func isTruthy(obj object.Object) bool {
 	case *object.Number:
 		return obj.Value != 0
 
+	case *object.Integer:
+		return obj.Value != 0
+
+	case *object.BigInt, *object.Decimal:
+		return object.SignOf(obj) != 0
+
 	case *object.NaN:
 		return false
 
@@ -43,6 +50,46 @@ func (e *Evaluator) throwErr(node ast.Node, hint string, format string, a ...int
 	}
 }
 
+// step counts one function call or loop iteration against MaxSteps, ---
+// running out is fatal so a catch can't keep the run going ---
+func (e *Evaluator) step(node ast.Node) object.Object {
+	if e.MaxSteps == 0 {
+		return nil
+	}
+
+	e.steps++
+	if e.steps <= e.MaxSteps {
+		return nil
+	}
+
+	err := e.throwErr(
+		node,
+		"This error occurs when a run takes more steps than its host allows",
+		"Step limit exceeded (%d steps)",
+		e.MaxSteps,
+	)
+	err.Fatal = true
+	return err
+}
+
+// traceError records the calls active in env on an error that ---
+// doesn't have a trace yet, the innermost call to see it wins ---
+func (e *Evaluator) traceError(obj object.Object, env *object.Environment) object.Object {
+	if err, ok := obj.(*object.Error); ok && err.Trace == nil {
+		err.Trace = env.CallFrame().Trace(e.File, err.Line, err.Column)
+	}
+
+	return obj
+}
+
+func functionName(fn *object.Function) string {
+	if fn.Name == nil {
+		return "<anonymous>"
+	}
+
+	return fn.Name.Value
+}
+
 func (e *Evaluator) unwrapReturnValue(obj object.Object) object.Object {
 	if returnVal, ok := obj.(*object.ReturnValue); ok {
 		return returnVal.Value
@@ -50,3 +97,14 @@ func (e *Evaluator) unwrapReturnValue(obj object.Object) object.Object {
 
 	return obj
 }
+
+// Reuses one scanner per input so buffered lines
+// aren't lost between prompt() calls ---
+func (e *Evaluator) inputScanner() *bufio.Scanner {
+	if e.stdinScanner == nil || e.scannerInput != e.Stdin {
+		e.stdinScanner = bufio.NewScanner(e.Stdin)
+		e.scannerInput = e.Stdin
+	}
+
+	return e.stdinScanner
+}
@@ -0,0 +1,222 @@
+package evaluation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// Expand runs the macro pass over a freshly parsed program, it has ---
+// to happen before the program is evaluated or compiled ---
+func Expand(program *ast.Program) (*ast.Program, *object.Error) {
+	e := New()
+	env := object.NewEnvironment(nil)
+
+	e.DefineMacros(program, env)
+	return e.ExpandMacros(program, env)
+}
+
+// DefineMacros moves every top-level `var <name> = macro(...) {...};` ---
+// out of program and into env ---
+func (e *Evaluator) DefineMacros(program *ast.Program, env *object.Environment) {
+	e.InitializeNativeFunctions(env)
+
+	statements := make([]ast.Statement, 0, len(program.Statements))
+	for _, stmt := range program.Statements {
+		varStmt, ok := stmt.(*ast.VarStatement)
+		if !ok {
+			statements = append(statements, stmt)
+			continue
+		}
+
+		literal, ok := varStmt.Value.(*ast.MacroLiteral)
+		if !ok {
+			statements = append(statements, stmt)
+			continue
+		}
+
+		for _, name := range varStmt.Names {
+			env.Declare(name.Value, &object.Macro{Parameters: literal.Parameters, Body: literal.Body, Scope: env})
+		}
+	}
+
+	program.Statements = statements
+}
+
+// ExpandMacros replaces every call to a macro in env with the code ---
+// its body returns, the body sees the arguments as quotes ---
+func (e *Evaluator) ExpandMacros(program *ast.Program, env *object.Environment) (*ast.Program, *object.Error) {
+	var failed *object.Error
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if !ok || failed != nil {
+			return node
+		}
+
+		macro, ok := e.macroFor(call, env)
+		if !ok {
+			return node
+		}
+
+		if len(call.Arguments) != len(macro.Parameters) {
+			failed = e.throwErr(
+				call,
+				"",
+				"Macro '%s' expects %d argument(s), got %d",
+				call.Function,
+				len(macro.Parameters),
+				len(call.Arguments),
+			)
+			return node
+		}
+
+		scope := object.NewEnvironment(macro.Scope)
+		for i, param := range macro.Parameters {
+			scope.Declare(param.Value, &object.Quote{Node: call.Arguments[i]})
+		}
+
+		switch result := e.unwrapReturnValue(e.Evaluate(macro.Body, scope)).(type) {
+		case *object.Error:
+			failed = result
+			return node
+		case *object.Quote:
+			return result.Node
+		default:
+			failed = e.throwErr(
+				call,
+				"Macros return the code to put in place of the call, build it with quote(...)",
+				"Macro '%s' must return a quote, got '%s'",
+				call.Function,
+				result.Type(),
+			)
+			return node
+		}
+	})
+
+	return expanded.(*ast.Program), failed
+}
+
+func (e *Evaluator) macroFor(call *ast.CallExpression, env *object.Environment) (*object.Macro, bool) {
+	ident, ok := call.Function.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	obj, ok := env.Get(ident.Value)
+	if !ok {
+		return nil, false
+	}
+
+	macro, ok := obj.(*object.Macro)
+	return macro, ok
+}
+
+// IsCallTo reports whether call calls name directly ---
+func IsCallTo(call *ast.CallExpression, name string) bool {
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == name
+}
+
+// quote returns its argument as code, with each unquote(...) inside ---
+// replaced by the code for its evaluated argument ---
+func (e *Evaluator) quote(node *ast.CallExpression, env *object.Environment) object.Object {
+	if len(node.Arguments) != 1 {
+		return e.throwErr(node, "", "quote() expects 1 argument, got %d", len(node.Arguments))
+	}
+
+	var failed object.Object
+
+	// Copied, a macro's body is quoted again on every expansion ---
+	quoted := ast.Modify(ast.Clone(node.Arguments[0]), func(n ast.Node) ast.Node {
+		call, ok := n.(*ast.CallExpression)
+		if !ok || failed != nil || !IsCallTo(call, "unquote") {
+			return n
+		}
+
+		if len(call.Arguments) != 1 {
+			failed = e.throwErr(call, "", "unquote() expects 1 argument, got %d", len(call.Arguments))
+			return n
+		}
+
+		value := e.unwrapReturnValue(e.Evaluate(call.Arguments[0], env))
+		if isError(value) {
+			failed = value
+			return n
+		}
+
+		code, ok := objectToNode(value, call.Token)
+		if !ok {
+			failed = e.throwErr(call, "", "Cannot unquote a value of type '%s'", value.Type())
+			return n
+		}
+
+		return code
+	})
+
+	if failed != nil {
+		return failed
+	}
+
+	return &object.Quote{Node: quoted}
+}
+
+// objectToNode is the literal that evaluates to obj, at's position ---
+// is used for it so errors point at the unquote ---
+func objectToNode(obj object.Object, at token.Token) (ast.Node, bool) {
+	tok := func(tokenType token.TokenType, literal string) token.Token {
+		return token.Token{Type: tokenType, Literal: literal, Line: at.Line, Column: at.Column}
+	}
+
+	switch obj := obj.(type) {
+	case *object.Quote:
+		return obj.Node, true
+	case *object.Number:
+		literal := strconv.FormatFloat(obj.Value, 'f', -1, 64)
+		if !strings.Contains(literal, ".") {
+			literal += ".0" // Or it reads back as an integer ---
+		}
+		return &ast.NumberLiteral{Token: tok(token.NUMBER, literal), Value: obj.Value}, true
+	case *object.Integer:
+		literal := strconv.FormatInt(obj.Value, 10)
+		return &ast.IntegerLiteral{Token: tok(token.NUMBER, literal), Value: obj.Value}, true
+	case *object.BigInt:
+		return &ast.BigIntLiteral{Token: tok(token.NUMBER, obj.Inspect()), Value: obj.Value}, true
+	case *object.Decimal:
+		return &ast.DecimalLiteral{Token: tok(token.NUMBER, obj.Inspect()), Unscaled: obj.Unscaled, Scale: obj.Scale}, true
+	case *object.String:
+		return &ast.StringLiteral{Token: tok(token.STRING, obj.Value), Value: obj.Value}, true
+	case *object.Boolean:
+		if obj.Value {
+			return &ast.BooleanExpression{Token: tok(token.TRUE, "true"), Value: true}, true
+		}
+		return &ast.BooleanExpression{Token: tok(token.FALSE, "false"), Value: false}, true
+	case *object.Nil:
+		return &ast.NilLiteral{Token: tok(token.NIL, "nil")}, true
+	case *object.NaN:
+		return &ast.NaNLiteral{Token: tok(token.NOT_A_NUMBER, "NaN")}, true
+	case *object.Infinity:
+		inf := &ast.InfinityLiteral{Token: tok(token.INFINITY, "Inf"), Sign: 1}
+		if obj.Sign < 0 {
+			minus := tok(token.MINUS, "-")
+			return &ast.UnaryExpression{Token: minus, Operator: minus, Right: inf}, true
+		}
+		return inf, true
+
+	case *object.Array:
+		array := &ast.ArrayLiteral{Token: tok(token.LEFT_BRACKET, "["), Elements: make([]ast.Expression, len(obj.Elements))}
+		for i, element := range obj.Elements {
+			node, ok := objectToNode(element, at)
+			if !ok {
+				return nil, false
+			}
+			array.Elements[i] = node.(ast.Expression)
+		}
+		return array, true
+	}
+
+	return nil, false
+}
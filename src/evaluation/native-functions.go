@@ -1,10 +1,8 @@
 package evaluation
 
 import (
-	"bufio"
 	"fmt"
 	"math/rand"
-	"os"
 	"strconv"
 	"time"
 
@@ -27,10 +25,10 @@ func (e *Evaluator) NATIVE_LEN_FUNCTION(callNode *ast.CallExpression, args []obj
 	switch arg.Type() {
 	case object.STRING_OBJECT:
 		s, _ := arg.(*object.String)
-		return &object.Number{Value: float64(len(s.Value))}
+		return &object.Integer{Value: int64(len(s.Value))}
 	case object.ARRAY_OBJECT:
 		a, _ := arg.(*object.Array)
-		return &object.Number{Value: float64(len(a.Elements))}
+		return &object.Integer{Value: int64(len(a.Elements))}
 
 	default:
 		return e.throwErr(
@@ -69,6 +67,11 @@ func (e *Evaluator) NATIVE_TO_NUMBER_FUNCTION(callNode *ast.CallExpression, args
 	switch obj := args[0].(type) {
 	case *object.Number, *object.NaN, *object.Infinity:
 		return obj
+	case *object.Integer:
+		return &object.Number{Value: float64(obj.Value)}
+	case *object.BigInt, *object.Decimal:
+		value, _ := object.BigToFloat(obj)
+		return &object.Number{Value: value}
 	case *object.String:
 		v, err := strconv.ParseFloat(obj.Value, 64)
 		if err != nil {
@@ -85,6 +88,98 @@ func (e *Evaluator) NATIVE_TO_NUMBER_FUNCTION(callNode *ast.CallExpression, args
 	}
 }
 
+func (e *Evaluator) NATIVE_TO_INTEGER_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	if len(args) != 1 {
+		return e.throwErr(
+			callNode,
+			"This error occurs when trying to pass more than 1 argument value to the function",
+			"Expected 1 argument, got %d",
+			len(args),
+		)
+	}
+
+	integer, err := object.ToInteger(args[0])
+	if err != nil {
+		return e.throwErr(
+			callNode.Arguments[0],
+			"to_integer() takes a number, a boolean or a string holding an integer",
+			"%s",
+			err,
+		)
+	}
+
+	return integer
+}
+
+func (e *Evaluator) NATIVE_BIGINT_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	return e.exactConversion(callNode, args, object.ToBigInt, "bigint() takes a number or a string holding an integer")
+}
+
+func (e *Evaluator) NATIVE_DECIMAL_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	return e.exactConversion(callNode, args, object.ToDecimal, "decimal() takes a number or a string holding a decimal")
+}
+
+// exactConversion backs bigint() and decimal() ---
+func (e *Evaluator) exactConversion(
+	callNode *ast.CallExpression,
+	args []object.Object,
+	convert func(obj object.Object) (object.Object, error),
+	hint string,
+) object.Object {
+	if len(args) != 1 {
+		return e.throwErr(
+			callNode,
+			"This error occurs when trying to pass more than 1 argument value to the function",
+			"Expected 1 argument, got %d",
+			len(args),
+		)
+	}
+
+	result, err := convert(args[0])
+	if err != nil {
+		return e.throwErr(callNode.Arguments[0], hint, "%s", err)
+	}
+
+	return result
+}
+
+func (e *Evaluator) NATIVE_DIV_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	return e.integerDivision(callNode, args, object.FloorDivide)
+}
+
+func (e *Evaluator) NATIVE_MOD_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	return e.integerDivision(callNode, args, object.Modulo)
+}
+
+// integerDivision backs div() and mod(), which differ only in what ---
+// they keep of the division ---
+func (e *Evaluator) integerDivision(
+	callNode *ast.CallExpression,
+	args []object.Object,
+	divide func(l, r object.Object) (object.Object, error),
+) object.Object {
+	if len(args) != 2 {
+		return e.throwErr(
+			callNode,
+			"This error occurs when an argument passed was less than or greater than expected amount",
+			"Expected 2 arguments, got %d",
+			len(args),
+		)
+	}
+
+	result, err := divide(args[0], args[1])
+	if err != nil {
+		return e.throwErr(
+			callNode,
+			"div() and mod() round the quotient down, both take integers or floats",
+			"%s",
+			err,
+		)
+	}
+
+	return result
+}
+
 func (e *Evaluator) NATIVE_TO_STRING_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
 	if len(args) != 1 {
 		return e.throwErr(
@@ -112,17 +207,12 @@ func (e *Evaluator) NATIVE_TIME_FUNCTION(callNode *ast.CallExpression, args []ob
 
 func (e *Evaluator) NATIVE_PRINT_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
 	for i, arg := range args {
-		if arg.Type() == object.STRING_OBJECT {
-			msg := arg.Inspect()
-			fmt.Printf("%s", msg[1:len(msg)-1]) // Trim quotes
-		} else {
-			fmt.Printf("%s", arg.Inspect())
-		}
+		fmt.Fprintf(e.Stdout, "%s", object.Display(arg))
 		if i != len(args)-1 {
-			fmt.Printf(", ")
+			fmt.Fprintf(e.Stdout, ", ")
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(e.Stdout)
 	return object.NIL
 }
 
@@ -144,8 +234,8 @@ func (e *Evaluator) NATIVE_PROMPT_FUNCTION(callNode *ast.CallExpression, args []
 			args[0].Type(),
 		)
 	}
-	fmt.Print(message.Value)
-	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Fprint(e.Stdout, message.Value)
+	scanner := e.inputScanner()
 	if scanner.Scan() {
 		return &object.String{Value: scanner.Text()}
 	}
@@ -224,3 +314,64 @@ func (e *Evaluator) NATIVE_RANDOM_FUNCTION(callNode *ast.CallExpression, args []
 
 	return &object.Number{Value: rand.Float64()}
 }
+
+func (e *Evaluator) NATIVE_NEXT_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return e.throwErr(
+			callNode,
+			"next() takes a generator, and optionally the value the paused yield evaluates to",
+			"Expected 1 or 2 arguments, got %d",
+			len(args),
+		)
+	}
+
+	generator, ok := args[0].(*object.Generator)
+	if !ok {
+		return e.throwErr(
+			callNode.Arguments[0],
+			"This error occurs when calling next() on something that isn't a generator",
+			"Cannot resume type '%s'",
+			args[0].Type(),
+		)
+	}
+
+	var sent object.Object = object.NIL
+	if len(args) == 2 {
+		sent = args[1]
+	}
+
+	result, err := generator.Next(sent)
+	if err != nil {
+		return e.throwErr(
+			callNode,
+			"This error occurs when a generator resumes itself",
+			"%s",
+			err,
+		)
+	}
+
+	return result
+}
+
+func (e *Evaluator) NATIVE_IS_DONE_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	if len(args) != 1 {
+		return e.throwErr(
+			callNode,
+			"This error occurs when trying to pass more than 1 argument value to the function",
+			"Expected 1 argument, got %d",
+			len(args),
+		)
+	}
+
+	generator, ok := args[0].(*object.Generator)
+	if !ok {
+		return e.throwErr(
+			callNode.Arguments[0],
+			"This error occurs when calling is_done() on something that isn't a generator",
+			"Cannot check type '%s'",
+			args[0].Type(),
+		)
+	}
+
+	return e.evaluateToObjectBoolean(generator.Done)
+}
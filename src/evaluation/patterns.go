@@ -0,0 +1,115 @@
+package evaluation
+
+import (
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+const destructureHint = "This error occurs when a value doesn't have the shape of the pattern it's unpacked into.\nGive an element a default, like [a, b = 0] or {name = \"\"}, when it can be missing"
+
+// binder puts one unpacked value in a name, an error stops the unpacking ---
+type binder func(name *ast.Identifier, value object.Object) object.Object
+
+// destructure unpacks value into the names of pattern through bind, ---
+// defaults are evaluated in env once they're needed. It's nil when ---
+// everything was bound ---
+func (e *Evaluator) destructure(pattern ast.Expression, value object.Object, env *object.Environment, bind binder) object.Object {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		return bind(pattern, value)
+
+	case *ast.DefaultPattern:
+		if value.Type() == object.NIL_OBJECT {
+			value = e.Evaluate(pattern.Default, env)
+			if isError(value) {
+				return value
+			}
+		}
+		return e.destructure(pattern.Target, value, env, bind)
+
+	case *ast.ArrayPattern:
+		array, err := object.UnpackArray(value, pattern.Required(), len(pattern.Elements), pattern.Rest != nil)
+		if err != nil {
+			return e.throwErr(pattern, destructureHint, "%s", err)
+		}
+
+		for i, element := range pattern.Elements {
+			if result := e.destructure(element, object.UnpackElement(array, i, false), env, bind); result != nil {
+				return result
+			}
+		}
+
+		if pattern.Rest != nil {
+			return bind(pattern.Rest, object.UnpackElement(array, len(pattern.Elements), true))
+		}
+
+	case *ast.HashPattern:
+		hash, err := object.UnpackHash(value)
+		if err != nil {
+			return e.throwErr(pattern, destructureHint, "%s", err)
+		}
+
+		for _, entry := range pattern.Entries {
+			_, hasDefault := entry.Value.(*ast.DefaultPattern)
+			element, err := object.UnpackKey(hash, &object.String{Value: entry.Key.Value}, hasDefault)
+			if err != nil {
+				return e.throwErr(entry.Key, destructureHint, "%s", err)
+			}
+
+			if result := e.destructure(entry.Value, element, env, bind); result != nil {
+				return result
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkDuplicates rejects a declaration or parameter list that binds ---
+// a name twice, reported at the second one like the compiler does ---
+func (e *Evaluator) checkDuplicates(names []*ast.Identifier) object.Object {
+	duplicate := ast.DuplicateName(names)
+	if duplicate == nil {
+		return nil
+	}
+
+	return e.throwErr(
+		duplicate,
+		"This error occurs when a pattern or parameter list binds the same name more than once",
+		"Cannot bind '%s' twice in the same declaration",
+		duplicate.Value,
+	)
+}
+
+// declareIn binds names as new variables of env ---
+func declareIn(env *object.Environment) binder {
+	return func(name *ast.Identifier, value object.Object) object.Object {
+		env.Set(name.Value, value)
+		return nil
+	}
+}
+
+// assignIn binds names to the variables they already are in env ---
+func assignIn(env *object.Environment) binder {
+	return func(name *ast.Identifier, value object.Object) object.Object {
+		env.Assign(name.Value, value)
+		return nil
+	}
+}
+
+// runBody unpacks the arguments of fn's pattern parameters, which are ---
+// in env under the parameter's name already, then evaluates its body ---
+func (e *Evaluator) runBody(fn *object.Function, env *object.Environment) object.Object {
+	for i, pattern := range fn.Patterns {
+		if pattern == nil {
+			continue
+		}
+
+		arg, _ := env.Get(fn.Parameters[i].Value)
+		if result := e.destructure(pattern, arg, env, declareIn(env)); result != nil {
+			return result
+		}
+	}
+
+	return e.Evaluate(fn.Body, env)
+}
@@ -40,9 +40,17 @@ func (e *Evaluator) evaluateIfStatement(node *ast.IfStatement, env *object.Envir
 }
 
 func (e *Evaluator) evaluateVariableDeclaration(node *ast.VarStatement, env *object.Environment) object.Object {
+	names := node.Names
+	if node.Pattern != nil {
+		names = ast.PatternNames(node.Pattern)
+		if err := e.checkDuplicates(names); err != nil {
+			return err
+		}
+	}
+
 	// Check if every assignees are valid ---
 	// Then discard everything if not ---
-	for _, name := range node.Names {
+	for _, name := range names {
 		if !env.DoesExist(name.Value) {
 			continue
 		}
@@ -60,6 +68,13 @@ func (e *Evaluator) evaluateVariableDeclaration(node *ast.VarStatement, env *obj
 		return value
 	}
 
+	if node.Pattern != nil {
+		if err := e.destructure(node.Pattern, value, env, declareIn(env)); err != nil {
+			return err
+		}
+		return value
+	}
+
 	for _, name := range node.Names {
 		env.Set(name.Value, value)
 	}
@@ -68,10 +83,15 @@ func (e *Evaluator) evaluateVariableDeclaration(node *ast.VarStatement, env *obj
 }
 
 func (e *Evaluator) evaluateBatchAssignmentStatement(node *ast.BatchAssignmentStatement, env *object.Environment) object.Object {
+	assignees := node.Assignees
+	if node.Pattern != nil {
+		assignees = ast.PatternNames(node.Pattern)
+	}
+
 	// Check if every assignees are valid ---
 	// Then discard everything if not ---
-	for _, assignee := range node.Assignees {
-		exists := env.DoesExist(assignee.Value)
+	for _, assignee := range assignees {
+		_, exists := env.Get(assignee.Value)
 
 		if !exists {
 			return e.throwErr(
@@ -84,12 +104,40 @@ func (e *Evaluator) evaluateBatchAssignmentStatement(node *ast.BatchAssignmentSt
 	}
 
 	newValue := e.Evaluate(node.NewValue, env)
+	if isError(newValue) {
+		return newValue
+	}
 
-	for _, assignee := range node.Assignees {
-		env.Set(assignee.Value, newValue)
+	if node.Pattern != nil {
+		if err := e.destructure(node.Pattern, newValue, env, assignIn(env)); err != nil {
+			return err
+		}
+		return newValue
+	}
+
+	if node.Operator.Type == "" {
+		for _, assignee := range node.Assignees {
+			env.Assign(assignee.Value, newValue)
+		}
+		return newValue
+	}
+
+	// `assign a, b += v` adds v to each, all of them are worked ---
+	// out before any is assigned ---
+	results := make([]object.Object, len(node.Assignees))
+	for i, assignee := range node.Assignees {
+		current, _ := env.Get(assignee.Value)
+		results[i] = e.evaluateCompound(node.Assign, node.Operator, assignee, node.NewValue, current, newValue)
+		if isError(results[i]) {
+			return results[i]
+		}
 	}
 
-	return newValue
+	for i, assignee := range node.Assignees {
+		env.Assign(assignee.Value, results[i])
+	}
+
+	return results[len(results)-1]
 }
 
 func (e *Evaluator) evaluateReturnStatement(node *ast.ReturnStatement, env *object.Environment) object.Object {
@@ -102,13 +150,57 @@ func (e *Evaluator) evaluateReturnStatement(node *ast.ReturnStatement, env *obje
 }
 
 func (e *Evaluator) evaluateFunctionDeclaration(node *ast.FunctionDeclarationStatement, env *object.Environment) object.Object {
+	if err := e.checkDuplicates(ast.ParameterNames(node.Parameters, node.Patterns)); err != nil {
+		return err
+	}
+
 	function := &object.Function{
-		Parameters: node.Parameters,
-		Name:       node.Name,
-		Body:       node.Body,
-		Scope:      env,
+		Parameters:  node.Parameters,
+		Patterns:    node.Patterns,
+		Name:        node.Name,
+		Body:        node.Body,
+		Scope:       env,
+		IsGenerator: node.IsGenerator,
 	}
 
 	value := env.Declare(node.Name.Value, function)
 	return value
 }
+
+func (e *Evaluator) evaluateTryStatement(node *ast.TryStatement, env *object.Environment) object.Object {
+	result := e.Evaluate(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && !err.Fatal && node.Catch != nil {
+		e.traceError(err, env)
+
+		if node.CatchParam != nil {
+			env.Set(node.CatchParam.Value, &object.Exception{Err: err})
+		}
+
+		result = e.Evaluate(node.Catch, env)
+	}
+
+	if node.Finally != nil {
+		// A return or error from finally replaces the pending result ---
+		finally := e.Evaluate(node.Finally, env)
+		if finally != nil && (finally.Type() == object.RETURN_VALUE_OBJECT || isError(finally)) {
+			return finally
+		}
+	}
+
+	return result
+}
+
+func (e *Evaluator) evaluateThrowStatement(node *ast.ThrowStatement, env *object.Environment) object.Object {
+	value := e.Evaluate(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	err := object.NewThrownError(value, node.GetLine(), node.GetColumn())
+	if err.NodeStr == "" {
+		err.NodeStr = node.String()
+	}
+
+	return err
+}
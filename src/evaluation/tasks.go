@@ -0,0 +1,77 @@
+package evaluation
+
+import (
+	"errors"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/scheduler"
+)
+
+// Arguments are evaluated by the spawning task, the call itself runs ---
+// once the spawning task blocks (see the scheduler package) ---
+func (e *Evaluator) evaluateSpawnExpression(node *ast.SpawnExpression, env *object.Environment) object.Object {
+	fn, args := e.resolveCall(node.Call, env)
+	if fn == nil {
+		return args[0]
+	}
+
+	if fn.Type() != object.FUNCTION_OBJECT {
+		return e.throwErr(
+			node.Call.Function,
+			"Only functions can be spawned as tasks",
+			"Attempted to spawn %s",
+			fn.Type(),
+		)
+	}
+
+	name := ""
+	if ident, ok := node.Call.Function.(*ast.Identifier); ok {
+		name = ident.Value
+	}
+
+	return e.tasks.Spawn(name, func() (object.Object, error) {
+		return e.applyFunction(node.Call.Function, node.Call, fn, args, env.CallFrame()), nil
+	})
+}
+
+func (e *Evaluator) evaluateAwaitExpression(node *ast.AwaitExpression, env *object.Environment) object.Object {
+	value := e.Evaluate(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	result, err := e.tasks.AwaitValue(value)
+	if err != nil {
+		return e.schedulerErr(
+			node,
+			"This error occurs when awaiting something that isn't a task, or when every task is blocked",
+			err,
+		)
+	}
+
+	// A task that failed hands its error to whoever awaits it ---
+	return result
+}
+
+func (e *Evaluator) registerSchedulerFn(env *object.Environment, name string, fn object.HostFunctionFn) {
+	e.registerNativeFn(env, name, func(callNode *ast.CallExpression, args []object.Object) object.Object {
+		result, err := fn(args...)
+		if err != nil {
+			return e.schedulerErr(
+				callNode,
+				"This error occurs when a channel operation is invalid, or when every task is blocked",
+				err,
+			)
+		}
+
+		return result
+	})
+}
+
+// An aborted task must unwind all the way, so that error can't be caught ---
+func (e *Evaluator) schedulerErr(node ast.Node, hint string, err error) *object.Error {
+	errObj := e.throwErr(node, hint, "%s", err)
+	errObj.Fatal = errors.Is(err, scheduler.ErrAborted)
+	return errObj
+}
@@ -0,0 +1,69 @@
+package format
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/caelondev/monkey-compiler-go/src/run"
+)
+
+// Command runs `monkey fmt [-w] [--check] <paths>`. Without flags the ---
+// formatted files go to out, directories are searched for .mn files ---
+func Command(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := flags.Bool("w", false, "rewrite files in place")
+	check := flags.Bool("check", false, "list unformatted files and fail if there are any")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("Usage: monkey fmt [-w] [--check] <path>...")
+	}
+
+	files, err := run.SourceFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	unformatted := 0
+	for _, path := range files {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		formatted, err := Source(string(source))
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+
+		changed := formatted != string(source)
+
+		switch {
+		case *check:
+			if changed {
+				fmt.Fprintln(out, path)
+				unformatted++
+			}
+		case *write:
+			if changed {
+				err = os.WriteFile(path, []byte(formatted), 0644)
+			}
+		default:
+			_, err = io.WriteString(out, formatted)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if unformatted != 0 {
+		return fmt.Errorf("%d file(s) need formatting", unformatted)
+	}
+
+	return nil
+}
@@ -0,0 +1,850 @@
+// Package format is the canonical pretty-printer behind `monkey fmt`,
+// it reprints the AST with the comments the lexer kept as trivia.
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+const (
+	indentation = "    "
+	maxWidth    = 80 // Lists wider than this get one element per line ---
+)
+
+// Source formats a whole file, it fails if the file doesn't parse.
+func Source(source string) (string, error) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return "", errors.New(p.Errors()[0])
+	}
+
+	return Program(program, source), nil
+}
+
+// Program prints a parsed program, source is what it was parsed from ---
+// and places its comments and blank lines ---
+func Program(program *ast.Program, source string) string {
+	pr := &printer{
+		source:    source,
+		lines:     strings.Split(source, "\n"),
+		comments:  program.Comments,
+		blockEnds: matchBraces(source),
+		breaking:  true,
+	}
+
+	pr.statements(program.Statements, endOfFile)
+	return pr.out.String()
+}
+
+// Expression prints expr on its own, grouped the way it parsed so the ---
+// text parses back, as a statement, to the same tree ---
+func Expression(expr ast.Expression) string {
+	pr := &printer{breaking: true}
+
+	// Like an expression statement, a leading fn would start a declaration ---
+	if fn, ok := leftmost(expr).(*ast.FunctionLiteral); ok {
+		pr.grouped = fn
+	}
+
+	pr.expression(expr, parser.LOWEST)
+	return pr.out.String()
+}
+
+type pos struct {
+	line, column uint
+}
+
+var endOfFile = pos{line: ^uint(0)}
+
+func (p pos) before(other pos) bool {
+	return p.line < other.line || (p.line == other.line && p.column < other.column)
+}
+
+func nodePos(node ast.Node) pos {
+	return pos{node.GetLine(), node.GetColumn()}
+}
+
+func spanPos(position token.Position) pos {
+	return pos{position.Line, position.Column}
+}
+
+// within is Span.Contains by line and column, comments have no offsets ---
+func within(p pos, span ast.Span) bool {
+	return !p.before(spanPos(span.Start)) && p.before(spanPos(span.End))
+}
+
+type printer struct {
+	out    bytes.Buffer
+	indent int
+
+	source    string
+	lines     []string
+	comments  []token.Token
+	next      int         // First comment not printed yet ---
+	blockEnds map[pos]pos // Position of each '{' to its '}' ---
+
+	// Measuring printers render everything on one line ---
+	breaking bool
+
+	grouped ast.Expression // Printed in parentheses even where it needn't be ---
+}
+
+// matchBraces pairs every '{' with its '}', the AST only keeps the first ---
+func matchBraces(source string) map[pos]pos {
+	ends := make(map[pos]pos)
+	open := make([]pos, 0)
+
+	l := lexer.New(source)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		switch tok.Type {
+		case token.LEFT_BRACE:
+			open = append(open, pos{tok.Line, tok.Column})
+		case token.RIGHT_BRACE:
+			if len(open) != 0 {
+				ends[open[len(open)-1]] = pos{tok.Line, tok.Column}
+				open = open[:len(open)-1]
+			}
+		}
+	}
+
+	return ends
+}
+
+func (p *printer) write(s string) {
+	p.out.WriteString(s)
+}
+
+func (p *printer) writeIndent() {
+	p.write(strings.Repeat(indentation, p.indent))
+}
+
+// column is the width of the line being written ---
+func (p *printer) column() int {
+	out := p.out.Bytes()
+	return len(out) - (bytes.LastIndexByte(out, '\n') + 1)
+}
+
+// fits reports whether render's first line fits on the current one, ---
+// and whether it spans more lines (a function literal's body) ---
+func (p *printer) fits(render func(p *printer)) (bool, bool) {
+	measure := &printer{indent: p.indent, source: p.source, blockEnds: p.blockEnds}
+	render(measure)
+
+	firstLine, _, multiline := strings.Cut(measure.out.String(), "\n")
+	return p.column()+len(firstLine) <= maxWidth, multiline
+}
+
+// [ COMMENTS ] ---
+
+func (p *printer) hasCommentsBefore(end pos) bool {
+	return p.next < len(p.comments) && p.commentPos(p.next).before(end)
+}
+
+func (p *printer) commentPos(i int) pos {
+	return pos{p.comments[i].Line, p.comments[i].Column}
+}
+
+// flushComments prints the comments before end, a comment that ---
+// followed code on its line stays at the end of the last line ---
+func (p *printer) flushComments(end pos) {
+	for p.hasCommentsBefore(end) {
+		comment := p.comments[p.next]
+		p.next++
+
+		if p.trailsCode(comment) && p.out.Len() != 0 {
+			p.out.Truncate(p.out.Len() - 1) // The newline ---
+			p.write(" " + comment.Literal + "\n")
+			continue
+		}
+
+		p.blankLine(comment.Line)
+		p.writeIndent()
+		p.write(comment.Literal + "\n")
+	}
+}
+
+// commentsBetween reports whether node has comments outside of the ---
+// spans of its elements, a function literal's body keeps its own ---
+func (p *printer) commentsBetween(node ast.Node, spans []ast.Span) bool {
+	for i := p.next; i < len(p.comments); i++ {
+		comment := p.commentPos(i)
+		if !within(comment, node.NodeSpan()) {
+			return false
+		}
+
+		inside := false
+		for _, span := range spans {
+			inside = inside || within(comment, span)
+		}
+		if !inside {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *printer) trailsCode(comment token.Token) bool {
+	line := p.sourceLine(comment.Line)
+	column := min(int(comment.Column)-1, len(line))
+	return strings.TrimSpace(line[:max(column, 0)]) != ""
+}
+
+func (p *printer) sourceLine(line uint) string {
+	if line == 0 || int(line) > len(p.lines) {
+		return ""
+	}
+
+	return p.lines[line-1]
+}
+
+// blankLine keeps one empty line where the source had any ---
+// before line, except at the start of a file or block ---
+func (p *printer) blankLine(line uint) {
+	if line < 2 || strings.TrimSpace(p.sourceLine(line-1)) != "" {
+		return
+	}
+
+	out := p.out.Bytes()
+	if len(out) == 0 || bytes.HasSuffix(out, []byte("{\n")) || bytes.HasSuffix(out, []byte("\n\n")) {
+		return
+	}
+
+	p.write("\n")
+}
+
+// [ STATEMENTS ] ---
+
+// statements prints one statement per line, with the comments ---
+// up to end (the closing brace of their block) ---
+func (p *printer) statements(stmts []ast.Statement, end pos) {
+	for _, stmt := range stmts {
+		p.flushComments(nodePos(stmt))
+		p.blankLine(stmt.GetLine())
+
+		p.writeIndent()
+		p.statement(stmt)
+		p.write("\n")
+	}
+
+	p.flushComments(end)
+}
+
+func (p *printer) block(block *ast.BlockStatement) {
+	end, ok := p.blockEnds[nodePos(block)]
+	if !ok {
+		end = endOfFile
+	}
+
+	if len(block.Statements) == 0 && !p.hasCommentsBefore(end) {
+		p.write("{}")
+		return
+	}
+
+	// Measuring only needs the first line ---
+	if !p.breaking {
+		p.write("{\n}")
+		return
+	}
+
+	p.write("{\n")
+	p.indent++
+	p.statements(block.Statements, end)
+	p.indent--
+	p.writeIndent()
+	p.write("}")
+}
+
+// body prints the statement controlled by an if or else, ---
+// which is a block or a single statement on the same line ---
+func (p *printer) body(stmt ast.Statement) {
+	if block, ok := stmt.(*ast.BlockStatement); ok {
+		p.block(block)
+		return
+	}
+
+	p.statement(stmt)
+}
+
+func (p *printer) statement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		// A leading fn would start a declaration ---
+		if fn, ok := leftmost(stmt.Expression).(*ast.FunctionLiteral); ok {
+			p.grouped = fn
+		}
+
+		p.expression(stmt.Expression, parser.LOWEST)
+		p.write(";")
+
+	case *ast.VarStatement:
+		p.write("var ")
+		if stmt.Pattern != nil {
+			p.pattern(stmt.Pattern)
+			p.write(" = ")
+			p.expression(stmt.Value, parser.LOWEST)
+			p.write(";")
+			return
+		}
+
+		p.identifiers(stmt.Names)
+		p.annotation(stmt.Type)
+
+		// `var x;` declares it as nil without an initializer ---
+		if nilLit, ok := stmt.Value.(*ast.NilLiteral); ok && nilLit.Token.Type == token.SEMICOLON {
+			p.write(";")
+			return
+		}
+
+		p.write(" = ")
+		p.expression(stmt.Value, parser.LOWEST)
+		p.write(";")
+
+	case *ast.BatchAssignmentStatement:
+		p.write("assign ")
+		if stmt.Pattern != nil {
+			p.pattern(stmt.Pattern)
+		}
+		p.identifiers(stmt.Assignees)
+		p.assignment(stmt.Assign, stmt.NewValue, parser.LOWEST)
+		p.write(";")
+
+	case *ast.ReturnStatement:
+		if stmt.ReturnValue == nil {
+			p.write("return;")
+			return
+		}
+
+		p.write("return ")
+		p.expression(stmt.ReturnValue, parser.LOWEST)
+		p.write(";")
+
+	case *ast.ThrowStatement:
+		p.write("throw ")
+		p.expression(stmt.Value, parser.LOWEST)
+		p.write(";")
+
+	case *ast.FunctionDeclarationStatement:
+		p.write("fn " + stmt.Name.Value + "(")
+		p.parameters(stmt.Parameters, stmt.Patterns, stmt.ParameterTypes)
+		p.write(")")
+		p.annotation(stmt.ReturnType)
+		p.write(" ")
+		p.block(stmt.Body)
+
+	case *ast.IfStatement:
+		p.write("if (")
+		p.expression(stmt.Condition, parser.LOWEST)
+		p.write(") ")
+		p.body(stmt.Consequence)
+
+		if stmt.Alternative != nil {
+			p.write(" else ")
+			p.body(stmt.Alternative)
+		}
+
+	case *ast.ForInStatement:
+		// Unparenthesized, a slice's { would open the body ---
+		if containsSlice(stmt.Iterable) {
+			p.write("for (" + stmt.Variable.Value + " in ")
+			p.expression(stmt.Iterable, parser.LOWEST)
+			p.write(") ")
+		} else {
+			p.write("for " + stmt.Variable.Value + " in ")
+			p.expression(stmt.Iterable, parser.LOWEST)
+			p.write(" ")
+		}
+		p.block(stmt.Body)
+
+	case *ast.TryStatement:
+		p.write("try ")
+		p.block(stmt.Block)
+
+		if stmt.Catch != nil {
+			p.write(" catch ")
+			if stmt.CatchParam != nil {
+				p.write("(" + stmt.CatchParam.Value + ") ")
+			}
+			p.block(stmt.Catch)
+		}
+
+		if stmt.Finally != nil {
+			p.write(" finally ")
+			p.block(stmt.Finally)
+		}
+
+	case *ast.BlockStatement:
+		p.block(stmt)
+	}
+}
+
+func (p *printer) identifiers(idents []*ast.Identifier) {
+	for i, ident := range idents {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.write(ident.Value)
+	}
+}
+
+// assignment writes what follows an assignee, `++` or ` += value` ---
+func (p *printer) assignment(assign token.Token, value ast.Expression, minimum int) {
+	if ast.IsIncrement(assign.Type) {
+		p.write(assign.Literal)
+		return
+	}
+
+	p.write(" " + assign.Literal + " ")
+	p.expression(value, minimum)
+}
+
+func (p *printer) parameters(params []*ast.Identifier, patterns []ast.Expression, types []*ast.TypeAnnotation) {
+	for i, param := range params {
+		if i > 0 {
+			p.write(", ")
+		}
+		if i < len(patterns) && patterns[i] != nil {
+			p.pattern(patterns[i])
+		} else {
+			p.write(param.Value)
+		}
+		if i < len(types) {
+			p.annotation(types[i])
+		}
+	}
+}
+
+// pattern writes a name or pattern, keys keep the quotes they had ---
+func (p *printer) pattern(pattern ast.Expression) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		p.write(pattern.Value)
+
+	case *ast.DefaultPattern:
+		p.pattern(pattern.Target)
+		p.write(" = ")
+		p.expression(pattern.Default, parser.LOWEST)
+
+	case *ast.ArrayPattern:
+		p.write("[")
+		for i, element := range pattern.Elements {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.pattern(element)
+		}
+		if pattern.Rest != nil {
+			if len(pattern.Elements) != 0 {
+				p.write(", ")
+			}
+			p.write("..." + pattern.Rest.Value)
+		}
+		p.write("]")
+
+	case *ast.HashPattern:
+		p.write("{")
+		for i, entry := range pattern.Entries {
+			if i > 0 {
+				p.write(", ")
+			}
+			if entry.IsShorthand() {
+				p.pattern(entry.Value)
+				continue
+			}
+
+			if entry.Key.Token.Type == token.IDENTIFIER {
+				p.write(entry.Key.Value)
+			} else {
+				p.write(p.quote(entry.Key))
+			}
+			p.write(": ")
+			p.pattern(entry.Value)
+		}
+		p.write("}")
+	}
+}
+
+// annotation writes `: <type>`, nothing for an unannotated node ---
+func (p *printer) annotation(annotation *ast.TypeAnnotation) {
+	if annotation != nil {
+		p.write(": " + annotation.String())
+	}
+}
+
+// [ EXPRESSIONS ] ---
+
+// precedenceOf is how tightly an expression binds, the parser's levels ---
+func precedenceOf(expr ast.Expression) int {
+	switch expr := expr.(type) {
+	case *ast.AssignmentExpression:
+		if ast.IsIncrement(expr.Token.Type) {
+			return parser.CALL
+		}
+		return parser.ASSIGNMENT
+	case *ast.IndexAssignmentExpression:
+		if ast.IsIncrement(expr.Token.Type) {
+			return parser.CALL
+		}
+		return parser.ASSIGNMENT
+	case *ast.YieldExpression:
+		return parser.ASSIGNMENT
+	case *ast.TernaryExpression:
+		return parser.TERNARY
+	case *ast.BinaryExpression:
+		return parser.Precedence(expr.Operator.Type)
+	case *ast.UnaryExpression, *ast.AwaitExpression, *ast.SpawnExpression:
+		return parser.UNARY
+	}
+
+	return parser.CALL
+}
+
+// expression wraps expr in parentheses when it binds looser than minimum ---
+func (p *printer) expression(expr ast.Expression, minimum int) {
+	if precedenceOf(expr) < minimum || expr == p.grouped {
+		p.grouped = nil
+		p.write("(")
+		p.expression(expr, parser.LOWEST)
+		p.write(")")
+		return
+	}
+
+	switch expr := expr.(type) {
+	case *ast.Identifier:
+		p.write(expr.Value)
+	case *ast.NumberLiteral:
+		p.write(expr.Token.Literal)
+	case *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral:
+		p.write(expr.TokenLiteral())
+	case *ast.StringLiteral:
+		p.write(p.quote(expr))
+	case *ast.BooleanExpression:
+		p.write(expr.String())
+	case *ast.NilLiteral:
+		p.write("nil")
+	case *ast.NaNLiteral:
+		p.write("NaN")
+	case *ast.InfinityLiteral:
+		if expr.Sign < 0 {
+			p.write("-")
+		}
+		p.write("Inf")
+
+	case *ast.UnaryExpression:
+		p.write(expr.Operator.Literal)
+		if expr.Operator.Type == token.NOT {
+			p.write(" ")
+		}
+		p.expression(expr.Right, parser.CALL)
+
+	case *ast.BinaryExpression:
+		level := parser.Precedence(expr.Operator.Type)
+
+		// ^ is right associative ---
+		left, right := level, level+1
+		if expr.Operator.Type == token.CARET {
+			left, right = level+1, level
+		}
+
+		p.expression(expr.Left, left)
+		p.write(" " + expr.Operator.Literal + " ")
+		p.expression(expr.Right, right)
+
+	case *ast.TernaryExpression:
+		p.expression(expr.Consequence, parser.TERNARY)
+		p.write(" if ")
+		p.expression(expr.Condition, parser.TERNARY+1)
+		p.write(" else ")
+		p.expression(expr.Alternative, parser.TERNARY+1)
+
+	case *ast.AssignmentExpression:
+		p.expression(expr.Assignee, parser.CALL)
+		p.assignment(expr.Token, expr.NewValue, parser.ASSIGNMENT+2)
+
+	case *ast.IndexAssignmentExpression:
+		p.expression(expr.Target, parser.CALL)
+		p.write("[")
+		p.expression(expr.Index, parser.LOWEST)
+		p.write("]")
+		p.assignment(expr.Token, expr.NewValue, parser.ASSIGNMENT+2)
+
+	case *ast.FunctionLiteral:
+		p.write("fn(")
+		p.parameters(expr.Parameters, expr.Patterns, expr.ParameterTypes)
+		p.write(")")
+		p.annotation(expr.ReturnType)
+		p.write(" ")
+		p.block(expr.Body)
+
+	case *ast.MacroLiteral:
+		p.write("macro(")
+		p.identifiers(expr.Parameters)
+		p.write(") ")
+		p.block(expr.Body)
+
+	case *ast.CallExpression:
+		p.expression(expr.Function, parser.CALL)
+		p.list(expr, "(", expr.Arguments, ")")
+
+	case *ast.ArrayLiteral:
+		p.list(expr, "[", expr.Elements, "]")
+
+	case *ast.InterpolatedString:
+		p.interpolation(expr)
+
+	case *ast.HashLiteral:
+		p.hash(expr)
+
+	case *ast.IndexExpression:
+		p.expression(expr.Target, parser.CALL)
+		p.write("[")
+		p.expression(expr.Index, parser.LOWEST)
+		p.write("]")
+
+	case *ast.IndexSliceExpression:
+		p.expression(expr.Target, parser.CALL)
+		p.write("{")
+		if expr.Start != nil {
+			// {~x~} would read as a slice from the start ---
+			if unary, ok := leftmost(expr.Start).(*ast.UnaryExpression); ok && unary.Operator.Type == token.TILDE {
+				p.grouped = expr.Start
+			}
+			p.expression(expr.Start, parser.LOWEST)
+		}
+		p.write("~")
+		if expr.End != nil {
+			p.expression(expr.End, parser.LOWEST)
+		}
+		p.write("}")
+
+	case *ast.AbsoluteExpression:
+		// A bitwise or would close the bars ---
+		if containsBitwiseOr(expr.Value) {
+			p.grouped = expr.Value
+		}
+		p.write("|")
+		p.expression(expr.Value, parser.LOWEST)
+		p.write("|")
+
+	case *ast.SpawnExpression:
+		p.write("spawn ")
+		p.expression(expr.Call, parser.CALL)
+
+	case *ast.AwaitExpression:
+		p.write("await ")
+		p.expression(expr.Value, parser.CALL)
+
+	case *ast.YieldExpression:
+		p.write("yield")
+		if expr.Value != nil {
+			p.write(" ")
+			p.expression(expr.Value, parser.ASSIGNMENT+1)
+		}
+	}
+}
+
+// list prints comma separated elements, one per line when they don't fit ---
+// or have comments between them. Arrays also break around function ---
+// literals, call arguments don't ---
+func (p *printer) list(node ast.Expression, open string, elements []ast.Expression, close string) {
+	p.write(open)
+	defer p.write(close)
+
+	spans := make([]ast.Span, len(elements))
+	for i, element := range elements {
+		spans[i] = element.NodeSpan()
+	}
+
+	flat := func(p *printer) {
+		for i, element := range elements {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(element, parser.LOWEST)
+		}
+	}
+
+	if !p.breaking || len(elements) == 0 {
+		flat(p)
+		return
+	}
+
+	if fits, multiline := p.fits(flat); fits && (open == "(" || !multiline) && !p.commentsBetween(node, spans) {
+		flat(p)
+		return
+	}
+
+	p.write("\n")
+	p.indent++
+	for i, element := range elements {
+		p.flushComments(spanPos(spans[i].Start))
+		p.writeIndent()
+		p.expression(element, parser.LOWEST)
+		if i < len(elements)-1 {
+			p.write(",")
+		}
+		p.write("\n")
+	}
+	p.flushComments(spanPos(node.NodeSpan().End))
+	p.indent--
+	p.writeIndent()
+}
+
+func (p *printer) hash(hash *ast.HashLiteral) {
+	// Pairs is a map, print it in source order ---
+	keys := ast.SortedKeys(hash)
+
+	spans := make([]ast.Span, len(keys))
+	for i, key := range keys {
+		spans[i] = ast.Span{Start: key.NodeSpan().Start, End: hash.Pairs[key].NodeSpan().End}
+	}
+
+	pair := func(p *printer, key ast.Expression) {
+		p.expression(key, parser.LOWEST)
+		p.write(": ")
+		p.expression(hash.Pairs[key], parser.LOWEST)
+	}
+
+	p.write("{")
+	defer p.write("}")
+
+	flat := func(p *printer) {
+		for i, key := range keys {
+			if i > 0 {
+				p.write(", ")
+			}
+			pair(p, key)
+		}
+	}
+
+	if !p.breaking || len(keys) == 0 {
+		flat(p)
+		return
+	}
+
+	if fits, multiline := p.fits(flat); fits && !multiline && !p.commentsBetween(hash, spans) {
+		flat(p)
+		return
+	}
+
+	p.write("\n")
+	p.indent++
+	for i, key := range keys {
+		p.flushComments(spanPos(spans[i].Start))
+		p.writeIndent()
+		pair(p, key)
+		if i < len(keys)-1 {
+			p.write(",")
+		}
+		p.write("\n")
+	}
+	p.flushComments(spanPos(hash.NodeSpan().End))
+	p.indent--
+	p.writeIndent()
+}
+
+// quote keeps a string the way it was written, escapes, raw and ---
+// multi-line strings included ---
+func (p *printer) quote(literal *ast.StringLiteral) string {
+	tok := literal.Token
+	if tok.EndOffset == 0 || tok.EndOffset > len(p.source) {
+		return lexer.Quote(literal.Value)
+	}
+
+	return p.source[tok.Offset:tok.EndOffset]
+}
+
+// interpolation writes the text as it was, quotes and ${ } included, ---
+// and formats what's inside each ${...} ---
+func (p *printer) interpolation(expr *ast.InterpolatedString) {
+	if head := expr.Token; head.EndOffset == 0 || head.EndOffset > len(p.source) {
+		p.write(expr.String())
+		return
+	}
+
+	for _, part := range expr.Parts {
+		if text, ok := part.(*ast.StringLiteral); ok {
+			p.write(p.source[text.Token.Offset:text.Token.EndOffset])
+		} else {
+			p.expression(part, parser.LOWEST)
+		}
+	}
+}
+
+// leftmost is the expression printed first in expr ---
+func leftmost(expr ast.Expression) ast.Expression {
+	switch node := expr.(type) {
+	case *ast.BinaryExpression:
+		return leftmost(node.Left)
+	case *ast.TernaryExpression:
+		return leftmost(node.Consequence)
+	case *ast.CallExpression:
+		return leftmost(node.Function)
+	case *ast.IndexExpression:
+		return leftmost(node.Target)
+	case *ast.IndexSliceExpression:
+		return leftmost(node.Target)
+	case *ast.AssignmentExpression:
+		return leftmost(node.Assignee)
+	case *ast.IndexAssignmentExpression:
+		return leftmost(node.Target)
+	}
+
+	return expr
+}
+
+// containsSlice looks for a slice outside of parentheses, ---
+// brackets and function bodies, where { would be ambiguous ---
+func containsSlice(expr ast.Expression) bool {
+	switch node := expr.(type) {
+	case *ast.IndexSliceExpression:
+		return true
+	case *ast.BinaryExpression:
+		return containsSlice(node.Left) || containsSlice(node.Right)
+	case *ast.TernaryExpression:
+		return containsSlice(node.Consequence) || containsSlice(node.Condition) || containsSlice(node.Alternative)
+	case *ast.UnaryExpression:
+		return containsSlice(node.Right)
+	case *ast.CallExpression:
+		for _, arg := range node.Arguments {
+			if containsSlice(arg) {
+				return true
+			}
+		}
+		return containsSlice(node.Function)
+	case *ast.IndexExpression:
+		return containsSlice(node.Target)
+	case *ast.AwaitExpression:
+		return containsSlice(node.Value)
+	case *ast.SpawnExpression:
+		return containsSlice(node.Call)
+	}
+
+	return false
+}
+
+// containsBitwiseOr looks for a | outside of parentheses and brackets ---
+func containsBitwiseOr(expr ast.Expression) bool {
+	switch node := expr.(type) {
+	case *ast.BinaryExpression:
+		return node.Operator.Type == token.PIPE || containsBitwiseOr(node.Left) || containsBitwiseOr(node.Right)
+	case *ast.TernaryExpression:
+		return containsBitwiseOr(node.Consequence) || containsBitwiseOr(node.Condition) || containsBitwiseOr(node.Alternative)
+	case *ast.UnaryExpression:
+		return containsBitwiseOr(node.Right)
+	case *ast.AssignmentExpression:
+		return containsBitwiseOr(node.NewValue)
+	case *ast.AwaitExpression:
+		return containsBitwiseOr(node.Value)
+	}
+
+	return false
+}
@@ -1,6 +1,11 @@
 package lexer
 
-import "github.com/caelondev/monkey-compiler-go/src/token"
+import (
+	"sort"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
 
 type Lexer struct {
 	source          string
@@ -9,21 +14,45 @@ type Lexer struct {
 	currentChar     byte
 	line            uint
 	column          uint
+
+	// Comments are skipped by NextToken but kept as trivia ---
+	comments []token.Token
+
+	// Offsets where each line starts, for positions of arbitrary bytes ---
+	lineStarts []int
+
+	// With keepTrivia whitespace and comments are collected too, ---
+	// until TakeTrivia hands them over ---
+	keepTrivia bool
+	trivia     []token.Token
+
+	// The rest of an interpolated string, read in one go ---
+	pending []token.Token
 }
 
 func New(source string) *Lexer {
 	lexer := Lexer{
-		source: source,
-		line:   1,
-		column: 0,
+		source:     source,
+		line:       1,
+		column:     0,
+		lineStarts: []int{0},
 	}
 	lexer.readChar()
 	return &lexer
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
+	if len(l.pending) != 0 {
+		tok := l.pending[0]
+		l.pending = l.pending[1:]
+		return tok
+	}
 
+	l.skipTrivia()
+	return l.scanToken()
+}
+
+func (l *Lexer) skipTrivia() {
 	for {
 		l.skipWhitespace()
 		if l.currentChar == '/' && (l.peekChar() == '/' || l.peekChar() == '*') {
@@ -32,35 +61,59 @@ func (l *Lexer) NextToken() token.Token {
 			break
 		}
 	}
+}
+
+// scanToken reads the token at the current char ---
+func (l *Lexer) scanToken() token.Token {
+	var tok token.Token
 
 	// Capture position at START of token
 	startLine := l.line
 	startColumn := l.column
+	startOffset := l.offset()
 
 	switch l.currentChar {
 	case ';':
 		tok = l.newTokenWithPos(token.SEMICOLON, l.currentChar, startLine, startColumn)
 		l.readChar()
 	case '^':
-		tok = l.newTokenWithPos(token.CARET, l.currentChar, startLine, startColumn)
+		tok = l.newCompound(token.CARET, token.CARET_ASSIGN, startLine, startColumn)
 		l.readChar()
 	case '+':
-		tok = l.newTokenWithPos(token.PLUS, l.currentChar, startLine, startColumn)
+		if l.peekChar() == '+' {
+			tok = l.newPair(token.INCREMENT, startLine, startColumn)
+		} else {
+			tok = l.newCompound(token.PLUS, token.PLUS_ASSIGN, startLine, startColumn)
+		}
 		l.readChar()
 	case '-':
-		tok = l.newTokenWithPos(token.MINUS, l.currentChar, startLine, startColumn)
+		if l.peekChar() == '-' && l.endsOperand(l.currentPosition+1) {
+			tok = l.newPair(token.DECREMENT, startLine, startColumn)
+		} else {
+			tok = l.newCompound(token.MINUS, token.MINUS_ASSIGN, startLine, startColumn)
+		}
 		l.readChar()
 	case '*':
-		tok = l.newTokenWithPos(token.STAR, l.currentChar, startLine, startColumn)
+		tok = l.newCompound(token.STAR, token.STAR_ASSIGN, startLine, startColumn)
 		l.readChar()
 	case '/':
-		tok = l.newTokenWithPos(token.SLASH, l.currentChar, startLine, startColumn)
+		tok = l.newCompound(token.SLASH, token.SLASH_ASSIGN, startLine, startColumn)
 		l.readChar()
 	case '|':
 		tok = l.newTokenWithPos(token.PIPE, l.currentChar, startLine, startColumn)
 		l.readChar()
 	case '~':
-		tok = l.newTokenWithPos(token.TILDE, l.currentChar, startLine, startColumn)
+		if l.peekChar() == '/' {
+			tok = l.newPair(token.FLOOR_DIVIDE, startLine, startColumn)
+		} else {
+			tok = l.newTokenWithPos(token.TILDE, l.currentChar, startLine, startColumn)
+		}
+		l.readChar()
+	case '%':
+		tok = l.newTokenWithPos(token.PERCENT, l.currentChar, startLine, startColumn)
+		l.readChar()
+	case '&':
+		tok = l.newTokenWithPos(token.AMPERSAND, l.currentChar, startLine, startColumn)
 		l.readChar()
 	case '!':
 		tok = l.newCompound(token.BANG, token.NOT_EQUAL, startLine, startColumn)
@@ -69,10 +122,18 @@ func (l *Lexer) NextToken() token.Token {
 		tok = l.newCompound(token.ASSIGNMENT, token.EQUAL, startLine, startColumn)
 		l.readChar()
 	case '<':
-		tok = l.newCompound(token.LESS, token.LESS_EQUAL, startLine, startColumn)
+		if l.peekChar() == '<' {
+			tok = l.newPair(token.SHIFT_LEFT, startLine, startColumn)
+		} else {
+			tok = l.newCompound(token.LESS, token.LESS_EQUAL, startLine, startColumn)
+		}
 		l.readChar()
 	case '>':
-		tok = l.newCompound(token.GREATER, token.GREATER_EQUAL, startLine, startColumn)
+		if l.peekChar() == '>' {
+			tok = l.newPair(token.SHIFT_RIGHT, startLine, startColumn)
+		} else {
+			tok = l.newCompound(token.GREATER, token.GREATER_EQUAL, startLine, startColumn)
+		}
 		l.readChar()
 	case ':':
 		tok = l.newTokenWithPos(token.COLON, l.currentChar, startLine, startColumn)
@@ -99,8 +160,26 @@ func (l *Lexer) NextToken() token.Token {
 		tok = l.newTokenWithPos(token.RIGHT_BRACE, l.currentChar, startLine, startColumn)
 		l.readChar()
 
+	case '.':
+		if strings.HasPrefix(l.source[l.lastPosition:], "...") {
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "...", Line: startLine, Column: startColumn}
+			l.readChar()
+			l.readChar()
+			l.readChar()
+		} else if isNumber(l.peekChar()) {
+			tok = l.readNumber(startLine, startColumn)
+		} else {
+			tok = l.newTokenWithPos(token.ILLEGAL, l.currentChar, startLine, startColumn)
+			l.readChar()
+		}
+
 	case '\'', '"':
 		tok = l.readString(l.currentChar, startLine, startColumn)
+		if tok.Type == token.STRING_HEAD {
+			return tok // Its parts are spanned already ---
+		}
+	case '`':
+		tok = l.readRawString(startLine, startColumn)
 
 	case 0:
 		tok = token.Token{Type: token.EOF, Literal: "EOF", Line: startLine, Column: startColumn}
@@ -115,57 +194,44 @@ func (l *Lexer) NextToken() token.Token {
 				Column:  startColumn,
 			}
 		} else if isNumber(l.currentChar) {
-			literal := l.readNumber()
-			tok = token.Token{
-				Type:    token.NUMBER,
-				Literal: literal,
-				Line:    startLine,
-				Column:  startColumn,
-			}
+			tok = l.readNumber(startLine, startColumn)
 		} else {
 			tok = l.newTokenWithPos(token.ILLEGAL, l.currentChar, startLine, startColumn)
 			l.readChar()
 		}
 	}
 
+	tok.Offset = startOffset
+	l.finish(&tok)
 	return tok
 }
 
-func (l *Lexer) readString(terminator byte, line, column uint) token.Token {
-	// consume opening quote
-	l.readChar()
-	start := l.lastPosition
+// finish sets where tok ends, at the lexer's current position ---
+func (l *Lexer) finish(tok *token.Token) {
+	end := l.Position(l.offset())
+	tok.EndOffset, tok.EndLine, tok.EndColumn = end.Offset, end.Line, end.Column
+}
 
-	for {
-		if l.currentChar == 0 {
-			return l.errorToken("unterminated string", line, column)
-		}
-		if l.currentChar == '\n' {
-			return l.errorToken("string literal cannot span multiple lines", line, column)
-		}
-		if l.currentChar == terminator {
-			lit := l.source[start:l.lastPosition]
-			l.readChar() // consume closing quote
-			return token.Token{
-				Type:    token.STRING,
-				Literal: lit,
-				Line:    line,
-				Column:  column,
-			}
-		}
-		l.readChar()
-	}
+// offset is the offset of the current char, the end of the source past it ---
+func (l *Lexer) offset() int {
+	return min(l.lastPosition, len(l.source))
 }
 
-func (l *Lexer) readNumber() string {
-	start := l.lastPosition
-	for isNumber(l.currentChar) {
-		l.readChar()
+// Position is the line and column of the byte at offset, ---
+// which must have been read already ---
+func (l *Lexer) Position(offset int) token.Position {
+	line := sort.Search(len(l.lineStarts), func(i int) bool { return l.lineStarts[i] > offset }) - 1
+	return token.Position{
+		Offset: offset,
+		Line:   uint(line + 1),
+		Column: uint(offset-l.lineStarts[line]) + 1,
 	}
-	return l.source[start:l.lastPosition]
 }
 
 func (l *Lexer) skipWhitespace() {
+	start := l.offset()
+	defer func() { l.addTrivia(token.WHITESPACE, start) }()
+
 	for {
 		switch l.currentChar {
 		case ' ', '\r', '\t':
@@ -179,13 +245,23 @@ func (l *Lexer) skipWhitespace() {
 }
 
 func (l *Lexer) skipComments() {
+	line, column := l.line, l.column
+	start := l.lastPosition
+
 	if l.currentChar == '/' {
 		if l.peekChar() == '/' {
 			l.readChar()
 			for !l.isAtEnd() && l.currentChar != '\n' {
 				l.readChar()
 			}
-			l.readChar()
+			end := l.lastPosition
+			if l.currentChar != '\n' {
+				end = min(end+1, len(l.source)) // Ends at the end of the file ---
+			}
+			l.addComment(l.source[start:end], line, column)
+			if l.currentChar != '\n' {
+				l.readChar()
+			}
 		} else if l.peekChar() == '*' {
 			l.readChar()
 			for !l.isAtEnd() && !(l.currentChar == '*' && l.peekChar() == '/') {
@@ -193,9 +269,68 @@ func (l *Lexer) skipComments() {
 			}
 			l.readChar()
 			l.readChar()
+			l.addComment(l.source[start:min(l.lastPosition, len(l.source))], line, column)
 		}
 	}
-	l.skipWhitespace()
+
+	// The newline after a line comment is whitespace ---
+	l.addTrivia(token.COMMENT, start)
+}
+
+func (l *Lexer) addComment(text string, line, column uint) {
+	l.comments = append(l.comments, token.Token{
+		Type:    token.COMMENT,
+		Literal: strings.TrimRight(text, " \t\r"),
+		Line:    line,
+		Column:  column,
+	})
+}
+
+// addTrivia keeps what was skipped since start ---
+func (l *Lexer) addTrivia(tokenType token.TokenType, start int) {
+	end := l.offset()
+	if !l.keepTrivia || end == start {
+		return
+	}
+
+	pos := l.Position(start)
+	tok := token.Token{
+		Type:    tokenType,
+		Literal: l.source[start:end],
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Offset:  start,
+	}
+	l.finish(&tok)
+	l.trivia = append(l.trivia, tok)
+}
+
+// KeepTrivia makes the lexer collect the whitespace and comments ---
+// it skips, call it before the first NextToken ---
+func (l *Lexer) KeepTrivia() {
+	l.keepTrivia = true
+}
+
+func (l *Lexer) KeepsTrivia() bool {
+	return l.keepTrivia
+}
+
+func (l *Lexer) Source() string {
+	return l.source
+}
+
+// TakeTrivia returns the trivia skipped since the last call, it ---
+// comes before the token NextToken returned last, or inside the ---
+// interpolated string it started ---
+func (l *Lexer) TakeTrivia() []token.Token {
+	trivia := l.trivia
+	l.trivia = nil
+	return trivia
+}
+
+// Comments returns the comments skipped so far, in source order ---
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
 }
 
 func (l *Lexer) readIdentifier() string {
@@ -226,6 +361,7 @@ func (l *Lexer) readChar() {
 	if l.currentChar == '\n' {
 		l.line++
 		l.column = 0
+		l.lineStarts = append(l.lineStarts, l.currentPosition)
 	} else {
 		l.column++
 	}
@@ -246,6 +382,45 @@ func (l *Lexer) newCompound(regular, compound token.TokenType, line, column uint
 	}
 }
 
+// endsOperand reports whether the source from offset (past spaces and ---
+// block comments) closes an operand: the end of the line or input, a ---
+// line comment or one of ; ) ] } ,. x-- is only a decrement there, so ---
+// 5--3 and x--1 stay a subtraction of a negated operand ---
+func (l *Lexer) endsOperand(offset int) bool {
+	for offset < len(l.source) {
+		if l.source[offset] == ' ' || l.source[offset] == '\t' {
+			offset++
+		} else if strings.HasPrefix(l.source[offset:], "/*") {
+			end := strings.Index(l.source[offset+2:], "*/")
+			if end == -1 {
+				return true
+			}
+			offset += end + 4
+		} else {
+			break
+		}
+	}
+
+	if offset >= len(l.source) || strings.HasPrefix(l.source[offset:], "//") {
+		return true
+	}
+
+	return strings.IndexByte(";)]},\r\n", l.source[offset]) != -1
+}
+
+// newPair reads a two char operator like <<, leaving its second char current ---
+func (l *Lexer) newPair(tokenType token.TokenType, line, column uint) token.Token {
+	start := l.currentChar
+	l.readChar()
+
+	return token.Token{
+		Type:    tokenType,
+		Literal: string(start) + string(l.currentChar),
+		Line:    line,
+		Column:  column,
+	}
+}
+
 func (l *Lexer) newTokenWithPos(token_type token.TokenType, c byte, line, column uint) token.Token {
 	return token.Token{
 		Type:    token_type,
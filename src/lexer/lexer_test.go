@@ -0,0 +1,45 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+func TestDecrement(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected []token.TokenType
+	}{
+		{"x--;", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.SEMICOLON}},
+		{"x--", []token.TokenType{token.IDENTIFIER, token.DECREMENT}},
+		{"f(x--)", []token.TokenType{token.IDENTIFIER, token.LEFT_PARENTHESIS, token.IDENTIFIER, token.DECREMENT, token.RIGHT_PARENTHESIS}},
+		{"x--\n}", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.RIGHT_BRACE}},
+		{"x--\r\ny", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.IDENTIFIER}},
+		{"x-- // dec\nprint(x)", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.IDENTIFIER, token.LEFT_PARENTHESIS, token.IDENTIFIER, token.RIGHT_PARENTHESIS}},
+		{"x-- /* dec */;", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.SEMICOLON}},
+		{"5--3", []token.TokenType{token.NUMBER, token.MINUS, token.MINUS, token.NUMBER}},
+		{"x--1", []token.TokenType{token.IDENTIFIER, token.MINUS, token.MINUS, token.NUMBER}},
+		{"x -- y", []token.TokenType{token.IDENTIFIER, token.MINUS, token.MINUS, token.IDENTIFIER}},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.source)
+
+		got := make([]token.TokenType, 0)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			got = append(got, tok.Type)
+		}
+
+		if len(got) != len(tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.source, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("%q: expected %v, got %v", tt.source, tt.expected, got)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// Suffixes that make a literal exact: 123n is a BigInt, 1.10m a Decimal ---
+const (
+	BigIntSuffix  = "n"
+	DecimalSuffix = "m"
+)
+
+// readNumber reads 12, 1_000, 1.5, .5, 6.02e23, 0xFF, 0o17, 0b1010, ---
+// 123n and 1.10m. The literal keeps the source text, IntegerValue, ---
+// NumberValue, BigIntValue or DecimalValue gives its value ---
+func (l *Lexer) readNumber(line, column uint) token.Token {
+	start := l.lastPosition
+	prefixed := l.currentChar == '0' && strings.ContainsRune("xXoObB", rune(l.peekChar()))
+
+	// Everything that could belong to the number, so 1.2.3 and ---
+	// 12abc are reported whole ---
+	for isAlphanumeric(l.currentChar) || l.currentChar == '.' {
+		exponent := !prefixed && (l.currentChar == 'e' || l.currentChar == 'E')
+		l.readChar()
+
+		if exponent && (l.currentChar == '+' || l.currentChar == '-') {
+			l.readChar()
+		}
+	}
+
+	literal := l.source[start:l.lastPosition]
+
+	var err error
+	switch {
+	case strings.HasSuffix(literal, BigIntSuffix):
+		_, err = BigIntValue(literal)
+	case strings.HasSuffix(literal, DecimalSuffix):
+		_, _, err = DecimalValue(literal)
+	case IsInteger(literal):
+		_, err = IntegerValue(literal)
+	default:
+		_, err = NumberValue(literal)
+	}
+
+	if err != nil {
+		return l.errorToken(err.Error(), line, column)
+	}
+
+	return token.Token{Type: token.NUMBER, Literal: literal, Line: line, Column: column}
+}
+
+var decimalSyntax = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// NumberValue is the value of a number literal the lexer accepted, ---
+// the error says what's wrong with one it didn't ---
+func NumberValue(literal string) (float64, error) {
+	if _, ok := basePrefix(literal); ok {
+		value, err := IntegerValue(literal)
+		return float64(value), err
+	}
+
+	digits, err := decimalDigits(literal)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseFloat(digits, 64)
+	if errors.Is(err, strconv.ErrRange) && value != 0 {
+		return 0, fmt.Errorf("Number literal '%s' is out of range", literal)
+	}
+
+	// Too small to represent is zero, like any float ---
+	return value, nil
+}
+
+// IsInteger reports whether literal is a plain integer: it has no ---
+// suffix, and a base prefix or neither a '.' nor an exponent ---
+func IsInteger(literal string) bool {
+	if strings.HasSuffix(literal, BigIntSuffix) || strings.HasSuffix(literal, DecimalSuffix) {
+		return false
+	}
+	if _, ok := basePrefix(literal); ok {
+		return true
+	}
+
+	return !strings.ContainsAny(literal, ".eE")
+}
+
+// IntegerValue is the value of an integer literal, which must fit ---
+// in a signed 64-bit integer ---
+func IntegerValue(literal string) (int64, error) {
+	digits, base, err := integerDigits(literal, literal)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(digits, base, 64)
+	if errors.Is(err, strconv.ErrRange) || value > math.MaxInt64 {
+		return 0, fmt.Errorf("Integer literal '%s' is too large for 64 bits, write %s%s for a BigInt", literal, literal, BigIntSuffix)
+	}
+
+	return int64(value), nil
+}
+
+// BigIntValue is the value of a 123n literal ---
+func BigIntValue(literal string) (*big.Int, error) {
+	body := strings.TrimSuffix(literal, BigIntSuffix)
+	if !IsInteger(body) {
+		return nil, fmt.Errorf("BigInt literal '%s' must be a whole number", literal)
+	}
+
+	digits, base, err := integerDigits(body, literal)
+	if err != nil {
+		return nil, err
+	}
+
+	value, _ := new(big.Int).SetString(digits, base)
+	return value, nil
+}
+
+// DecimalValue is the value of a 1.10m literal as unscaled / 10^scale, ---
+// so 1.10m is 110 and 2. An exponent moves the point: 1.5e3m is 1500 ---
+func DecimalValue(literal string) (unscaled *big.Int, scale int32, err error) {
+	body := strings.TrimSuffix(literal, DecimalSuffix)
+	if _, ok := basePrefix(body); ok {
+		return nil, 0, fmt.Errorf("Decimal literal '%s' can't have a base prefix", literal)
+	}
+
+	digits, err := decimalDigits(body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mantissa, exponent, _ := strings.Cut(strings.ToLower(digits), "e")
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+
+	shift := 0
+	if exponent != "" {
+		if shift, err = strconv.Atoi(exponent); err != nil || shift > math.MaxInt16 || shift < math.MinInt16 {
+			return nil, 0, fmt.Errorf("Decimal literal '%s' has too large an exponent", literal)
+		}
+	}
+
+	unscaled, _ = new(big.Int).SetString(whole+fraction, 10)
+	scale = int32(len(fraction) - shift)
+
+	// No negative scales, the point only moves right of the digits ---
+	if scale < 0 {
+		unscaled.Mul(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+
+	return unscaled, scale, nil
+}
+
+// decimalDigits checks a literal without a base prefix and drops its _ ---
+func decimalDigits(literal string) (string, error) {
+	if !separatedDigits(literal, 10) {
+		return "", fmt.Errorf("Misplaced '_' in '%s', it only goes between digits", literal)
+	}
+
+	digits := strings.ReplaceAll(literal, "_", "")
+	if !decimalSyntax.MatchString(digits) {
+		return "", fmt.Errorf("Malformed number '%s'", literal)
+	}
+
+	return digits, nil
+}
+
+// integerDigits checks an integer literal, whole is the literal as ---
+// written for the errors. It gives the digits without their prefix ---
+// and _, and their base ---
+func integerDigits(literal, whole string) (string, int, error) {
+	prefix, ok := basePrefix(literal)
+	if !ok {
+		digits, err := decimalDigits(literal)
+		return digits, 10, err
+	}
+
+	digits := literal[2:]
+	if digits == "" {
+		return "", 0, fmt.Errorf("Expected %s digits after '%s'", prefix.name, whole)
+	}
+	if !separatedDigits(digits, prefix.base) {
+		return "", 0, fmt.Errorf("Misplaced '_' in '%s', it only goes between digits", whole)
+	}
+
+	digits = strings.ReplaceAll(digits, "_", "")
+	if _, ok := new(big.Int).SetString(digits, prefix.base); !ok {
+		return "", 0, fmt.Errorf("Invalid digit in %s literal '%s'", prefix.name, whole)
+	}
+
+	return digits, prefix.base, nil
+}
+
+type base struct {
+	base int
+	name string
+}
+
+var bases = map[byte]base{
+	'x': {16, "hex"},
+	'o': {8, "octal"},
+	'b': {2, "binary"},
+}
+
+func basePrefix(literal string) (base, bool) {
+	if len(literal) < 2 || literal[0] != '0' {
+		return base{}, false
+	}
+
+	prefix, ok := bases[literal[1]|0x20]
+	return prefix, ok
+}
+
+// separatedDigits reports whether every _ in text sits between two digits ---
+func separatedDigits(text string, base int) bool {
+	digit := func(c byte) bool {
+		if base == 16 {
+			return isNumber(c) || ('a' <= c|0x20 && c|0x20 <= 'f')
+		}
+		return isNumber(c)
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == '_' && (i == 0 || i == len(text)-1 || !digit(text[i-1]) || !digit(text[i+1])) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,369 @@
+package lexer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// readString reads a '...' or "..." literal, three quotes open ---
+// a multi-line one. With ${...} parts it's returned as a STRING_HEAD, ---
+// the tokens after it wait in pending ---
+func (l *Lexer) readString(quote byte, line, column uint) token.Token {
+	if l.peekChar() == quote && l.peekAt(2) == quote {
+		return l.readMultilineString(quote, line, column)
+	}
+
+	parts := make([]token.Token, 0)
+	partStart := l.offset()
+
+	l.readChar() // consume opening quote
+	start := l.lastPosition
+
+	for l.currentChar != quote {
+		switch l.currentChar {
+		case 0:
+			return l.errorToken("Unterminated string", line, column)
+		case '\n':
+			return l.errorToken("String literal cannot span multiple lines, use \"\"\" or ` for multi-line strings", line, column)
+		case '\\':
+			l.readChar() // An escaped quote doesn't end the string ---
+			if l.currentChar == 0 || l.currentChar == '\n' {
+				continue
+			}
+
+		case '$':
+			if l.peekChar() != '{' {
+				break
+			}
+
+			end, open := l.lastPosition, l.Position(l.lastPosition)
+			l.readChar()
+			l.readChar() // Eat ${ ---
+
+			partType := token.TokenType(token.STRING_MIDDLE)
+			if len(parts) == 0 {
+				partType = token.STRING_HEAD
+			}
+
+			part, err := l.stringPart(partType, partStart, start, end)
+			if err != nil {
+				return *err
+			}
+
+			tokens, err := l.readInterpolation(open)
+			if err != nil {
+				return *err
+			}
+			parts = append(append(parts, part), tokens...)
+
+			partStart = l.offset()
+			l.readChar() // Eat } ---
+			start = l.lastPosition
+			continue
+		}
+		l.readChar()
+	}
+
+	end := l.lastPosition
+	l.readChar() // consume closing quote
+
+	if len(parts) != 0 {
+		tail, err := l.stringPart(token.STRING_TAIL, partStart, start, end)
+		if err != nil {
+			return *err
+		}
+
+		l.pending = append(l.pending, append(parts[1:], tail)...)
+		return parts[0]
+	}
+
+	value, err := l.unescape(l.source[start:end], start)
+	if err != nil {
+		return *err
+	}
+
+	return token.Token{Type: token.STRING, Literal: value, Line: line, Column: column}
+}
+
+// stringPart is the text of an interpolated string from start to end, ---
+// its token runs from partStart up to the current char ---
+func (l *Lexer) stringPart(partType token.TokenType, partStart, start, end int) (token.Token, *token.Token) {
+	value, err := l.unescape(l.source[start:end], start)
+	if err != nil {
+		return token.Token{}, err
+	}
+
+	pos := l.Position(partStart)
+	tok := token.Token{Type: partType, Literal: value, Line: pos.Line, Column: pos.Column, Offset: partStart}
+	l.finish(&tok)
+	return tok, nil
+}
+
+// readInterpolation reads the tokens inside ${...}, stopping at the ---
+// } that closes it. Strings inside may have their own quotes ---
+func (l *Lexer) readInterpolation(open token.Position) ([]token.Token, *token.Token) {
+	tokens := make([]token.Token, 0)
+	depth := 0
+
+	for {
+		l.skipTrivia()
+
+		if l.currentChar == 0 {
+			tok := l.errorToken("Unterminated ${ in string", open.Line, open.Column)
+			return nil, &tok
+		}
+		if l.currentChar == '}' && depth == 0 {
+			if len(tokens) == 0 {
+				tok := l.errorToken("Expected an expression inside ${}", open.Line, open.Column)
+				return nil, &tok
+			}
+			return tokens, nil
+		}
+
+		tok := l.scanToken()
+		switch tok.Type {
+		case token.ERROR:
+			return nil, &tok
+		case token.LEFT_BRACE:
+			depth++
+		case token.RIGHT_BRACE:
+			depth--
+		}
+
+		// A string inside leaves its own parts behind ---
+		tokens = append(append(tokens, tok), l.pending...)
+		l.pending = nil
+	}
+}
+
+// readMultilineString reads a """...""" literal. A line break right ---
+// after the opening quotes and a last line holding only the closing ---
+// ones are dropped, then the indentation the lines share is stripped ---
+func (l *Lexer) readMultilineString(quote byte, line, column uint) token.Token {
+	l.readChar()
+	l.readChar()
+	l.readChar() // Eat the opening quotes ---
+	start := l.lastPosition
+
+	for !(l.currentChar == quote && l.peekChar() == quote && l.peekAt(2) == quote) {
+		switch l.currentChar {
+		case 0:
+			return l.errorToken("Unterminated multi-line string", line, column)
+		case '\\':
+			l.readChar()
+			if l.currentChar == 0 {
+				continue
+			}
+		}
+		l.readChar()
+	}
+
+	end := l.lastPosition
+	l.readChar()
+	l.readChar()
+	l.readChar() // Eat the closing quotes ---
+
+	lines := strings.Split(l.source[start:end], "\n")
+	offsets := make([]int, len(lines))
+	for i, offset := 0, start; i < len(lines); i++ {
+		offsets[i] = offset
+		offset += len(lines[i]) + 1
+	}
+
+	// Text on the opening line is kept as is ---
+	opening := true
+	if len(lines) > 1 && isBlank(lines[0]) {
+		lines, offsets = lines[1:], offsets[1:]
+		opening = false
+	}
+	if len(lines) > 1 && isBlank(lines[len(lines)-1]) {
+		lines, offsets = lines[:len(lines)-1], offsets[:len(offsets)-1]
+	}
+
+	indent, first := "", true
+	for i, text := range lines {
+		if (opening && i == 0) || isBlank(text) {
+			continue
+		}
+
+		leading := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+		if first {
+			indent, first = leading, false
+		} else {
+			indent = commonPrefix(indent, leading)
+		}
+	}
+
+	for i, text := range lines {
+		text = strings.TrimSuffix(text, "\r")
+
+		switch {
+		case isBlank(text):
+			text = ""
+		case !opening || i != 0:
+			text = text[len(indent):]
+			offsets[i] += len(indent)
+		}
+
+		value, err := l.unescape(text, offsets[i])
+		if err != nil {
+			return *err
+		}
+		lines[i] = value
+	}
+
+	return token.Token{Type: token.STRING, Literal: strings.Join(lines, "\n"), Line: line, Column: column}
+}
+
+// readRawString reads a `...` literal, there are no escapes in it ---
+// and it may span lines, only \r\n line endings become \n ---
+func (l *Lexer) readRawString(line, column uint) token.Token {
+	l.readChar() // consume opening backtick
+	start := l.lastPosition
+
+	for l.currentChar != '`' {
+		if l.currentChar == 0 {
+			return l.errorToken("Unterminated raw string", line, column)
+		}
+		l.readChar()
+	}
+
+	end := l.lastPosition
+	l.readChar() // consume closing backtick
+
+	return token.Token{
+		Type:    token.STRING,
+		Literal: strings.ReplaceAll(l.source[start:end], "\r\n", "\n"),
+		Line:    line,
+		Column:  column,
+	}
+}
+
+// unescape decodes the escape sequences in raw, which sits at offset ---
+// in the source. A bad one is an error token pointing at it ---
+func (l *Lexer) unescape(raw string, offset int) (string, *token.Token) {
+	if !strings.Contains(raw, `\`) {
+		return raw, nil
+	}
+
+	fail := func(at int, format string, a ...interface{}) (string, *token.Token) {
+		pos := l.Position(offset + at)
+		tok := l.errorToken(fmt.Sprintf(format, a...), pos.Line, pos.Column)
+		return "", &tok
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			out.WriteByte(raw[i])
+			continue
+		}
+
+		escape := i
+		if i++; i == len(raw) {
+			return fail(escape, "Unfinished escape sequence")
+		}
+
+		switch raw[i] {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case '0':
+			out.WriteByte(0)
+		case '\\', '"', '\'', '$':
+			out.WriteByte(raw[i])
+
+		case 'x':
+			digits := raw[i+1 : min(i+3, len(raw))]
+			value, err := strconv.ParseUint(digits, 16, 8)
+			if len(digits) != 2 || err != nil {
+				return fail(escape, "Escape '\\x' expects 2 hex digits, like \\x41")
+			}
+			out.WriteRune(rune(value))
+			i += 2
+
+		case 'u':
+			digits, _, closed := strings.Cut(raw[i+1:], "}")
+			if !closed || !strings.HasPrefix(digits, "{") || len(digits) < 2 || len(digits) > 7 {
+				return fail(escape, "Escape '\\u' expects 1 to 6 hex digits in braces, like \\u{1F600}")
+			}
+
+			value, err := strconv.ParseUint(digits[1:], 16, 32)
+			if err != nil {
+				return fail(escape, "Escape '\\u' expects 1 to 6 hex digits in braces, like \\u{1F600}")
+			}
+			if !utf8.ValidRune(rune(value)) {
+				return fail(escape, "'\\u%s}' is not a valid code point", digits)
+			}
+			out.WriteRune(rune(value))
+			i += len(digits) + 1
+
+		default:
+			r, _ := utf8.DecodeRuneInString(raw[i:])
+			return fail(escape, "Invalid escape sequence '\\%c'", r)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// Quote writes value as a double quoted literal that reads back as value ---
+func Quote(value string) string {
+	var out strings.Builder
+	out.WriteByte('"')
+
+	for i, r := range value {
+		switch r {
+		case '"', '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case '$':
+			if strings.HasPrefix(value[i:], "${") {
+				out.WriteByte('\\') // Written as is, not interpolated ---
+			}
+			out.WriteRune(r)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '\r':
+			out.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&out, `\x%02x`, r)
+			} else {
+				out.WriteRune(r)
+			}
+		}
+	}
+
+	out.WriteByte('"')
+	return out.String()
+}
+
+// peekAt is the char n past the current one ---
+func (l *Lexer) peekAt(n int) byte {
+	if l.lastPosition+n >= len(l.source) {
+		return 0
+	}
+	return l.source[l.lastPosition+n]
+}
+
+func isBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
@@ -0,0 +1,734 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/evaluation"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// sourcePos is one-based like token positions ---
+type sourcePos struct {
+	line, column uint
+}
+
+var endOfFile = sourcePos{line: math.MaxUint32}
+
+// Function calls and loop iterations a document's macros may take ---
+const MACRO_STEPS = 100_000
+
+func (p sourcePos) before(other sourcePos) bool {
+	return p.line < other.line || (p.line == other.line && p.column < other.column)
+}
+
+func identPos(ident *ast.Identifier) sourcePos {
+	return sourcePos{ident.Token.Line, ident.Token.Column}
+}
+
+type symbolKind int
+
+const (
+	variableSymbol symbolKind = iota
+	parameterSymbol
+	functionSymbol
+)
+
+type symbol struct {
+	Name       string
+	Kind       symbolKind
+	Definition *ast.Identifier
+	References []*ast.Identifier // The definition included ---
+
+	Type        string            // Inferred object type, "" when unknown ---
+	Parameters  []*ast.Identifier // Functions only ---
+	IsGenerator bool
+}
+
+// scope mirrors the compiler's symbol tables, ---
+// only functions open one ---
+type scope struct {
+	outer      *scope
+	symbols    map[string]*symbol
+	start, end sourcePos
+}
+
+func (s *scope) resolve(name string) (*symbol, bool) {
+	for ; s != nil; s = s.outer {
+		if sym, ok := s.symbols[name]; ok {
+			return sym, true
+		}
+	}
+
+	return nil, false
+}
+
+// occurrence is an identifier and what it resolved to, ---
+// builtin is set instead of symbol for builtin names ---
+type occurrence struct {
+	ident   *ast.Identifier
+	symbol  *symbol
+	builtin string
+}
+
+// analysis is everything the server knows about one document ---
+type analysis struct {
+	diagnostics []diagnostic
+	occurrences []occurrence
+	scopes      []*scope
+	functions   []documentSymbol
+
+	blockEnds map[sourcePos]sourcePos // '{' to just past its '}' ---
+	lines     []string                // Converts byte columns to LSP characters ---
+}
+
+func analyze(source string) *analysis {
+	a := &analysis{
+		diagnostics: make([]diagnostic, 0),
+		blockEnds:   matchBraces(source),
+		lines:       strings.Split(source, "\n"),
+	}
+
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+
+	global := a.openScope(nil, sourcePos{1, 1}, endOfFile)
+	a.functions = a.walk(program, global)
+
+	for _, msg := range p.Errors() {
+		a.diagnostics = append(a.diagnostics, a.parseDiagnostic(msg))
+	}
+
+	// A program that doesn't parse can't be compiled ---
+	if len(p.Errors()) == 0 {
+		a.compile(program)
+	}
+
+	return a
+}
+
+func (a *analysis) compile(program *ast.Program) {
+	// Macro bodies run here, the protocol owns stdin and stdout and ---
+	// a body that never returns mustn't hang the server ---
+	expander := evaluation.New()
+	expander.Stdout = io.Discard
+	expander.Stdin = strings.NewReader("")
+	expander.MaxSteps = MACRO_STEPS
+
+	macros := object.NewEnvironment(nil)
+	expander.DefineMacros(program, macros)
+	program, expandErr := expander.ExpandMacros(program, macros)
+	if expandErr != nil {
+		pos := sourcePos{expandErr.Line, expandErr.Column}
+		a.diagnostics = append(a.diagnostics, a.newDiagnostic(pos, 1, expandErr.Message))
+		return
+	}
+
+	err := compiler.New().Compile(program)
+	if err == nil {
+		return
+	}
+
+	var compileErr *compiler.Error
+	if !errors.As(err, &compileErr) {
+		a.diagnostics = append(a.diagnostics, a.newDiagnostic(sourcePos{1, 1}, 1, err.Error()))
+		return
+	}
+
+	pos := sourcePos{uint(compileErr.Line), uint(compileErr.Column)}
+	length := 1
+	if occ, ok := a.occurrenceAt(pos); ok {
+		length = len(occ.ident.Value)
+	}
+
+	a.diagnostics = append(a.diagnostics, a.newDiagnostic(pos, length, compileErr.Message))
+}
+
+// Parser messages are prefixed with "[Ln <line>:<column>] ->" ---
+func (a *analysis) parseDiagnostic(msg string) diagnostic {
+	var line, column uint
+	if n, _ := fmt.Sscanf(msg, "[Ln %d:%d]", &line, &column); n != 2 {
+		return a.newDiagnostic(sourcePos{1, 1}, 1, msg)
+	}
+
+	rest := strings.TrimSpace(msg[strings.Index(msg, "]")+1:])
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "->"))
+	return a.newDiagnostic(sourcePos{line, column}, 1, rest)
+}
+
+func (a *analysis) newDiagnostic(pos sourcePos, length int, msg string) diagnostic {
+	return diagnostic{
+		Range:    a.spanRange(pos, length),
+		Severity: severityError,
+		Source:   "monkey",
+		Message:  msg,
+	}
+}
+
+// LSP characters count UTF-16 code units, token columns count bytes. ---
+// Columns past the end of the line count one character per byte ---
+func (a *analysis) toPosition(pos sourcePos) position {
+	line := a.line(pos.line)
+	column := max(int(pos.column)-1, 0)
+
+	prefix := line[:min(column, len(line))]
+	character := max(column-len(line), 0)
+	for _, r := range prefix {
+		character += utf16.RuneLen(r)
+	}
+
+	return position{Line: max(int(pos.line)-1, 0), Character: character}
+}
+
+func (a *analysis) fromPosition(pos position) sourcePos {
+	line := a.line(uint(pos.Line + 1))
+
+	offset, character := 0, 0
+	for offset < len(line) && character < pos.Character {
+		r, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+		character += utf16.RuneLen(r)
+	}
+	offset += max(pos.Character-character, 0)
+
+	return sourcePos{uint(pos.Line + 1), uint(offset + 1)}
+}
+
+func (a *analysis) line(line uint) string {
+	if line == 0 || int(line) > len(a.lines) {
+		return ""
+	}
+
+	return a.lines[line-1]
+}
+
+// spanRange covers length bytes from pos ---
+func (a *analysis) spanRange(pos sourcePos, length int) textRange {
+	return textRange{Start: a.toPosition(pos), End: a.toPosition(sourcePos{pos.line, pos.column + uint(length)})}
+}
+
+func (a *analysis) identRange(ident *ast.Identifier) textRange {
+	return a.spanRange(identPos(ident), len(ident.Value))
+}
+
+// matchBraces pairs every '{' with its '}', unclosed ones run to the end of the file ---
+func matchBraces(source string) map[sourcePos]sourcePos {
+	ends := make(map[sourcePos]sourcePos)
+	open := make([]sourcePos, 0)
+
+	l := lexer.New(source)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		pos := sourcePos{tok.Line, tok.Column}
+
+		switch tok.Type {
+		case token.LEFT_BRACE:
+			open = append(open, pos)
+		case token.RIGHT_BRACE:
+			if len(open) == 0 {
+				continue
+			}
+			ends[open[len(open)-1]] = sourcePos{pos.line, pos.column + 1}
+			open = open[:len(open)-1]
+		}
+	}
+
+	for _, pos := range open {
+		ends[pos] = endOfFile
+	}
+
+	return ends
+}
+
+func (a *analysis) blockEnd(block *ast.BlockStatement) sourcePos {
+	if end, ok := a.blockEnds[sourcePos{block.Token.Line, block.Token.Column}]; ok {
+		return end
+	}
+
+	return endOfFile
+}
+
+func (a *analysis) openScope(outer *scope, start, end sourcePos) *scope {
+	s := &scope{outer: outer, symbols: make(map[string]*symbol), start: start, end: end}
+	a.scopes = append(a.scopes, s)
+	return s
+}
+
+func (a *analysis) define(s *scope, ident *ast.Identifier, sym *symbol) {
+	sym.Name = ident.Value
+	sym.Definition = ident
+	sym.References = []*ast.Identifier{ident}
+
+	s.symbols[ident.Value] = sym
+	a.occurrences = append(a.occurrences, occurrence{ident: ident, symbol: sym})
+}
+
+func (a *analysis) reference(s *scope, ident *ast.Identifier) {
+	if sym, ok := s.resolve(ident.Value); ok {
+		sym.References = append(sym.References, ident)
+		a.occurrences = append(a.occurrences, occurrence{ident: ident, symbol: sym})
+		return
+	}
+
+	if _, ok := builtins[ident.Value]; ok {
+		a.occurrences = append(a.occurrences, occurrence{ident: ident, builtin: ident.Value})
+	}
+}
+
+// walk resolves identifiers in source order like the compiler does, ---
+// returning the fn declarations found for document symbols ---
+func (a *analysis) walk(node ast.Node, s *scope) []documentSymbol {
+	if ast.IsNil(node) {
+		return nil
+	}
+
+	functions := make([]documentSymbol, 0)
+	visit := func(nodes ...ast.Node) {
+		for _, node := range nodes {
+			functions = append(functions, a.walk(node, s)...)
+		}
+	}
+
+	switch node := node.(type) {
+	case *ast.Program:
+		for _, stmt := range node.Statements {
+			visit(stmt)
+		}
+
+	case *ast.BlockStatement:
+		for _, stmt := range node.Statements {
+			visit(stmt)
+		}
+
+	case *ast.VarStatement:
+		visit(node.Value)
+		if !ast.IsNil(node.Pattern) {
+			functions = append(functions, a.pattern(node.Pattern, s, func(name *ast.Identifier) {
+				a.define(s, name, &symbol{Kind: variableSymbol})
+			})...)
+		}
+		for _, name := range node.Names {
+			a.define(s, name, a.valueSymbol(node.Value, s))
+		}
+
+	case *ast.FunctionDeclarationStatement:
+		// Defined first, so the body can call itself ---
+		if ast.IsNil(node.Name) {
+			return functions
+		}
+		a.define(s, node.Name, &symbol{
+			Kind:        functionSymbol,
+			Type:        "FUNCTION",
+			Parameters:  node.Parameters,
+			IsGenerator: node.IsGenerator,
+		})
+
+		children := a.function(node.Parameters, node.Patterns, node.Body, s)
+		if ast.IsNil(node.Body) {
+			return functions
+		}
+
+		functions = append(functions, documentSymbol{
+			Name:   node.Name.Value,
+			Detail: signature(node.Name.Value, node.Parameters),
+			Kind:   symbolFunction,
+			Range: textRange{
+				Start: a.toPosition(sourcePos{node.Token.Line, node.Token.Column}),
+				End:   a.toPosition(a.blockEnd(node.Body)),
+			},
+			SelectionRange: a.identRange(node.Name),
+			Children:       children,
+		})
+
+	case *ast.FunctionLiteral:
+		functions = append(functions, a.function(node.Parameters, node.Patterns, node.Body, s)...)
+	case *ast.MacroLiteral:
+		functions = append(functions, a.function(node.Parameters, nil, node.Body, s)...)
+
+	case *ast.ForInStatement:
+		visit(node.Iterable)
+		if !ast.IsNil(node.Variable) {
+			a.define(s, node.Variable, &symbol{Kind: variableSymbol})
+		}
+		visit(node.Body)
+
+	case *ast.TryStatement:
+		visit(node.Block)
+		if !ast.IsNil(node.CatchParam) {
+			a.define(s, node.CatchParam, &symbol{Kind: variableSymbol})
+		}
+		visit(node.Catch, node.Finally)
+
+	case *ast.BatchAssignmentStatement:
+		visit(node.NewValue)
+		if !ast.IsNil(node.Pattern) {
+			functions = append(functions, a.pattern(node.Pattern, s, func(name *ast.Identifier) {
+				a.reference(s, name)
+			})...)
+		}
+		for _, assignee := range node.Assignees {
+			a.reference(s, assignee)
+		}
+
+	case *ast.Identifier:
+		a.reference(s, node)
+
+	case *ast.ReturnStatement:
+		visit(node.ReturnValue)
+	case *ast.ExpressionStatement:
+		visit(node.Expression)
+	case *ast.IfStatement:
+		visit(node.Condition, node.Consequence, node.Alternative)
+	case *ast.ThrowStatement:
+		visit(node.Value)
+	case *ast.UnaryExpression:
+		visit(node.Right)
+	case *ast.BinaryExpression:
+		visit(node.Left, node.Right)
+	case *ast.TernaryExpression:
+		visit(node.Condition, node.Consequence, node.Alternative)
+	case *ast.AssignmentExpression:
+		visit(node.Assignee, node.NewValue)
+	case *ast.IndexExpression:
+		visit(node.Target, node.Index)
+	case *ast.IndexAssignmentExpression:
+		visit(node.Target, node.Index, node.NewValue)
+	case *ast.IndexSliceExpression:
+		visit(node.Target, node.Start, node.End)
+	case *ast.AbsoluteExpression:
+		visit(node.Value)
+	case *ast.AwaitExpression:
+		visit(node.Value)
+	case *ast.YieldExpression:
+		visit(node.Value)
+	case *ast.SpawnExpression:
+		visit(node.Call)
+
+	case *ast.CallExpression:
+		visit(node.Function)
+		for _, arg := range node.Arguments {
+			visit(arg)
+		}
+
+	case *ast.ArrayLiteral:
+		for _, element := range node.Elements {
+			visit(element)
+		}
+	case *ast.InterpolatedString:
+		for _, part := range node.Parts {
+			visit(part)
+		}
+
+	case *ast.HashLiteral:
+		// Pairs is a map, walk it in source order ---
+		for _, key := range ast.SortedKeys(node) {
+			visit(key, node.Pairs[key])
+		}
+	}
+
+	return functions
+}
+
+// function opens the scope of a function body ---
+func (a *analysis) function(params []*ast.Identifier, patterns []ast.Expression, body *ast.BlockStatement, outer *scope) []documentSymbol {
+	if ast.IsNil(body) {
+		return nil
+	}
+
+	s := a.openScope(outer, sourcePos{body.Token.Line, body.Token.Column}, a.blockEnd(body))
+	functions := make([]documentSymbol, 0)
+	for i, param := range params {
+		// A pattern's parameter has no name of its own to show ---
+		if i < len(patterns) && patterns[i] != nil {
+			functions = append(functions, a.pattern(patterns[i], s, func(name *ast.Identifier) {
+				a.define(s, name, &symbol{Kind: parameterSymbol})
+			})...)
+			continue
+		}
+
+		a.define(s, param, &symbol{Kind: parameterSymbol})
+	}
+
+	return append(functions, a.walk(body, s)...)
+}
+
+// pattern walks the defaults of pattern and hands its names to bind, ---
+// in the order they're unpacked ---
+func (a *analysis) pattern(pattern ast.Expression, s *scope, bind func(*ast.Identifier)) []documentSymbol {
+	functions := make([]documentSymbol, 0)
+
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		bind(pattern)
+
+	case *ast.DefaultPattern:
+		functions = append(functions, a.walk(pattern.Default, s)...)
+		functions = append(functions, a.pattern(pattern.Target, s, bind)...)
+
+	case *ast.ArrayPattern:
+		for _, element := range pattern.Elements {
+			functions = append(functions, a.pattern(element, s, bind)...)
+		}
+		if pattern.Rest != nil {
+			bind(pattern.Rest)
+		}
+
+	case *ast.HashPattern:
+		for _, entry := range pattern.Entries {
+			functions = append(functions, a.pattern(entry.Value, s, bind)...)
+		}
+	}
+
+	return functions
+}
+
+func (a *analysis) valueSymbol(value ast.Expression, s *scope) *symbol {
+	if fn, ok := value.(*ast.FunctionLiteral); ok {
+		return &symbol{
+			Kind:        functionSymbol,
+			Type:        "FUNCTION",
+			Parameters:  fn.Parameters,
+			IsGenerator: fn.IsGenerator,
+		}
+	}
+
+	return &symbol{Kind: variableSymbol, Type: inferType(value, s)}
+}
+
+// inferType guesses the object type an expression evaluates to ---
+func inferType(node ast.Expression, s *scope) string {
+	if ast.IsNil(node) {
+		return ""
+	}
+
+	switch node := node.(type) {
+	case *ast.NumberLiteral, *ast.NaNLiteral, *ast.InfinityLiteral:
+		return "NUMBER"
+	case *ast.IntegerLiteral:
+		return "INTEGER"
+	case *ast.BigIntLiteral:
+		return "BIGINT"
+	case *ast.DecimalLiteral:
+		return "DECIMAL"
+	case *ast.AbsoluteExpression:
+		return numericType(inferType(node.Value, s), "INTEGER")
+	case *ast.StringLiteral, *ast.InterpolatedString:
+		return "STRING"
+	case *ast.BooleanExpression:
+		return "BOOLEAN"
+	case *ast.NilLiteral:
+		return "NIL"
+	case *ast.ArrayLiteral:
+		return "ARRAY"
+	case *ast.HashLiteral:
+		return "HASH"
+	case *ast.FunctionLiteral:
+		return "FUNCTION"
+	case *ast.MacroLiteral:
+		return "MACRO"
+	case *ast.SpawnExpression:
+		return "TASK"
+
+	case *ast.Identifier:
+		if sym, ok := s.resolve(node.Value); ok {
+			return sym.Type
+		}
+
+	case *ast.UnaryExpression:
+		switch node.Operator.Type {
+		case token.BANG, token.NOT:
+			return "BOOLEAN"
+		case token.MINUS:
+			return numericType(inferType(node.Right, s), "INTEGER")
+		case token.TILDE:
+			right := inferType(node.Right, s)
+			return bitwiseType(right, right)
+		}
+
+	case *ast.BinaryExpression:
+		switch node.Operator.Type {
+		case token.EQUAL, token.NOT_EQUAL, token.LESS, token.GREATER, token.LESS_EQUAL, token.GREATER_EQUAL:
+			return "BOOLEAN"
+		}
+
+		left, right := inferType(node.Left, s), inferType(node.Right, s)
+		if node.Operator.Type == token.PLUS && (left == "STRING" || right == "STRING") {
+			return "STRING"
+		}
+		switch node.Operator.Type {
+		case token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+			return bitwiseType(left, right)
+		}
+		if isNumeric(left) && isNumeric(right) {
+			switch node.Operator.Type {
+			case token.PLUS, token.MINUS, token.STAR, token.PERCENT, token.FLOOR_DIVIDE:
+				return numericType(left, right)
+			}
+			// Only a Decimal or a float keeps / and ^ from varying ---
+			for _, kind := range []string{"DECIMAL", "NUMBER"} {
+				if left == kind || right == kind {
+					return numericType(left, right)
+				}
+			}
+		}
+
+	case *ast.TernaryExpression:
+		consequence := inferType(node.Consequence, s)
+		if consequence == inferType(node.Alternative, s) {
+			return consequence
+		}
+
+	case *ast.CallExpression:
+		callee, ok := node.Function.(*ast.Identifier)
+		if !ok {
+			return ""
+		}
+
+		if sym, ok := s.resolve(callee.Value); ok {
+			if sym.IsGenerator {
+				return "GENERATOR"
+			}
+			return ""
+		}
+
+		return builtins[callee.Value].Returns
+	}
+
+	return ""
+}
+
+func isNumeric(kind string) bool {
+	return kind == "NUMBER" || exactRanks[kind] > 0
+}
+
+// exactRanks orders the exact numbers, arithmetic on two of them ---
+// gives the higher one ---
+var exactRanks = map[string]int{"INTEGER": 1, "BIGINT": 2, "DECIMAL": 3}
+
+// numericType is what arithmetic on left and right gives, integers ---
+// stay integers and BigInts or Decimals take over integers. A float ---
+// next to either doesn't work, that's "" ---
+func numericType(left, right string) string {
+	l, r := exactRanks[left], exactRanks[right]
+
+	switch {
+	case l > 0 && r > 0:
+		if l >= r {
+			return left
+		}
+		return right
+	case max(l, r) > 1:
+		return ""
+	}
+	return "NUMBER"
+}
+
+// bitwiseType is what & | xor << >> and ~ give ---
+func bitwiseType(left, right string) string {
+	switch {
+	case left == "BOOLEAN" && right == "BOOLEAN":
+		return "BOOLEAN"
+	case left == "BIGINT" || right == "BIGINT":
+		return "BIGINT"
+	case isNumeric(left) && isNumeric(right) && left != "DECIMAL" && right != "DECIMAL":
+		return "INTEGER"
+	}
+	return ""
+}
+
+func signature(name string, params []*ast.Identifier) string {
+	names := make([]string, len(params))
+	for i, param := range params {
+		names[i] = param.Value
+	}
+
+	return fmt.Sprintf("fn %s(%s)", name, strings.Join(names, ", "))
+}
+
+// occurrenceAt finds the identifier under pos ---
+func (a *analysis) occurrenceAt(pos sourcePos) (occurrence, bool) {
+	for _, occ := range a.occurrences {
+		start := identPos(occ.ident)
+		end := sourcePos{start.line, start.column + uint(len(occ.ident.Value))}
+
+		if !pos.before(start) && (pos.before(end) || pos == end) {
+			return occ, true
+		}
+	}
+
+	return occurrence{}, false
+}
+
+// hoverText describes what an occurrence refers to, in markdown ---
+func (occ occurrence) hoverText() string {
+	if occ.builtin != "" {
+		b := builtins[occ.builtin]
+		return fmt.Sprintf("```monkey\n%s\n```\n%s", b.Signature, b.Doc)
+	}
+
+	sym := occ.symbol
+	var detail string
+
+	switch sym.Kind {
+	case functionSymbol:
+		detail = signature(sym.Name, sym.Parameters)
+		if sym.IsGenerator {
+			detail += " (generator)"
+		}
+	case parameterSymbol:
+		detail = "(parameter) " + sym.Name
+	default:
+		detail = "(variable) " + sym.Name
+		if sym.Type != "" {
+			detail += ": " + sym.Type
+		}
+	}
+
+	return fmt.Sprintf("```monkey\n%s\n```", detail)
+}
+
+// visibleSymbols are the variables in scope at pos, innermost first ---
+func (a *analysis) visibleSymbols(pos sourcePos) []*symbol {
+	var innermost *scope
+	for _, s := range a.scopes {
+		if pos.before(s.start) || !pos.before(s.end) {
+			continue
+		}
+		if innermost == nil || innermost.start.before(s.start) {
+			innermost = s
+		}
+	}
+
+	visible := make([]*symbol, 0)
+	seen := make(map[string]bool)
+
+	for s := innermost; s != nil; s = s.outer {
+		names := make([]string, 0, len(s.symbols))
+		for name := range s.symbols {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			sym := s.symbols[name]
+			if seen[name] || !identPos(sym.Definition).before(pos) {
+				continue
+			}
+
+			seen[name] = true
+			visible = append(visible, sym)
+		}
+	}
+
+	return visible
+}
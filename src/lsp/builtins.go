@@ -0,0 +1,38 @@
+package lsp
+
+type builtin struct {
+	Signature string
+	Doc       string
+	Returns   string // Object type of the result, "" when it varies ---
+}
+
+// Keep in step with object.BuiltinNames ---
+var builtins = map[string]builtin{
+	"len":        {"len(value)", "Length of a string, array or hash", "INTEGER"},
+	"print":      {"print(...values)", "Writes the values to stdout separated by \", \" and ends the line", "NIL"},
+	"prompt":     {"prompt(message)", "Writes message and reads one line from stdin", "STRING"},
+	"time":       {"time()", "Milliseconds since the Unix epoch", "NUMBER"},
+	"to_string":  {"to_string(value)", "The value as a string", "STRING"},
+	"to_number":  {"to_number(value)", "The value as a number", "NUMBER"},
+	"to_integer": {"to_integer(value)", "The value as an integer, numbers are truncated toward zero", "INTEGER"},
+	"bigint":     {"bigint(value)", "The value as an exact BigInt, numbers are truncated toward zero", "BIGINT"},
+	"decimal":    {"decimal(value)", "The value as an exact Decimal, a float becomes its shortest decimal form", "DECIMAL"},
+	"type":       {"type(value)", "Name of the value's type, like \"NUMBER\"", "STRING"},
+	"is_NaN":     {"is_NaN(value)", "Whether value is NaN", "BOOLEAN"},
+	"is_Inf":     {"is_Inf(value)", "Whether value is Inf or -Inf", "BOOLEAN"},
+	"is_nil":     {"is_nil(value)", "Whether value is nil", "BOOLEAN"},
+	"random":     {"random()", "A random number in [0, 1)", "NUMBER"},
+	"next":       {"next(generator, value?)", "Resumes the generator, value is what the paused yield evaluates to", ""},
+	"is_done":    {"is_done(generator)", "Whether the generator has returned", "BOOLEAN"},
+	"div":        {"div(a, b)", "a divided by b, rounded down", ""},
+	"mod":        {"mod(a, b)", "The remainder of div(a, b), it has the sign of b", ""},
+
+	"channel": {"channel(capacity?)", "A channel buffering up to capacity values, unbuffered by default", "CHANNEL"},
+	"send":    {"send(channel, value)", "Sends value, blocking until there's room", "NIL"},
+	"recv":    {"recv(channel)", "Receives a value, blocking until one is sent", ""},
+	"close":   {"close(channel)", "Closes the channel, receivers get nil once it's drained", "NIL"},
+	"select":  {"select(...channels)", "Waits on the channels, returns [index, value, ok] for the first ready one", "ARRAY"},
+
+	"quote":   {"quote(expression)", "The expression as code instead of its value", "QUOTE"},
+	"unquote": {"unquote(value)", "Inside quote(...), the code for value", ""},
+}
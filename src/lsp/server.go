@@ -0,0 +1,250 @@
+// Package lsp is the Language Server Protocol server behind `monkey lsp`,
+// it gives editors diagnostics, hover, navigation, completion and an outline for .mn files.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// Open documents by URI, reanalyzed on every change ---
+	documents map[string]*analysis
+	shutdown  bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:        bufio.NewReader(in),
+		out:       out,
+		documents: make(map[string]*analysis),
+	}
+}
+
+// Serve handles messages until the client sends exit or closes the stream ---
+func (s *Server) Serve() error {
+	for {
+		msg, err := readMessage(s.in)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("Exit requested before shutdown")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.handle(msg)
+
+		// Notifications get no response ---
+		if msg.ID == nil {
+			continue
+		}
+
+		err = writeMessage(s.out, response{JSONRPC: "2.0", ID: msg.ID, Result: result, Error: rpcErr})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *request) (any, *responseError) {
+	switch msg.Method {
+	case "initialize":
+		return s.initialize(), nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		params := didOpenParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return nil, internalError(s.update(params.TextDocument.URI, params.TextDocument.Text))
+
+	case "textDocument/didChange":
+		params := didChangeParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		// Full sync, the last change is the whole document ---
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		return nil, internalError(s.update(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text))
+
+	case "textDocument/didClose":
+		params := didCloseParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return nil, internalError(s.publishDiagnostics(params.TextDocument.URI, make([]diagnostic, 0)))
+
+	case "textDocument/hover":
+		return withPosition(s, msg, s.hover)
+	case "textDocument/definition":
+		return withPosition(s, msg, s.definition)
+	case "textDocument/completion":
+		return withPosition(s, msg, s.completion)
+
+	case "textDocument/references":
+		params := referenceParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.references(params), nil
+
+	case "textDocument/documentSymbol":
+		params := documentSymbolParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok {
+			return make([]documentSymbol, 0), nil
+		}
+		return doc.functions, nil
+
+	case "initialized", "$/cancelRequest", "$/setTrace":
+		return nil, nil
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: "Unsupported method '" + msg.Method + "'"}
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
+
+func internalError(err error) *responseError {
+	if err == nil {
+		return nil
+	}
+
+	return &responseError{Code: codeInternalError, Message: err.Error()}
+}
+
+func withPosition(s *Server, msg *request, handler func(doc *analysis, params positionParams) any) (any, *responseError) {
+	params := positionParams{}
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+
+	return handler(doc, params), nil
+}
+
+func (s *Server) initialize() any {
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync":       1, // Full ---
+			"hoverProvider":          true,
+			"definitionProvider":     true,
+			"referencesProvider":     true,
+			"documentSymbolProvider": true,
+			"completionProvider":     map[string]any{},
+		},
+		"serverInfo": map[string]any{"name": "monkey"},
+	}
+}
+
+func (s *Server) update(uri, text string) error {
+	doc := analyze(text)
+	s.documents[uri] = doc
+
+	return s.publishDiagnostics(uri, doc.diagnostics)
+}
+
+func (s *Server) publishDiagnostics(uri string, diagnostics []diagnostic) error {
+	return writeMessage(s.out, notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+}
+
+func (s *Server) hover(doc *analysis, params positionParams) any {
+	occ, ok := doc.occurrenceAt(doc.fromPosition(params.Position))
+	if !ok {
+		return nil
+	}
+
+	return hover{
+		Contents: markupContent{Kind: "markdown", Value: occ.hoverText()},
+		Range:    doc.identRange(occ.ident),
+	}
+}
+
+func (s *Server) definition(doc *analysis, params positionParams) any {
+	occ, ok := doc.occurrenceAt(doc.fromPosition(params.Position))
+	if !ok || occ.symbol == nil {
+		return nil
+	}
+
+	return location{URI: params.TextDocument.URI, Range: doc.identRange(occ.symbol.Definition)}
+}
+
+func (s *Server) references(params referenceParams) []location {
+	locations := make([]location, 0)
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return locations
+	}
+
+	occ, ok := doc.occurrenceAt(doc.fromPosition(params.Position))
+	if !ok || occ.symbol == nil {
+		return locations
+	}
+
+	for _, ident := range occ.symbol.References {
+		if ident == occ.symbol.Definition && !params.Context.IncludeDeclaration {
+			continue
+		}
+		locations = append(locations, location{URI: params.TextDocument.URI, Range: doc.identRange(ident)})
+	}
+
+	return locations
+}
+
+func (s *Server) completion(doc *analysis, params positionParams) any {
+	items := make([]completionItem, 0)
+
+	for _, sym := range doc.visibleSymbols(doc.fromPosition(params.Position)) {
+		item := completionItem{Label: sym.Name, Kind: completionVariable, Detail: sym.Type}
+		if sym.Kind == functionSymbol {
+			item.Kind = completionFunction
+			item.Detail = signature(sym.Name, sym.Parameters)
+		}
+		items = append(items, item)
+	}
+
+	for _, name := range object.BuiltinNames {
+		items = append(items, completionItem{Label: name, Kind: completionFunction, Detail: builtins[name].Signature})
+	}
+
+	for _, keyword := range token.Keywords() {
+		items = append(items, completionItem{Label: keyword, Kind: completionKeyword})
+	}
+
+	return items
+}
@@ -0,0 +1,188 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// message is any JSON-RPC message the server sends, decoded loosely ---
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Params json.RawMessage `json:"params"`
+}
+
+// replay runs the server over requests and returns what it sent back ---
+func replay(t *testing.T, requests ...string) []message {
+	t.Helper()
+
+	var in bytes.Buffer
+	for _, raw := range requests {
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(raw), raw)
+	}
+
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- NewServer(&in, &out).Serve() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Serve returned %s", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the server hung")
+	}
+
+	messages := make([]message, 0)
+	reader := bufio.NewReader(&out)
+	for {
+		headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+		if err == io.EOF {
+			return messages
+		}
+		if err != nil {
+			t.Fatalf("reading headers: %s", err)
+		}
+
+		length, _ := strconv.Atoi(headers.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			t.Fatalf("reading body: %s", err)
+		}
+
+		msg := message{}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("invalid message %s: %s", body, err)
+		}
+		messages = append(messages, msg)
+	}
+}
+
+func didOpen(text string) string {
+	quoted, _ := json.Marshal(text)
+	return fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///test.mn","text":%s}}}`, quoted)
+}
+
+func positionRequest(id int, method string, line, character int) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"%s","params":{"textDocument":{"uri":"file:///test.mn"},"position":{"line":%d,"character":%d}}}`, id, method, line, character)
+}
+
+func result(t *testing.T, messages []message, id int, v any) {
+	t.Helper()
+
+	for _, msg := range messages {
+		if msg.ID != nil && *msg.ID == id {
+			if err := json.Unmarshal(msg.Result, v); err != nil {
+				t.Fatalf("invalid result %s: %s", msg.Result, err)
+			}
+			return
+		}
+	}
+
+	t.Fatalf("no response to request %d", id)
+}
+
+func diagnostics(t *testing.T, messages []message) []diagnostic {
+	t.Helper()
+
+	for _, msg := range messages {
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var params publishDiagnosticsParams
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				t.Fatalf("invalid diagnostics %s: %s", msg.Params, err)
+			}
+			return params.Diagnostics
+		}
+	}
+
+	t.Fatalf("no diagnostics were published")
+	return nil
+}
+
+func TestNonASCIIPositions(t *testing.T) {
+	// 😀 is 4 bytes and 2 UTF-16 code units, é is 2 bytes and 1 unit ---
+	source := "var s = \"😀é\"; var total = 1;\nprint(\"😀\", total);\n"
+
+	messages := replay(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		didOpen(source),
+		positionRequest(2, "textDocument/definition", 1, 13), // total, after 😀 ---
+		positionRequest(3, "textDocument/hover", 1, 13),
+		`{"jsonrpc":"2.0","id":4,"method":"textDocument/references","params":{"textDocument":{"uri":"file:///test.mn"},"position":{"line":0,"character":21},"context":{"includeDeclaration":true}}}`, // total, after 😀é ---
+		`{"jsonrpc":"2.0","id":5,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+
+	definitionRange := textRange{Start: position{Line: 0, Character: 19}, End: position{Line: 0, Character: 24}}
+
+	var definition *location
+	result(t, messages, 2, &definition)
+	if definition == nil || definition.Range != definitionRange {
+		t.Errorf("wrong definition: expected %+v, got %+v", definitionRange, definition)
+	}
+
+	var hovered *hover
+	result(t, messages, 3, &hovered)
+	useRange := textRange{Start: position{Line: 1, Character: 12}, End: position{Line: 1, Character: 17}}
+	if hovered == nil || hovered.Range != useRange {
+		t.Errorf("wrong hover: expected the range %+v, got %+v", useRange, hovered)
+	}
+
+	var references []location
+	result(t, messages, 4, &references)
+	if len(references) != 2 || references[0].Range != definitionRange || references[1].Range != useRange {
+		t.Errorf("wrong references: %+v", references)
+	}
+}
+
+func TestMacroExpansionIsSandboxed(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		message string
+	}{
+		{
+			name:    "prompt gets no input instead of protocol bytes",
+			source:  "var m = macro() { prompt(\"> \"); quote(1); };\nm();\n",
+			message: "I/O error",
+		},
+		{
+			name: "a macro that runs too long is stopped",
+			source: `var slow = macro() {
+    fn spin(n) { if (n == 0) { return 0; } spin(n - 1); spin(n - 1); }
+    spin(40);
+    quote(1);
+};
+slow();
+`,
+			message: "Step limit exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The exit is still read as a message after the macro ran ---
+			messages := replay(t,
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+				didOpen(tt.source),
+				`{"jsonrpc":"2.0","id":2,"method":"shutdown"}`,
+				`{"jsonrpc":"2.0","method":"exit"}`,
+			)
+
+			got := diagnostics(t, messages)
+			if len(got) != 1 || !strings.Contains(got[0].Message, tt.message) {
+				t.Errorf("expected a %q diagnostic, got %+v", tt.message, got)
+			}
+		})
+	}
+}
@@ -6,6 +6,11 @@ import (
 	"os"
 
 	"github.com/caelondev/monkey-compiler-go/src/build"
+	"github.com/caelondev/monkey-compiler-go/src/check"
+	"github.com/caelondev/monkey-compiler-go/src/dap"
+	"github.com/caelondev/monkey-compiler-go/src/debug"
+	"github.com/caelondev/monkey-compiler-go/src/format"
+	"github.com/caelondev/monkey-compiler-go/src/lsp"
 	"github.com/caelondev/monkey-compiler-go/src/repl"
 	"github.com/caelondev/monkey-compiler-go/src/run"
 )
@@ -38,6 +43,51 @@ func Main() {
 		return
 	}
 
+	if len(args) == 1 && args[0] == "dap" {
+		err := dap.NewServer(os.Stdin, os.Stdout).Serve()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) >= 1 && args[0] == "fmt" {
+		err := format.Command(args[1:], os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) >= 1 && args[0] == "check" {
+		err := check.Command(args[1:], os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) == 1 && args[0] == "lsp" {
+		err := lsp.NewServer(os.Stdin, os.Stdout).Serve()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) == 2 && args[0] == "debug" {
+		err := debug.Start(args[1], os.Stdin, os.Stdout)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if len(args) == 1 {
 		// TODO: File runner still uses the old ---
 		// Evaluator and not the VM
@@ -45,6 +95,6 @@ func Main() {
 		return
 	}
 
-	fmt.Println("Usage: monkey [filepath]")
+	fmt.Println("Usage: monkey [filepath] | monkey debug <filepath> | monkey fmt [-w] [--check] <path> | monkey check [--types] <path> | monkey dap | monkey lsp")
 	os.Exit(1)
 }
@@ -0,0 +1,488 @@
+package object
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// NOTE: BigInt and Decimal are exact, they never round like floats. ---
+// An Integer next to either is promoted to it, a BigInt next to a ---
+// Decimal becomes one. A float is never promoted into them or the ---
+// other way round, scripts convert with bigint(), decimal() or ---
+// to_number() so it's clear where exactness is given up ---
+
+// DecimalPrecision is how many digits after the point a division ---
+// that doesn't end is rounded to ---
+const DecimalPrecision = 32
+
+// BigInt is an integer of any size, written 123n ---
+type BigInt struct {
+	Value *big.Int
+}
+
+func (o *BigInt) Type() ObjectType {
+	return BIGINT_OBJECT
+}
+
+func (o *BigInt) Inspect() string {
+	return o.Value.String() + lexer.BigIntSuffix
+}
+
+func (o *BigInt) HashKey() HashKey {
+	return exactHashKey(new(big.Rat).SetInt(o.Value))
+}
+
+// Decimal is an exact base 10 number worth Unscaled / 10^Scale, ---
+// written 1.10m. It keeps its scale, 1.10m + 1m is 2.10m ---
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
+func (o *Decimal) Type() ObjectType {
+	return DECIMAL_OBJECT
+}
+
+func (o *Decimal) Inspect() string {
+	return o.String() + lexer.DecimalSuffix
+}
+
+// String is the decimal without its suffix, what print shows ---
+func (o *Decimal) String() string {
+	digits := new(big.Int).Abs(o.Unscaled).String()
+
+	sign := ""
+	if o.Unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if o.Scale == 0 {
+		return sign + digits
+	}
+
+	if pad := int(o.Scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	point := len(digits) - int(o.Scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+func (o *Decimal) HashKey() HashKey {
+	return exactHashKey(o.Rat())
+}
+
+// Rat is the decimal as a fraction ---
+func (o *Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(o.Unscaled, pow10(o.Scale))
+}
+
+// exactHashKey keys a value the way the Integer or Number it equals ---
+// is keyed, so 2n, 2.0m and 2 find the same entry ---
+func exactHashKey(value *big.Rat) HashKey {
+	if value.IsInt() && value.Num().IsInt64() {
+		return (&Integer{Value: value.Num().Int64()}).HashKey()
+	}
+	if float, exact := value.Float64(); exact {
+		return (&Number{Value: float}).HashKey()
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(value.RatString()))
+
+	// BigInts and Decimals share their keys too ---
+	return HashKey{Type: DECIMAL_OBJECT, Value: hash.Sum64()}
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// IsBig reports whether obj is a BigInt or a Decimal ---
+func IsBig(obj Object) bool {
+	switch obj.(type) {
+	case *BigInt, *Decimal:
+		return true
+	}
+
+	return false
+}
+
+// IsNumber reports whether obj is an Integer, a Number, a BigInt ---
+// or a Decimal ---
+func IsNumber(obj Object) bool {
+	switch obj.(type) {
+	case *Integer, *Number, *BigInt, *Decimal:
+		return true
+	}
+
+	return false
+}
+
+func bigInt(obj Object) (*big.Int, bool) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return big.NewInt(obj.Value), true
+	case *BigInt:
+		return obj.Value, true
+	}
+
+	return nil, false
+}
+
+func decimal(obj Object) (*Decimal, bool) {
+	if obj, ok := obj.(*Decimal); ok {
+		return obj, true
+	}
+	if value, ok := bigInt(obj); ok {
+		return &Decimal{Unscaled: value, Scale: 0}, true
+	}
+
+	return nil, false
+}
+
+// rescale is d's unscaled value at a scale at least as large as its own ---
+func (o *Decimal) rescale(scale int32) *big.Int {
+	return new(big.Int).Mul(o.Unscaled, pow10(scale-o.Scale))
+}
+
+// BigArithmetic works out `left operator right` when either side is ---
+// a BigInt or a Decimal, following the promotions in the NOTE above ---
+func BigArithmetic(operator token.TokenType, left, right Object) (Object, error) {
+	if _, ok := left.(*Decimal); !ok {
+		if _, ok := right.(*Decimal); !ok {
+			l, lok := bigInt(left)
+			r, rok := bigInt(right)
+			if lok && rok {
+				return bigIntArithmetic(operator, l, r)
+			}
+		}
+	}
+
+	l, lok := decimal(left)
+	r, rok := decimal(right)
+	if !lok || !rok {
+		return nil, mixedError(operator, left, right)
+	}
+
+	return decimalArithmetic(operator, l, r)
+}
+
+func mixedError(operator token.TokenType, left, right Object) error {
+	return fmt.Errorf(
+		"Cannot perform `%s %s %s`, convert one side with bigint(), decimal() or to_number() first",
+		left.Type(), operator, right.Type(),
+	)
+}
+
+func bigIntArithmetic(operator token.TokenType, l, r *big.Int) (Object, error) {
+	result := new(big.Int)
+
+	switch operator {
+	case token.PLUS:
+		result.Add(l, r)
+	case token.MINUS:
+		result.Sub(l, r)
+	case token.STAR:
+		result.Mul(l, r)
+
+	case token.SLASH:
+		if r.Sign() == 0 {
+			return nil, fmt.Errorf("Division by zero")
+		}
+
+		// Like 7 / 2 for integers, a quotient that isn't whole ---
+		// goes up a type, here to a Decimal ---
+		remainder := new(big.Int)
+		if result.QuoRem(l, r, remainder); remainder.Sign() != 0 {
+			return divideDecimals(&Decimal{Unscaled: l}, &Decimal{Unscaled: r}), nil
+		}
+
+	case token.CARET:
+		if !r.IsInt64() {
+			return nil, fmt.Errorf("Exponent %s is too large", r)
+		}
+		if r.Sign() < 0 {
+			return decimalArithmetic(operator, &Decimal{Unscaled: l}, &Decimal{Unscaled: r})
+		}
+		result.Exp(l, r, nil)
+
+	default:
+		return nil, fmt.Errorf("Invalid operator '%s' for BIGINT", operator)
+	}
+
+	return &BigInt{Value: result}, nil
+}
+
+func decimalArithmetic(operator token.TokenType, l, r *Decimal) (Object, error) {
+	switch operator {
+	case token.PLUS, token.MINUS:
+		scale := max(l.Scale, r.Scale)
+		result := &Decimal{Unscaled: new(big.Int), Scale: scale}
+
+		if operator == token.PLUS {
+			result.Unscaled.Add(l.rescale(scale), r.rescale(scale))
+		} else {
+			result.Unscaled.Sub(l.rescale(scale), r.rescale(scale))
+		}
+		return result, nil
+
+	case token.STAR:
+		return &Decimal{Unscaled: new(big.Int).Mul(l.Unscaled, r.Unscaled), Scale: l.Scale + r.Scale}, nil
+
+	case token.SLASH:
+		if r.Unscaled.Sign() == 0 {
+			return nil, fmt.Errorf("Division by zero")
+		}
+		return divideDecimals(l, r), nil
+
+	case token.CARET:
+		exponent := r.Rat()
+		if !exponent.IsInt() || !exponent.Num().IsInt64() {
+			return nil, fmt.Errorf("Decimal exponents must be whole numbers, got %s", r.String())
+		}
+
+		n := exponent.Num().Int64()
+		if abs := max(n, -n); abs > math.MaxInt32/int64(max(l.Scale, 1)) {
+			return nil, fmt.Errorf("Exponent %d is too large", n)
+		}
+
+		result := &Decimal{
+			Unscaled: new(big.Int).Exp(l.Unscaled, big.NewInt(max(n, -n)), nil),
+			Scale:    l.Scale * int32(max(n, -n)),
+		}
+		if n >= 0 {
+			return result, nil
+		}
+		if result.Unscaled.Sign() == 0 {
+			return nil, fmt.Errorf("Division by zero")
+		}
+		return divideDecimals(&Decimal{Unscaled: big.NewInt(1)}, result), nil
+	}
+
+	return nil, fmt.Errorf("Invalid operator '%s' for DECIMAL", operator)
+}
+
+// divideDecimals rounds l / r half to even at DecimalPrecision digits ---
+// after the point, then drops the zeros it didn't need, down to the ---
+// larger scale of the two ---
+func divideDecimals(l, r *Decimal) *Decimal {
+	scale := max(l.Scale, r.Scale)
+	target := scale + DecimalPrecision
+
+	// l / r = (lu / 10^ls) / (ru / 10^rs), times 10^target ---
+	numerator := new(big.Int).Mul(l.Unscaled, pow10(target+r.Scale-l.Scale))
+	quotient, remainder := new(big.Int).QuoRem(numerator, r.Unscaled, new(big.Int))
+
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	if order := twice.Cmp(new(big.Int).Abs(r.Unscaled)); order > 0 || (order == 0 && quotient.Bit(0) == 1) {
+		if numerator.Sign()*r.Unscaled.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return trimDecimal(&Decimal{Unscaled: quotient, Scale: target}, scale)
+}
+
+// trimDecimal drops trailing zeros from d without going below scale ---
+func trimDecimal(d *Decimal, scale int32) *Decimal {
+	ten := big.NewInt(10)
+	quotient, remainder := new(big.Int), new(big.Int)
+
+	for d.Scale > scale {
+		if quotient.QuoRem(d.Unscaled, ten, remainder); remainder.Sign() != 0 {
+			break
+		}
+		d = &Decimal{Unscaled: new(big.Int).Set(quotient), Scale: d.Scale - 1}
+	}
+
+	return d
+}
+
+// NegateBig is -obj for a BigInt or a Decimal ---
+func NegateBig(obj Object) Object {
+	switch obj := obj.(type) {
+	case *BigInt:
+		return &BigInt{Value: new(big.Int).Neg(obj.Value)}
+	case *Decimal:
+		return &Decimal{Unscaled: new(big.Int).Neg(obj.Unscaled), Scale: obj.Scale}
+	}
+
+	return obj
+}
+
+// SignOf is -1, 0 or 1 for a BigInt or a Decimal ---
+func SignOf(obj Object) int {
+	switch obj := obj.(type) {
+	case *BigInt:
+		return obj.Value.Sign()
+	case *Decimal:
+		return obj.Unscaled.Sign()
+	}
+
+	return 0
+}
+
+// exactValue is obj as a fraction, false for NaN and the infinities ---
+func exactValue(obj Object) (*big.Rat, bool) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return new(big.Rat).SetInt64(obj.Value), true
+	case *BigInt:
+		return new(big.Rat).SetInt(obj.Value), true
+	case *Decimal:
+		return obj.Rat(), true
+	case *Number:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return nil, false
+		}
+		return new(big.Rat).SetFloat64(obj.Value), true
+	}
+
+	return nil, false
+}
+
+// floorBig is FloorDivide and Modulo for BigInts and Decimals ---
+func floorBig(left, right Object, modulo bool) (Object, error) {
+	if l, lok := bigInt(left); lok {
+		if r, rok := bigInt(right); rok {
+			quotient, remainder, err := floorDivide(l, r)
+			if err != nil {
+				return nil, err
+			}
+			if modulo {
+				return &BigInt{Value: remainder}, nil
+			}
+			return &BigInt{Value: quotient}, nil
+		}
+	}
+
+	l, lok := decimal(left)
+	r, rok := decimal(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("Cannot use %s and %s together, convert one with bigint(), decimal() or to_number() first", left.Type(), right.Type())
+	}
+
+	scale := max(l.Scale, r.Scale)
+	quotient, remainder, err := floorDivide(l.rescale(scale), r.rescale(scale))
+	if err != nil {
+		return nil, err
+	}
+	if modulo {
+		return &Decimal{Unscaled: remainder, Scale: scale}, nil
+	}
+	return &Decimal{Unscaled: quotient, Scale: 0}, nil
+}
+
+// floorDivide rounds the quotient down, the remainder takes r's sign ---
+func floorDivide(l, r *big.Int) (*big.Int, *big.Int, error) {
+	if r.Sign() == 0 {
+		return nil, nil, fmt.Errorf("Division by zero")
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(l, r, new(big.Int))
+	if remainder.Sign() != 0 && remainder.Sign() != r.Sign() {
+		quotient.Sub(quotient, big.NewInt(1))
+		remainder.Add(remainder, r)
+	}
+
+	return quotient, remainder, nil
+}
+
+// BigToFloat is the float nearest to a BigInt or a Decimal ---
+func BigToFloat(obj Object) (float64, bool) {
+	value, ok := exactValue(obj)
+	if !ok || !IsBig(obj) {
+		return 0, false
+	}
+
+	float, _ := value.Float64()
+	return float, true
+}
+
+// ToBigInt converts obj for bigint(): Numbers and Decimals are ---
+// truncated toward zero and strings are read as integer literals ---
+func ToBigInt(obj Object) (Object, error) {
+	switch obj := obj.(type) {
+	case *BigInt:
+		return obj, nil
+	case *Integer:
+		return &BigInt{Value: big.NewInt(obj.Value)}, nil
+	case *Decimal:
+		return &BigInt{Value: new(big.Int).Quo(obj.Unscaled, pow10(obj.Scale))}, nil
+
+	case *Number:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return nil, fmt.Errorf("Cannot convert %s to a BigInt", obj.Inspect())
+		}
+		value, _ := big.NewFloat(math.Trunc(obj.Value)).Int(nil)
+		return &BigInt{Value: value}, nil
+
+	case *String:
+		negative, literal := splitSign(obj.Value)
+		value, err := lexer.BigIntValue(strings.TrimSuffix(literal, lexer.BigIntSuffix) + lexer.BigIntSuffix)
+		if literal == "" || err != nil {
+			return nil, fmt.Errorf("Cannot convert %s to a BigInt", obj.Inspect())
+		}
+		if negative {
+			value.Neg(value)
+		}
+		return &BigInt{Value: value}, nil
+	}
+
+	return nil, fmt.Errorf("Cannot convert type '%s' to a BigInt", obj.Type())
+}
+
+// ToDecimal converts obj for decimal(). A Number becomes the shortest ---
+// decimal that reads back as it, so decimal(0.1) is 0.1m ---
+func ToDecimal(obj Object) (Object, error) {
+	switch obj := obj.(type) {
+	case *Decimal:
+		return obj, nil
+	case *Integer, *BigInt:
+		value, _ := decimal(obj)
+		return value, nil
+
+	case *Number:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return nil, fmt.Errorf("Cannot convert %s to a Decimal", obj.Inspect())
+		}
+		return ToDecimal(&String{Value: strconv.FormatFloat(obj.Value, 'g', -1, 64)})
+
+	case *String:
+		negative, literal := splitSign(obj.Value)
+		unscaled, scale, err := lexer.DecimalValue(strings.TrimSuffix(literal, lexer.DecimalSuffix) + lexer.DecimalSuffix)
+		if literal == "" || err != nil {
+			return nil, fmt.Errorf("Cannot convert %s to a Decimal", obj.Inspect())
+		}
+		if negative {
+			unscaled.Neg(unscaled)
+		}
+		return &Decimal{Unscaled: unscaled, Scale: scale}, nil
+	}
+
+	return nil, fmt.Errorf("Cannot convert type '%s' to a Decimal", obj.Type())
+}
+
+// splitSign takes a leading + or - off a number read from a string ---
+func splitSign(text string) (bool, string) {
+	text = strings.TrimSpace(text)
+
+	switch {
+	case strings.HasPrefix(text, "-"):
+		return true, text[1:]
+	case strings.HasPrefix(text, "+"):
+		return false, text[1:]
+	}
+
+	return false, text
+}
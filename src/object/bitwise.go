@@ -0,0 +1,183 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// NOTE: The bitwise operators work on Integers and BigInts as if they ---
+// were two's complement of any width, so ~x is -x - 1 and >> rounds ---
+// down. A float that's a whole number counts as an Integer, any ---
+// other float, NaN, Inf or Decimal is an error. Booleans take & | ---
+// and xor too, without short circuiting ---
+
+// MaxShift caps how far << moves a BigInt, past it the result would ---
+// take more memory than a script should ask for by accident ---
+const MaxShift = 1 << 24
+
+// IsIntegerOperator reports whether operator is % ~/ & | xor << or >>, ---
+// which IntegerOperation works out for any operands ---
+func IsIntegerOperator(operator token.TokenType) bool {
+	switch operator {
+	case token.PERCENT, token.FLOOR_DIVIDE,
+		token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		return true
+	}
+
+	return false
+}
+
+// IntegerOperation works out `left operator right` for the operators ---
+// IsIntegerOperator accepts. % and ~/ are mod() and div() ---
+func IntegerOperation(operator token.TokenType, left, right Object) (Object, error) {
+	switch operator {
+	case token.PERCENT:
+		return Modulo(left, right)
+	case token.FLOOR_DIVIDE:
+		return FloorDivide(left, right)
+	}
+
+	if l, ok := left.(*Integer); ok {
+		if r, ok := right.(*Integer); ok {
+			switch operator {
+			case token.AMPERSAND:
+				return &Integer{Value: l.Value & r.Value}, nil
+			case token.PIPE:
+				return &Integer{Value: l.Value | r.Value}, nil
+			case token.XOR:
+				return &Integer{Value: l.Value ^ r.Value}, nil
+			}
+		}
+	}
+
+	if l, ok := left.(*Boolean); ok {
+		if r, ok := right.(*Boolean); ok && operator != token.SHIFT_LEFT && operator != token.SHIFT_RIGHT {
+			return booleanBitwise(operator, l.Value, r.Value), nil
+		}
+	}
+
+	l, lbig, err := bitwiseOperand(operator, left)
+	if err != nil {
+		return nil, err
+	}
+	r, rbig, err := bitwiseOperand(operator, right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		return shift(operator, l, r, lbig || rbig)
+	}
+
+	result := new(big.Int)
+	switch operator {
+	case token.AMPERSAND:
+		result.And(l, r)
+	case token.PIPE:
+		result.Or(l, r)
+	default:
+		result.Xor(l, r)
+	}
+
+	if !lbig && !rbig {
+		return &Integer{Value: result.Int64()}, nil // Whole floats ---
+	}
+	return &BigInt{Value: result}, nil
+}
+
+func booleanBitwise(operator token.TokenType, l, r bool) *Boolean {
+	var result bool
+
+	switch operator {
+	case token.AMPERSAND:
+		result = l && r
+	case token.PIPE:
+		result = l || r
+	default:
+		result = l != r
+	}
+
+	if result {
+		return TRUE
+	}
+	return FALSE
+}
+
+// shift moves l's bits by r, a BigInt on either side keeps the ---
+// result one. An Integer shifted out of 64 bits is an overflow ---
+func shift(operator token.TokenType, l, r *big.Int, isBig bool) (Object, error) {
+	if r.Sign() < 0 {
+		return nil, fmt.Errorf("Cannot shift by a negative count, got %s", r)
+	}
+
+	result := new(big.Int)
+	if operator == token.SHIFT_RIGHT {
+		if r.IsUint64() {
+			result.Rsh(l, uint(min(r.Uint64(), math.MaxInt32)))
+		} else if l.Sign() < 0 {
+			result.SetInt64(-1) // Everything shifted out but the sign ---
+		}
+	} else {
+		switch {
+		case l.Sign() == 0:
+		case !r.IsInt64() || r.Int64() > MaxShift:
+			return nil, fmt.Errorf("Shift count %s is too large, the limit is %d", r, MaxShift)
+		default:
+			result.Lsh(l, uint(r.Int64()))
+		}
+	}
+
+	if isBig {
+		return &BigInt{Value: result}, nil
+	}
+	if !result.IsInt64() {
+		return nil, fmt.Errorf("Integer overflow in `%s %s %s`, the result doesn't fit in 64 bits", l, symbol(operator), r)
+	}
+
+	return &Integer{Value: result.Int64()}, nil
+}
+
+// bitwiseOperand reads obj as an integer, isBig when it's a BigInt ---
+func bitwiseOperand(operator token.TokenType, obj Object) (value *big.Int, isBig bool, err error) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return big.NewInt(obj.Value), false, nil
+	case *BigInt:
+		return obj.Value, true, nil
+	case *Number:
+		if value, ok := exactInteger(obj.Value); ok {
+			return big.NewInt(value), false, nil
+		}
+		return nil, false, fmt.Errorf("'%s' needs integers, %s isn't one", symbol(operator), obj.Inspect())
+	case *Decimal, *NaN, *Infinity:
+		return nil, false, fmt.Errorf("'%s' needs integers, %s isn't one", symbol(operator), obj.Inspect())
+	}
+
+	return nil, false, fmt.Errorf("'%s' needs integers, got %s", symbol(operator), obj.Type())
+}
+
+// BitwiseNot is ~obj, -obj - 1 ---
+func BitwiseNot(obj Object) (Object, error) {
+	value, isBig, err := bitwiseOperand(token.TILDE, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBig {
+		return &BigInt{Value: new(big.Int).Not(value)}, nil
+	}
+	return &Integer{Value: ^value.Int64()}, nil
+}
+
+// symbol is operator as it's written ---
+func symbol(operator token.TokenType) string {
+	if operator == token.XOR {
+		return "xor"
+	}
+
+	return string(operator)
+}
@@ -0,0 +1,31 @@
+package object
+
+// BuiltinNames fixes the index of every builtin for the compiler's ---
+// OpGetBuiltin operand, the VM resolves them in the same order ---
+var BuiltinNames = []string{
+	"len",
+	"print",
+	"prompt",
+	"time",
+	"to_string",
+	"to_number",
+	"to_integer",
+	"bigint",
+	"decimal",
+	"type",
+	"is_NaN",
+	"is_Inf",
+	"is_nil",
+	"random",
+	"next",
+	"is_done",
+	"div",
+	"mod",
+
+	// Task/channel builtins, implemented by the scheduler ---
+	"channel",
+	"send",
+	"recv",
+	"close",
+	"select",
+}
@@ -0,0 +1,590 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// NOTE: Struct fields can be renamed with a `monkey:"name"` tag ---
+// and skipped with `monkey:"-"`, same rules as encoding/json ---
+const structTag = "monkey"
+
+var (
+	objectType = reflect.TypeOf((*Object)(nil)).Elem()
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Invoker calls a script function from Go, every engine supplies its own ---
+type Invoker func(fn Object, args ...Object) (Object, error)
+
+// converter carries the invoker used to turn script functions ---
+// into Go funcs, without one those conversions fail ---
+type converter struct {
+	invoke Invoker
+}
+
+// FromGo converts a Go value into its script counterpart:
+// integers -> Integer, *big.Int -> BigInt, floats -> Number, string -> String,
+// bool -> Boolean, slices/arrays -> Array, maps/structs -> Hash,
+// funcs -> HostFunction.
+func FromGo(value interface{}) (Object, error) {
+	switch value := value.(type) {
+	case nil:
+		return NIL, nil
+	case Object:
+		return value, nil
+	case *big.Int:
+		if value == nil {
+			return NIL, nil
+		}
+		return &BigInt{Value: new(big.Int).Set(value)}, nil
+	}
+
+	return fromReflect(reflect.ValueOf(value))
+}
+
+func fromReflect(v reflect.Value) (Object, error) {
+	if v.IsValid() && v.Type().Implements(objectType) && v.CanInterface() {
+		if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return NIL, nil
+			}
+		}
+		return v.Interface().(Object), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return NIL, nil
+
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return NIL, nil
+		}
+		return fromReflect(v.Elem())
+
+	case reflect.Bool:
+		if v.Bool() {
+			return TRUE, nil
+		}
+		return FALSE, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Integer{Value: v.Int()}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return &Number{Value: float64(v.Uint())}, nil
+		}
+		return &Integer{Value: int64(v.Uint())}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return numberFromFloat(v.Float()), nil
+
+	case reflect.String:
+		return &String{Value: v.String()}, nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return &Array{Elements: []Object{}}, nil
+		}
+
+		elements := make([]Object, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, err := fromReflect(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			elements[i] = elem
+		}
+		return &Array{Elements: elements}, nil
+
+	case reflect.Map:
+		pairs := make(map[HashKey]HashPair, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := fromReflect(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+
+			hashable, ok := key.(Hashable)
+			if !ok {
+				return nil, fmt.Errorf("cannot use key type '%s' as a hash key", key.Type())
+			}
+
+			value, err := fromReflect(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key.Inspect(), err)
+			}
+
+			pairs[hashable.HashKey()] = HashPair{Key: key, Value: value}
+		}
+		return &Hash{Pairs: pairs}, nil
+
+	case reflect.Struct:
+		pairs := make(map[HashKey]HashPair)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := structFieldName(field)
+			if !ok {
+				continue
+			}
+
+			value, err := fromReflect(v.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			key := &String{Value: name}
+			pairs[key.HashKey()] = HashPair{Key: key, Value: value}
+		}
+		return &Hash{Pairs: pairs}, nil
+
+	case reflect.Func:
+		if v.IsNil() {
+			return NIL, nil
+		}
+
+		fn, err := WrapGoFunc(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return &HostFunction{Name: v.Type().String(), Fn: fn}, nil
+	}
+
+	return nil, fmt.Errorf("cannot convert Go type '%s' into a script value", v.Type())
+}
+
+// ToGo stores obj into the value pointed to by target,
+// converting between script and Go types where possible.
+func ToGo(obj Object, target interface{}) error {
+	return ToGoWithInvoker(obj, target, nil)
+}
+
+// ToGoWithInvoker is ToGo, but script functions can also be stored into
+// Go func types; calling them goes through invoke.
+func ToGoWithInvoker(obj Object, target interface{}, invoke Invoker) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("ToGo target must be a non-nil pointer, got %T", target)
+	}
+
+	conv := converter{invoke: invoke}
+	return conv.assign(obj, ptr.Elem())
+}
+
+// ToGoValue converts obj into the closest plain Go value:
+// int64, float64, *big.Int, *big.Rat for a Decimal, string, bool, nil,
+// []interface{} or map[string]interface{}.
+// Functions are returned as-is.
+func ToGoValue(obj Object) interface{} {
+	switch obj := obj.(type) {
+	case nil, *Nil:
+		return nil
+	case *Integer:
+		return obj.Value
+	case *Number:
+		return obj.Value
+	case *BigInt:
+		return new(big.Int).Set(obj.Value)
+	case *Decimal:
+		return obj.Rat()
+	case *NaN:
+		return math.NaN()
+	case *Infinity:
+		return math.Inf(obj.Sign)
+	case *String:
+		return obj.Value
+	case *Boolean:
+		return obj.Value
+	case *Array:
+		values := make([]interface{}, len(obj.Elements))
+		for i, elem := range obj.Elements {
+			values[i] = ToGoValue(elem)
+		}
+		return values
+	case *Hash:
+		values := make(map[string]interface{}, len(obj.Pairs))
+		for _, pair := range obj.Pairs {
+			key := pair.Key.Inspect()
+			if str, ok := pair.Key.(*String); ok {
+				key = str.Value
+			}
+			values[key] = ToGoValue(pair.Value)
+		}
+		return values
+	default:
+		return obj
+	}
+}
+
+func (conv converter) assign(obj Object, dest reflect.Value) error {
+	if obj == nil {
+		obj = NIL
+	}
+
+	// Destination wants a script value as-is ---
+	if reflect.TypeOf(obj).AssignableTo(dest.Type()) && dest.Type() != reflect.TypeOf((*interface{})(nil)).Elem() {
+		dest.Set(reflect.ValueOf(obj))
+		return nil
+	}
+
+	if obj.Type() == NIL_OBJECT {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	switch dest.Kind() {
+	case reflect.Interface:
+		if dest.NumMethod() != 0 {
+			break
+		}
+
+		value := ToGoValue(obj)
+		if value == nil {
+			dest.Set(reflect.Zero(dest.Type()))
+		} else {
+			dest.Set(reflect.ValueOf(value))
+		}
+		return nil
+
+	case reflect.Pointer:
+		elem := reflect.New(dest.Type().Elem())
+		if err := conv.assign(obj, elem.Elem()); err != nil {
+			return err
+		}
+		dest.Set(elem)
+		return nil
+
+	case reflect.Bool:
+		if b, ok := obj.(*Boolean); ok {
+			dest.SetBool(b.Value)
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if integer, ok := obj.(*Integer); ok {
+			if dest.OverflowInt(integer.Value) {
+				return fmt.Errorf("integer %d overflows %s", integer.Value, dest.Type())
+			}
+			dest.SetInt(integer.Value)
+			return nil
+		}
+
+		num, ok := obj.(*Number)
+		if !ok {
+			break
+		}
+		if num.Value != math.Trunc(num.Value) {
+			return fmt.Errorf("cannot convert non-integer number %g into %s", num.Value, dest.Type())
+		}
+		if dest.OverflowInt(int64(num.Value)) || num.Value > math.MaxInt64 || num.Value < math.MinInt64 {
+			return fmt.Errorf("number %g overflows %s", num.Value, dest.Type())
+		}
+		dest.SetInt(int64(num.Value))
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if integer, ok := obj.(*Integer); ok {
+			if integer.Value < 0 || dest.OverflowUint(uint64(integer.Value)) {
+				return fmt.Errorf("integer %d overflows %s", integer.Value, dest.Type())
+			}
+			dest.SetUint(uint64(integer.Value))
+			return nil
+		}
+
+		num, ok := obj.(*Number)
+		if !ok {
+			break
+		}
+		if num.Value != math.Trunc(num.Value) || num.Value < 0 {
+			return fmt.Errorf("cannot convert number %g into %s", num.Value, dest.Type())
+		}
+		if num.Value > math.MaxUint64 || dest.OverflowUint(uint64(num.Value)) {
+			return fmt.Errorf("number %g overflows %s", num.Value, dest.Type())
+		}
+		dest.SetUint(uint64(num.Value))
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		switch num := obj.(type) {
+		case *Integer:
+			dest.SetFloat(float64(num.Value))
+			return nil
+		case *Number:
+			dest.SetFloat(num.Value)
+			return nil
+		case *NaN:
+			dest.SetFloat(math.NaN())
+			return nil
+		case *Infinity:
+			dest.SetFloat(math.Inf(num.Sign))
+			return nil
+		}
+
+	case reflect.String:
+		if str, ok := obj.(*String); ok {
+			dest.SetString(str.Value)
+			return nil
+		}
+
+	case reflect.Slice:
+		arr, ok := obj.(*Array)
+		if !ok {
+			break
+		}
+		slice := reflect.MakeSlice(dest.Type(), len(arr.Elements), len(arr.Elements))
+		for i, elem := range arr.Elements {
+			if err := conv.assign(elem, slice.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		dest.Set(slice)
+		return nil
+
+	case reflect.Array:
+		arr, ok := obj.(*Array)
+		if !ok {
+			break
+		}
+		if len(arr.Elements) != dest.Len() {
+			return fmt.Errorf("cannot convert array of length %d into %s", len(arr.Elements), dest.Type())
+		}
+		for i, elem := range arr.Elements {
+			if err := conv.assign(elem, dest.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		hash, ok := obj.(*Hash)
+		if !ok {
+			break
+		}
+		m := reflect.MakeMapWithSize(dest.Type(), len(hash.Pairs))
+		for _, pair := range hash.Pairs {
+			key := reflect.New(dest.Type().Key()).Elem()
+			if err := conv.assign(pair.Key, key); err != nil {
+				return fmt.Errorf("key %s: %w", pair.Key.Inspect(), err)
+			}
+
+			value := reflect.New(dest.Type().Elem()).Elem()
+			if err := conv.assign(pair.Value, value); err != nil {
+				return fmt.Errorf("key %s: %w", pair.Key.Inspect(), err)
+			}
+
+			m.SetMapIndex(key, value)
+		}
+		dest.Set(m)
+		return nil
+
+	case reflect.Struct:
+		hash, ok := obj.(*Hash)
+		if !ok {
+			break
+		}
+		t := dest.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := structFieldName(field)
+			if !ok {
+				continue
+			}
+
+			pair, ok := hash.Pairs[(&String{Value: name}).HashKey()]
+			if !ok {
+				continue
+			}
+
+			if err := conv.assign(pair.Value, dest.Field(i)); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+		return nil
+
+	case reflect.Func:
+		if obj.Type() != FUNCTION_OBJECT || conv.invoke == nil {
+			break
+		}
+		dest.Set(conv.goFunc(obj, dest.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("cannot convert script %s into Go type %s", obj.Type(), dest.Type())
+}
+
+// WrapGoFunc adapts any Go function into a HostFunctionFn.
+// Arguments are converted with ToGo, results with FromGo,
+// and a trailing error result is returned as the call's error.
+func WrapGoFunc(fn interface{}) (HostFunctionFn, error) {
+	return WrapGoFuncWithInvoker(fn, nil)
+}
+
+// WrapGoFuncWithInvoker is WrapGoFunc, but parameters of Go func type
+// accept script functions (callbacks) which are run through invoke.
+func WrapGoFuncWithInvoker(fn interface{}, invoke Invoker) (HostFunctionFn, error) {
+	if hostFn, ok := fn.(HostFunctionFn); ok {
+		return hostFn, nil
+	}
+	if hostFn, ok := fn.(func(args ...Object) (Object, error)); ok {
+		return hostFn, nil
+	}
+
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("cannot wrap non-function type %T", fn)
+	}
+
+	t := v.Type()
+	returnsError := t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+	valueCount := t.NumOut()
+	if returnsError {
+		valueCount--
+	}
+	if valueCount > 1 {
+		return nil, fmt.Errorf("cannot wrap %s: functions may return at most one value and an error", t)
+	}
+
+	return func(args ...Object) (Object, error) {
+		conv := converter{invoke: invoke}
+		in, err := conv.goArguments(t, args)
+		if err != nil {
+			return nil, err
+		}
+
+		out := v.Call(in)
+
+		if returnsError {
+			if errValue := out[len(out)-1]; !errValue.IsNil() {
+				return nil, errValue.Interface().(error)
+			}
+		}
+
+		if valueCount == 0 {
+			return NIL, nil
+		}
+
+		return fromReflect(out[0])
+	}, nil
+}
+
+func (conv converter) goArguments(t reflect.Type, args []Object) ([]reflect.Value, error) {
+	fixed := t.NumIn()
+	if t.IsVariadic() {
+		fixed--
+		if len(args) < fixed {
+			return nil, fmt.Errorf("Expected at least %d arguments, got %d", fixed, len(args))
+		}
+	} else if len(args) != fixed {
+		return nil, fmt.Errorf("Expected %d arguments, got %d", fixed, len(args))
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var paramType reflect.Type
+		if i >= fixed {
+			paramType = t.In(fixed).Elem()
+		} else {
+			paramType = t.In(i)
+		}
+
+		param := reflect.New(paramType).Elem()
+		if err := conv.assign(arg, param); err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		in[i] = param
+	}
+
+	return in, nil
+}
+
+// goFunc builds a Go func of type t that calls the script function fn ---
+func (conv converter) goFunc(fn Object, t reflect.Type) reflect.Value {
+	returnsError := t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+
+	return reflect.MakeFunc(t, func(in []reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, t.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(t.Out(i))
+		}
+
+		fail := func(err error) []reflect.Value {
+			if !returnsError {
+				panic(err)
+			}
+			out[len(out)-1] = reflect.ValueOf(&err).Elem()
+			return out
+		}
+
+		args := make([]Object, 0, len(in))
+		for i, value := range in {
+			if t.IsVariadic() && i == len(in)-1 {
+				for j := 0; j < value.Len(); j++ {
+					arg, err := fromReflect(value.Index(j))
+					if err != nil {
+						return fail(err)
+					}
+					args = append(args, arg)
+				}
+				continue
+			}
+
+			arg, err := fromReflect(value)
+			if err != nil {
+				return fail(err)
+			}
+			args = append(args, arg)
+		}
+
+		result, err := conv.invoke(fn, args...)
+		if err != nil {
+			return fail(err)
+		}
+
+		if len(out) > 0 && !(returnsError && len(out) == 1) {
+			value := reflect.New(t.Out(0)).Elem()
+			if err := conv.assign(result, value); err != nil {
+				return fail(err)
+			}
+			out[0] = value
+		}
+
+		return out
+	})
+}
+
+func structFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get(structTag)
+	if tag == "-" {
+		return "", false
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
+
+func numberFromFloat(value float64) Object {
+	switch {
+	case math.IsNaN(value):
+		return NAN
+	case math.IsInf(value, 1):
+		return INFINITY
+	case math.IsInf(value, -1):
+		return NEG_INFINITY
+	}
+
+	return &Number{Value: value}
+}
@@ -3,6 +3,8 @@ package object
 type Environment struct {
 	store map[string]Object
 	outer *Environment
+
+	frame *CallFrame // Set on the environment of a function call ---
 }
 
 func NewEnvironment(outer *Environment) *Environment {
@@ -36,11 +38,28 @@ func (e *Environment) Set(name string, value Object) (Object, bool) {
 	return value, exists
 }
 
+// Assign updates name in the nearest scope that declared it ---
+func (e *Environment) Assign(name string, value Object) bool {
+	for env := e; env != nil; env = env.outer {
+		if env.DoesExist(name) {
+			env.store[name] = value
+			return true
+		}
+	}
+
+	return false
+}
+
 func (e *Environment) Declare(name string, value Object) Object {
 	e.store[name] = value
 	return value
 }
 
+// Delete forgets name in e, so it can be declared again ---
+func (e *Environment) Delete(name string) {
+	delete(e.store, name)
+}
+
 func (e *Environment) DoesExist(name string) bool {
 	_, result := e.store[name]
 
@@ -50,3 +69,19 @@ func (e *Environment) DoesExist(name string) bool {
 func (e *Environment) GetOuter() *Environment {
 	return e.outer
 }
+
+func (e *Environment) SetCallFrame(frame *CallFrame) {
+	e.frame = frame
+}
+
+// CallFrame is the call e (or the nearest enclosing scope) belongs to, ---
+// nil at the top level ---
+func (e *Environment) CallFrame() *CallFrame {
+	for env := e; env != nil; env = env.outer {
+		if env.frame != nil {
+			return env.frame
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package object
+
+import "fmt"
+
+// ResumeFn runs a generator until its next yield (done is false), ---
+// or until it returns (done is true, value is the returned value) ---
+type ResumeFn func(sent Object) (value Object, done bool, err error)
+
+// Generator is what calling a generator function returns. How it ---
+// suspends is up to the engine, both only provide resume and stop ---
+type Generator struct {
+	Name string
+	Done bool
+
+	resume  ResumeFn
+	stop    func()
+	running bool
+}
+
+func NewGenerator(name string, resume ResumeFn, stop func()) *Generator {
+	return &Generator{Name: name, resume: resume, stop: stop}
+}
+
+func (o *Generator) Type() ObjectType {
+	return GENERATOR_OBJECT
+}
+
+func (o *Generator) Inspect() string {
+	if o.Name == "" {
+		return "[ Generator ]"
+	}
+
+	return "[ Generator '" + o.Name + "' ]"
+}
+
+// Next resumes the generator, sent becomes the value of the paused yield. ---
+// A finished generator keeps returning NIL ---
+func (o *Generator) Next(sent Object) (Object, error) {
+	if o.Done {
+		return NIL, nil
+	}
+
+	if o.running {
+		return nil, fmt.Errorf("Cannot resume %s from inside itself", o.Inspect())
+	}
+
+	o.running = true
+	value, done, err := o.resume(sent)
+	o.running = false
+
+	if err != nil || done {
+		o.Done = true
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return NIL, nil
+	}
+
+	return value, nil
+}
+
+// Close stops a suspended generator, it won't resume again.
+func (o *Generator) Close() {
+	if o.Done || o.running {
+		return
+	}
+
+	o.Done = true
+	if o.stop != nil {
+		o.stop()
+	}
+}
@@ -0,0 +1,271 @@
+package object
+
+import (
+	"math"
+
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// NOTE: This file only contains Infinity and NaN's semantics ---
+// I (caelondev) placed it on a seperate file because the NaN and ---
+// Inf rule is so massive that it's worth placing it on a new file ---
+
+// InfinityWithSign is Infinity or -Infinity, never a new object, since ---
+// the VM compares them by identity ---
+func InfinityWithSign(sign int) Object {
+	if sign >= 0 {
+		return INFINITY
+	}
+	return NEG_INFINITY
+}
+
+func signFromNumber(n float64) int {
+	if math.Signbit(n) {
+		return -1
+	}
+	return 1
+}
+
+// FloatOperation works out `left operator right` between Numbers, ---
+// Infinity and NaN for both engines. A float result that overflows or ---
+// isn't a number is Infinity or NaN, never a Number holding one. It's ---
+// nil for any other operand or operator ---
+func FloatOperation(op token.TokenType, left, right Object) Object {
+	if left.Type() == NAN_OBJECT || right.Type() == NAN_OBJECT {
+		switch op {
+		case token.EQUAL, token.LESS, token.GREATER, token.LESS_EQUAL, token.GREATER_EQUAL:
+			return FALSE
+		case token.NOT_EQUAL:
+			return TRUE
+		default:
+			return NAN
+		}
+	}
+
+	switch l := left.(type) {
+	case *Infinity:
+		switch r := right.(type) {
+		case *Infinity:
+			return infInf(op, l, r)
+		case *Number:
+			return infNum(op, l, r)
+		}
+
+	case *Number:
+		switch r := right.(type) {
+		case *Infinity:
+			return numInf(op, l, r)
+		case *Number:
+			return numNum(op, l.Value, r.Value)
+		}
+	}
+
+	return nil
+}
+
+func numNum(op token.TokenType, l, r float64) Object {
+	var result float64
+
+	switch op {
+	case token.PLUS:
+		result = l + r
+	case token.MINUS:
+		result = l - r
+	case token.STAR:
+		result = l * r
+	case token.SLASH:
+		result = l / r
+	case token.CARET:
+		result = math.Pow(l, r)
+
+	case token.LESS:
+		return nativeBool(l < r)
+	case token.GREATER:
+		return nativeBool(l > r)
+	case token.LESS_EQUAL:
+		return nativeBool(l <= r)
+	case token.GREATER_EQUAL:
+		return nativeBool(l >= r)
+	case token.EQUAL:
+		return nativeBool(l == r)
+	case token.NOT_EQUAL:
+		return nativeBool(l != r)
+
+	default:
+		return nil
+	}
+
+	if math.IsNaN(result) {
+		return NAN
+	}
+	if math.IsInf(result, 0) {
+		return InfinityWithSign(int(math.Copysign(1, result)))
+	}
+
+	return &Number{Value: result}
+}
+
+func infInf(op token.TokenType, l, r *Infinity) Object {
+	switch op {
+	case token.PLUS:
+		if l.Sign == r.Sign {
+			return InfinityWithSign(l.Sign)
+		}
+		return NAN
+
+	case token.MINUS:
+		if l.Sign == r.Sign {
+			return NAN
+		}
+		return InfinityWithSign(l.Sign)
+
+	case token.STAR:
+		return InfinityWithSign(l.Sign * r.Sign)
+
+	case token.SLASH:
+		return NAN
+
+	case token.CARET:
+		if r.Sign < 0 {
+			return &Number{Value: 0}
+		}
+		return INFINITY
+
+	case token.EQUAL:
+		return nativeBool(l.Sign == r.Sign)
+	case token.NOT_EQUAL:
+		return nativeBool(l.Sign != r.Sign)
+	case token.LESS:
+		return nativeBool(l.Sign < r.Sign)
+	case token.GREATER:
+		return nativeBool(l.Sign > r.Sign)
+	case token.LESS_EQUAL:
+		return nativeBool(l.Sign <= r.Sign)
+	case token.GREATER_EQUAL:
+		return nativeBool(l.Sign >= r.Sign)
+	}
+
+	return NIL
+}
+
+func infNum(op token.TokenType, inf *Infinity, num *Number) Object {
+	switch op {
+	case token.PLUS:
+		return InfinityWithSign(inf.Sign)
+
+	case token.MINUS:
+		return InfinityWithSign(inf.Sign)
+
+	case token.STAR:
+		if num.Value == 0 {
+			return NAN
+		}
+		return InfinityWithSign(inf.Sign * signFromNumber(num.Value))
+
+	case token.SLASH:
+		if num.Value == 0 {
+			return InfinityWithSign(inf.Sign)
+		}
+		return InfinityWithSign(inf.Sign * signFromNumber(num.Value))
+
+	case token.CARET:
+		if num.Value == 0 {
+			return &Number{Value: 1}
+		}
+		if num.Value < 0 {
+			return &Number{Value: 0}
+		}
+		if inf.Sign < 0 && num.Value != math.Floor(num.Value) {
+			return NAN
+		}
+		if inf.Sign < 0 && int(num.Value)%2 == 0 {
+			return INFINITY
+		}
+		return InfinityWithSign(inf.Sign)
+
+	case token.EQUAL:
+		return FALSE
+	case token.NOT_EQUAL:
+		return TRUE
+	case token.LESS:
+		return nativeBool(inf.Sign < 0)
+	case token.GREATER:
+		return nativeBool(inf.Sign > 0)
+	case token.LESS_EQUAL:
+		return nativeBool(inf.Sign < 0)
+	case token.GREATER_EQUAL:
+		return nativeBool(inf.Sign > 0)
+	}
+
+	return NAN
+}
+
+func numInf(op token.TokenType, num *Number, inf *Infinity) Object {
+	switch op {
+	case token.PLUS:
+		return InfinityWithSign(inf.Sign)
+
+	case token.MINUS:
+		return InfinityWithSign(-inf.Sign)
+
+	case token.STAR:
+		if num.Value == 0 {
+			return NAN
+		}
+		return InfinityWithSign(inf.Sign * signFromNumber(num.Value))
+
+	case token.SLASH:
+		return &Number{Value: math.Copysign(0, num.Value)}
+
+	case token.CARET:
+		absNum := math.Abs(num.Value)
+
+		if num.Value == 0 {
+			if inf.Sign > 0 {
+				return &Number{Value: 0}
+			}
+			return INFINITY
+		}
+
+		if absNum == 1 {
+			if num.Value == 1 {
+				return &Number{Value: 1}
+			}
+			return NAN
+		}
+
+		if inf.Sign > 0 {
+			if absNum < 1 {
+				return &Number{Value: 0}
+			}
+			return InfinityWithSign(signFromNumber(num.Value))
+		} else {
+			if absNum < 1 {
+				return INFINITY
+			}
+			return &Number{Value: 0}
+		}
+
+	case token.EQUAL:
+		return FALSE
+	case token.NOT_EQUAL:
+		return TRUE
+	case token.LESS:
+		return nativeBool(inf.Sign > 0)
+	case token.GREATER:
+		return nativeBool(inf.Sign < 0)
+	case token.LESS_EQUAL:
+		return nativeBool(inf.Sign > 0)
+	case token.GREATER_EQUAL:
+		return nativeBool(inf.Sign < 0)
+	}
+
+	return NAN
+}
+
+func nativeBool(v bool) *Boolean {
+	if v {
+		return TRUE
+	}
+	return FALSE
+}
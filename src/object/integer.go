@@ -0,0 +1,386 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// Integer is an exact 64-bit integer, what literals without a '.' or ---
+// an exponent evaluate to. Next to a Number it's promoted to one ---
+type Integer struct {
+	Value int64
+}
+
+func (o *Integer) Type() ObjectType {
+	return INTEGER_OBJECT
+}
+
+func (o *Integer) Inspect() string {
+	return strconv.FormatInt(o.Value, 10)
+}
+
+func (o *Integer) HashKey() HashKey {
+	return HashKey{Type: o.Type(), Value: uint64(o.Value)}
+}
+
+// IntegerArithmetic works out `l operator r`. The result is nil when ---
+// it isn't an integer, like 7 / 2, the caller then promotes both ---
+// sides and does it with floats. A result that doesn't fit in 64 bits ---
+// is an error rather than a wrong number ---
+func IntegerArithmetic(operator token.TokenType, l, r int64) (Object, error) {
+	var result int64
+	overflow := false
+
+	switch operator {
+	case token.PLUS:
+		result = l + r
+		overflow = (l > 0 && r > 0 && result < 0) || (l < 0 && r < 0 && result >= 0)
+	case token.MINUS:
+		result = l - r
+		overflow = (l >= 0 && r < 0 && result < 0) || (l < 0 && r > 0 && result >= 0)
+	case token.STAR:
+		result, overflow = multiply(l, r)
+
+	case token.SLASH:
+		if r == 0 || l%r != 0 {
+			return nil, nil
+		}
+		result = l / r
+		overflow = l == math.MinInt64 && r == -1
+
+	case token.CARET:
+		if r < 0 {
+			return nil, nil
+		}
+		result, overflow = power(l, r)
+
+	default:
+		return nil, nil
+	}
+
+	if overflow {
+		return nil, fmt.Errorf("Integer overflow in `%d %s %d`, the result doesn't fit in 64 bits", l, operator, r)
+	}
+
+	return &Integer{Value: result}, nil
+}
+
+func multiply(l, r int64) (int64, bool) {
+	if l == 0 || r == 0 {
+		return 0, false
+	}
+
+	result := l * r
+	overflow := result/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64)
+	return result, overflow
+}
+
+// power squares its way up, stopping at the first overflow ---
+func power(base, exponent int64) (int64, bool) {
+	result := int64(1)
+
+	for exponent > 0 {
+		var overflow bool
+		if exponent&1 == 1 {
+			if result, overflow = multiply(result, base); overflow {
+				return 0, true
+			}
+		}
+
+		if exponent >>= 1; exponent > 0 {
+			if base, overflow = multiply(base, base); overflow {
+				return 0, true
+			}
+		}
+	}
+
+	return result, false
+}
+
+// Negate is -value, the smallest int64 has no positive counterpart ---
+func Negate(value int64) (Object, error) {
+	if value == math.MinInt64 {
+		return nil, fmt.Errorf("Integer overflow in `-(%d)`, the result doesn't fit in 64 bits", value)
+	}
+
+	return &Integer{Value: -value}, nil
+}
+
+// ToFloat reads an Integer or a Number as a float ---
+func ToFloat(obj Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return float64(obj.Value), true
+	case *Number:
+		return obj.Value, true
+	}
+
+	return 0, false
+}
+
+// ToInt reads an index or a count, a Number is truncated ---
+func ToInt(obj Object) (int, bool) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return int(obj.Value), true
+	case *Number:
+		return int(obj.Value), true
+	case *BigInt:
+		if obj.Value.IsInt64() {
+			return int(obj.Value.Int64()), true
+		}
+	}
+
+	return 0, false
+}
+
+// CompareNumbers orders two Integers or Numbers, -1, 0 or 1 like ---
+// strings.Compare. An Integer next to a Number is compared exactly, ---
+// not through a float that may have rounded it, and so is anything ---
+// next to a BigInt or a Decimal. ok is false for NaN ---
+func CompareNumbers(left, right Object) (result int, ok bool) {
+	if IsBig(left) || IsBig(right) {
+		return compareExact(left, right)
+	}
+
+	switch l := left.(type) {
+	case *Integer:
+		switch r := right.(type) {
+		case *Integer:
+			return compare(l.Value, r.Value), true
+		case *Number:
+			return compareIntegerFloat(l.Value, r.Value)
+		}
+
+	case *Number:
+		switch r := right.(type) {
+		case *Integer:
+			result, ok := compareIntegerFloat(r.Value, l.Value)
+			return -result, ok
+		case *Number:
+			if math.IsNaN(l.Value) || math.IsNaN(r.Value) {
+				return 0, false
+			}
+			return compare(l.Value, r.Value), true
+		}
+	}
+
+	return 0, false
+}
+
+func compare[T int64 | float64](l, r T) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+
+	return 0
+}
+
+func compareIntegerFloat(i int64, f float64) (int, bool) {
+	switch {
+	case math.IsNaN(f):
+		return 0, false
+	case f >= math.MaxInt64: // 2^63, the float just past every int64 ---
+		return -1, true
+	case f < math.MinInt64:
+		return 1, true
+	}
+
+	whole := math.Trunc(f)
+	if result := compare(i, int64(whole)); result != 0 {
+		return result, true
+	}
+
+	// Same whole part, the fraction decides ---
+	return compare(whole, f), true
+}
+
+func compareExact(left, right Object) (int, bool) {
+	l, lok := exactValue(left)
+	r, rok := exactValue(right)
+	if lok && rok {
+		return l.Cmp(r), true
+	}
+
+	// An infinity is past every finite value, NaN isn't ordered ---
+	if sign := infinitySign(left); sign != 0 {
+		return sign, true
+	}
+	if sign := infinitySign(right); sign != 0 {
+		return -sign, true
+	}
+
+	return 0, false
+}
+
+func infinitySign(obj Object) int {
+	switch obj := obj.(type) {
+	case *Infinity:
+		return obj.Sign
+	case *Number:
+		if math.IsInf(obj.Value, 0) {
+			return int(math.Copysign(1, obj.Value))
+		}
+	}
+
+	return 0
+}
+
+// exactInteger is f as an int64, when it's a whole number in range ---
+func exactInteger(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, false
+	}
+
+	return int64(f), true
+}
+
+// FloorDivide is how many times r fits in l, rounded down. Integers, ---
+// BigInts and Decimals stay exact, dividing one by zero is an error ---
+func FloorDivide(left, right Object) (Object, error) {
+	if IsBig(left) || IsBig(right) {
+		return floorBig(left, right, false)
+	}
+
+	l, lok := left.(*Integer)
+	r, rok := right.(*Integer)
+
+	if lok && rok {
+		if r.Value == 0 {
+			return nil, fmt.Errorf("Integer division by zero")
+		}
+		if l.Value == math.MinInt64 && r.Value == -1 {
+			return nil, fmt.Errorf("Integer overflow in `div(%d, -1)`, the result doesn't fit in 64 bits", l.Value)
+		}
+
+		quotient := l.Value / r.Value
+		if (l.Value%r.Value != 0) && ((l.Value < 0) != (r.Value < 0)) {
+			quotient--
+		}
+		return &Integer{Value: quotient}, nil
+	}
+
+	lf, rf, err := floatOperands("floor divide", left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return numberFromFloat(math.Floor(lf / rf)), nil
+}
+
+// Modulo is what's left of l after FloorDivide, it takes the sign ---
+// of r, so mod(-7, 3) is 2 ---
+func Modulo(left, right Object) (Object, error) {
+	if IsBig(left) || IsBig(right) {
+		return floorBig(left, right, true)
+	}
+
+	l, lok := left.(*Integer)
+	r, rok := right.(*Integer)
+
+	if lok && rok {
+		if r.Value == 0 {
+			return nil, fmt.Errorf("Integer modulo by zero")
+		}
+		if r.Value == -1 {
+			return &Integer{Value: 0}, nil // MinInt64 % -1 would panic ---
+		}
+
+		remainder := l.Value % r.Value
+		if remainder != 0 && (remainder < 0) != (r.Value < 0) {
+			remainder += r.Value
+		}
+		return &Integer{Value: remainder}, nil
+	}
+
+	lf, rf, err := floatOperands("take the modulo of", left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	remainder := math.Mod(lf, rf)
+	if remainder != 0 && (remainder < 0) != (rf < 0) {
+		remainder += rf
+	}
+	return numberFromFloat(remainder), nil
+}
+
+func floatOperands(action string, left, right Object) (float64, float64, error) {
+	l, lok := floatValue(left)
+	r, rok := floatValue(right)
+
+	if !lok || !rok {
+		return 0, 0, fmt.Errorf("Cannot %s %s and %s, both must be numbers", action, left.Type(), right.Type())
+	}
+
+	return l, r, nil
+}
+
+// floatValue is ToFloat that also takes NaN and Infinity ---
+func floatValue(obj Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *NaN:
+		return math.NaN(), true
+	case *Infinity:
+		return math.Inf(obj.Sign), true
+	}
+
+	return ToFloat(obj)
+}
+
+// ToInteger converts obj for to_integer(): Numbers are truncated ---
+// toward zero and strings are read as integer literals ---
+func ToInteger(obj Object) (Object, error) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return obj, nil
+
+	case *Number:
+		value := math.Trunc(obj.Value)
+		if value >= math.MaxInt64 || value < math.MinInt64 || math.IsNaN(value) {
+			return nil, fmt.Errorf("Number %g doesn't fit in a 64-bit integer", obj.Value)
+		}
+		return &Integer{Value: int64(value)}, nil
+
+	case *BigInt, *Decimal:
+		value, _ := ToBigInt(obj)
+		if whole := value.(*BigInt).Value; whole.IsInt64() {
+			return &Integer{Value: whole.Int64()}, nil
+		}
+		return nil, fmt.Errorf("%s doesn't fit in a 64-bit integer", Display(obj))
+
+	case *Boolean:
+		if obj.Value {
+			return &Integer{Value: 1}, nil
+		}
+		return &Integer{Value: 0}, nil
+
+	case *String:
+		text := strings.TrimSpace(obj.Value)
+		negative := strings.HasPrefix(text, "-")
+		digits := strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+")
+
+		if digits == "" || !lexer.IsInteger(digits) {
+			return nil, fmt.Errorf("Cannot convert %s to an integer", obj.Inspect())
+		}
+
+		value, err := lexer.IntegerValue(digits)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot convert %s to an integer: %s", obj.Inspect(), err)
+		}
+		if negative {
+			value = -value
+		}
+		return &Integer{Value: value}, nil
+	}
+
+	return nil, fmt.Errorf("Cannot convert type '%s' to an integer", obj.Type())
+}
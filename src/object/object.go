@@ -4,15 +4,20 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
 	"strings"
 
 	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/code"
 )
 
 type ObjectType string
 
 const (
 	NUMBER_OBJECT       = "NUMBER"
+	INTEGER_OBJECT      = "INTEGER"
+	BIGINT_OBJECT       = "BIGINT"
+	DECIMAL_OBJECT      = "DECIMAL"
 	STRING_OBJECT       = "STRING"
 	ARRAY_OBJECT        = "ARRAY"
 	BOOLEAN_OBJECT      = "BOOLEAN"
@@ -21,8 +26,18 @@ const (
 	INFINITY_OBJECT     = "INFINITY"
 	RETURN_VALUE_OBJECT = "RETURN_VALUE"
 	ERROR_OBJECT        = "ERROR"
+	EXCEPTION_OBJECT    = "EXCEPTION"
 	FUNCTION_OBJECT     = "FUNCTION"
 	HASH_OBJECT         = "HASH"
+	QUOTE_OBJECT        = "QUOTE"
+	MACRO_OBJECT        = "MACRO"
+
+	COMPILED_FUNCTION_OBJECT = "COMPILED_FUNCTION"
+	CELL_OBJECT              = "CELL"
+
+	TASK_OBJECT      = "TASK"
+	CHANNEL_OBJECT   = "CHANNEL"
+	GENERATOR_OBJECT = "GENERATOR"
 )
 
 var (
@@ -91,6 +106,21 @@ func (o *String) Inspect() string {
 	return fmt.Sprintf("\"%s\"", o.Value)
 }
 
+// Display is how print shows obj, strings without their quotes ---
+// and everything else as Inspect writes it ---
+func Display(obj Object) string {
+	switch obj := obj.(type) {
+	case *String:
+		return obj.Value
+	case *BigInt:
+		return obj.Value.String()
+	case *Decimal:
+		return obj.String()
+	}
+
+	return obj.Inspect()
+}
+
 func (o *String) HashKey() HashKey {
 	hash := fnv.New64a()
 	hash.Write([]byte(o.Value))
@@ -110,8 +140,14 @@ func (o *Number) Inspect() string {
 	return fmt.Sprintf("%g", o.Value)
 }
 
+// A whole Number is the same key as the Integer it equals, any ---
+// other one is keyed by its bits, so 1.5 and 1 don't collide ---
 func (o *Number) HashKey() HashKey {
-	return HashKey{Type: o.Type(), Value: uint64(o.Value)}
+	if value, ok := exactInteger(o.Value); ok {
+		return (&Integer{Value: value}).HashKey()
+	}
+
+	return HashKey{Type: o.Type(), Value: math.Float64bits(o.Value)}
 }
 
 type Boolean struct {
@@ -192,6 +228,15 @@ type Error struct {
 	Message string
 	Hint    string
 	NodeStr string
+
+	// What the script threw, when it wasn't an error already ---
+	Value Object
+
+	// Fatal errors (e.g. an aborted task) can't be caught ---
+	Fatal bool
+
+	// Calls active where the error was raised, innermost first ---
+	Trace []TraceFrame
 }
 
 func (o *Error) Type() ObjectType {
@@ -202,11 +247,68 @@ func (o *Error) Inspect() string {
 	return fmt.Sprintf("Error at Ln %d:%d - %s", o.Line, o.Column, o.Message)
 }
 
+// Field backs err["message"] and friends in scripts ---
+func (o *Error) Field(name string) (Object, bool) {
+	switch name {
+	case "message":
+		return &String{Value: o.Message}, true
+	case "hint":
+		return &String{Value: o.Hint}, true
+	case "line":
+		return &Integer{Value: int64(o.Line)}, true
+	case "column":
+		return &Integer{Value: int64(o.Column)}, true
+	case "value":
+		if o.Value == nil {
+			return NIL, true
+		}
+		return o.Value, true
+	case "trace":
+		return o.traceObject(), true
+
+	default:
+		return nil, false
+	}
+}
+
+// Exception is a caught error as scripts see it. It's a separate type ---
+// because a plain *Error value is what unwinds the evaluator ---
+type Exception struct {
+	Err *Error
+}
+
+func (o *Exception) Type() ObjectType {
+	return EXCEPTION_OBJECT
+}
+
+func (o *Exception) Inspect() string {
+	return o.Err.Inspect()
+}
+
+// NewThrownError wraps a thrown value, rethrown exceptions keep their position ---
+func NewThrownError(value Object, line, column uint) *Error {
+	switch value := value.(type) {
+	case *Error:
+		return value
+	case *Exception:
+		return value.Err
+	}
+
+	message := value.Inspect()
+	if str, ok := value.(*String); ok {
+		message = str.Value
+	}
+
+	return &Error{Line: line, Column: column, Message: message, Value: value}
+}
+
 type Function struct {
-	Parameters []*ast.Identifier
-	Name       *ast.Identifier
-	Body       *ast.BlockStatement
-	Scope      *Environment
+	Parameters  []*ast.Identifier
+	Patterns    []ast.Expression // Like ast.FunctionLiteral's ---
+	Name        *ast.Identifier
+	Body        *ast.BlockStatement
+	Scope       *Environment
+	IsGenerator bool
 }
 
 func (o *Function) Type() ObjectType {
@@ -221,6 +323,34 @@ func (o *Function) Inspect() string {
 	return fmt.Sprintf("[ Function '%s' ]", o.Name)
 }
 
+// Quote is code as a value, what quote() returns ---
+type Quote struct {
+	Node ast.Node
+}
+
+func (o *Quote) Type() ObjectType {
+	return QUOTE_OBJECT
+}
+
+func (o *Quote) Inspect() string {
+	return "QUOTE(" + o.Node.String() + ")"
+}
+
+// Macro only exists while macros are expanded ---
+type Macro struct {
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Scope      *Environment
+}
+
+func (o *Macro) Type() ObjectType {
+	return MACRO_OBJECT
+}
+
+func (o *Macro) Inspect() string {
+	return "[ Macro ]"
+}
+
 type NativeFunctionFn func(
 	callNode *ast.CallExpression,
 	args []Object,
@@ -262,3 +392,62 @@ func (o *Array) Inspect() string {
 
 	return out.String()
 }
+
+type CompiledFunction struct {
+	Instructions  code.Instructions
+	NumLocals     int
+	NumParameters int
+	Name          string
+	IsGenerator   bool
+	Handlers      []code.Handler
+	Lines         []code.SourcePos
+}
+
+func (o *CompiledFunction) Type() ObjectType {
+	return COMPILED_FUNCTION_OBJECT
+}
+
+func (o *CompiledFunction) Inspect() string {
+	if o.Name == "" {
+		return "[ Compiled Function ]"
+	}
+
+	return fmt.Sprintf("[ Compiled Function '%s' ]", o.Name)
+}
+
+// Closure is what the VM actually calls, a compiled function ---
+// paired with the free variables it captured ---
+type Closure struct {
+	Fn   *CompiledFunction
+	Free []Object
+}
+
+func (o *Closure) Type() ObjectType {
+	return FUNCTION_OBJECT
+}
+
+func (o *Closure) Inspect() string {
+	if o.Fn.Name == "" {
+		return "[ Anonymous Function ]"
+	}
+
+	return fmt.Sprintf("[ Function '%s' ]", o.Fn.Name)
+}
+
+// Cell boxes a local once a closure captures it, so the function ---
+// and every closure over it share the one variable ---
+type Cell struct {
+	Value Object
+}
+
+func (o *Cell) Type() ObjectType {
+	return CELL_OBJECT
+}
+
+func (o *Cell) Inspect() string {
+	if o.Value == nil {
+		return NIL.Inspect()
+	}
+
+	return o.Value.Inspect()
+}
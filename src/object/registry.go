@@ -0,0 +1,102 @@
+package object
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HostFunctionFn is the signature Go hosts implement.
+// Unlike NativeFunctionFn it doesn't depend on the AST,
+// so the same function works under the evaluator and the VM ---
+type HostFunctionFn func(args ...Object) (Object, error)
+
+type HostFunction struct {
+	Name string
+	Fn   HostFunctionFn
+}
+
+func (o *HostFunction) Type() ObjectType {
+	return FUNCTION_OBJECT
+}
+
+func (o *HostFunction) Inspect() string {
+	return "[ Native Function '" + o.Name + "' ]"
+}
+
+// Call runs the function and normalizes a nil result to NIL.
+func (o *HostFunction) Call(args ...Object) (Object, error) {
+	result, err := o.Fn(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return NIL, nil
+	}
+
+	return result, nil
+}
+
+type Registry struct {
+	functions map[string]*HostFunction
+
+	// Used by RegisterFunc wrappers whose parameters are Go funcs, ---
+	// so scripts can hand callbacks to the host ---
+	Invoker Invoker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		functions: make(map[string]*HostFunction),
+	}
+}
+
+// Register adds (or replaces) a host function under name.
+func (r *Registry) Register(name string, fn HostFunctionFn) *HostFunction {
+	hostFn := &HostFunction{Name: name, Fn: fn}
+	r.functions[name] = hostFn
+	return hostFn
+}
+
+// RegisterFunc wraps an arbitrary Go function, see WrapGoFunc.
+func (r *Registry) RegisterFunc(name string, fn interface{}) error {
+	wrapped, err := WrapGoFuncWithInvoker(fn, r.invoke)
+	if err != nil {
+		return err
+	}
+
+	r.Register(name, wrapped)
+	return nil
+}
+
+func (r *Registry) Lookup(name string) (*HostFunction, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	fn, ok := r.functions[name]
+	return fn, ok
+}
+
+// Names returns every registered name in a stable order.
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.functions))
+	for name := range r.functions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func (r *Registry) invoke(fn Object, args ...Object) (Object, error) {
+	if r.Invoker == nil {
+		return nil, fmt.Errorf("no invoker available to call %s", fn.Inspect())
+	}
+
+	return r.Invoker(fn, args...)
+}
@@ -0,0 +1,80 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TraceFrame is one line of a stack trace, innermost call first ---
+type TraceFrame struct {
+	Function string
+	File     string
+	Line     uint
+	Column   uint
+}
+
+func (f TraceFrame) String() string {
+	if f.File == "" {
+		return fmt.Sprintf("at %s (Ln %d:%d)", f.Function, f.Line, f.Column)
+	}
+
+	return fmt.Sprintf("at %s (%s:%d:%d)", f.Function, f.File, f.Line, f.Column)
+}
+
+// CallFrame is the evaluator's record of a running call, kept on the ---
+// function's environment so tasks and generators each see their own chain ---
+type CallFrame struct {
+	Function string
+	Line     uint // Of the call, in the caller ---
+	Column   uint
+	Caller   *CallFrame // nil when called from the top level ---
+}
+
+// Trace walks the call chain out from a failure at line:column ---
+func (f *CallFrame) Trace(file string, line, column uint) []TraceFrame {
+	trace := make([]TraceFrame, 0)
+
+	for frame := f; frame != nil; frame = frame.Caller {
+		trace = append(trace, TraceFrame{Function: frame.Function, File: file, Line: line, Column: column})
+		line, column = frame.Line, frame.Column
+	}
+
+	return append(trace, TraceFrame{Function: "<main>", File: file, Line: line, Column: column})
+}
+
+// StackTrace formats Trace one frame per line, for error output ---
+func (o *Error) StackTrace() string {
+	var out strings.Builder
+
+	for _, frame := range o.Trace {
+		out.WriteString("    " + frame.String() + "\n")
+	}
+
+	return out.String()
+}
+
+func (o *Error) traceObject() *Array {
+	elements := make([]Object, len(o.Trace))
+
+	for i, frame := range o.Trace {
+		pairs := make(map[HashKey]HashPair)
+		fields := []struct {
+			name  string
+			value Object
+		}{
+			{"function", &String{Value: frame.Function}},
+			{"file", &String{Value: frame.File}},
+			{"line", &Integer{Value: int64(frame.Line)}},
+			{"column", &Integer{Value: int64(frame.Column)}},
+		}
+
+		for _, field := range fields {
+			key := &String{Value: field.name}
+			pairs[key.HashKey()] = HashPair{Key: key, Value: field.value}
+		}
+
+		elements[i] = &Hash{Pairs: pairs}
+	}
+
+	return &Array{Elements: elements}
+}
@@ -0,0 +1,63 @@
+package object
+
+import "fmt"
+
+// NOTE: Both engines unpack patterns through these, so a value of ---
+// the wrong shape fails the same way in either ---
+
+// UnpackArray checks value fits an array pattern of count elements, ---
+// the first required of which have no default ---
+func UnpackArray(value Object, required, count int, hasRest bool) (*Array, error) {
+	array, ok := value.(*Array)
+	if !ok {
+		return nil, fmt.Errorf("Cannot destructure %s with an array pattern", value.Type())
+	}
+
+	length := len(array.Elements)
+	if length < required {
+		return nil, fmt.Errorf("Cannot destructure an array of length %d, the pattern needs at least %d", length, required)
+	}
+	if !hasRest && length > count {
+		return nil, fmt.Errorf("Cannot destructure an array of length %d, the pattern only takes %d, add ...rest for the others", length, count)
+	}
+
+	return array, nil
+}
+
+// UnpackElement is element i of array, nil past its end. rest takes ---
+// every element from i on as a new array instead ---
+func UnpackElement(array *Array, i int, rest bool) Object {
+	if rest {
+		elements := make([]Object, max(len(array.Elements)-i, 0))
+		copy(elements, array.Elements[min(i, len(array.Elements)):])
+		return &Array{Elements: elements}
+	}
+
+	if i < len(array.Elements) {
+		return array.Elements[i]
+	}
+	return NIL
+}
+
+// UnpackHash checks value fits a hash pattern ---
+func UnpackHash(value Object) (*Hash, error) {
+	hash, ok := value.(*Hash)
+	if !ok {
+		return nil, fmt.Errorf("Cannot destructure %s with a hash pattern", value.Type())
+	}
+
+	return hash, nil
+}
+
+// UnpackKey is key's value in hash. A missing key is nil when its ---
+// entry has a default, an error otherwise ---
+func UnpackKey(hash *Hash, key *String, hasDefault bool) (Object, error) {
+	if pair, ok := hash.Pairs[key.HashKey()]; ok {
+		return pair.Value, nil
+	}
+
+	if hasDefault {
+		return NIL, nil
+	}
+	return nil, fmt.Errorf("Cannot destructure the hash, it has no key %s", key.Inspect())
+}
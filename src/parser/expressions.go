@@ -2,9 +2,10 @@ package parser
 
 import (
 	"fmt"
-	"strconv"
+	"strings"
 
 	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
 	"github.com/caelondev/monkey-compiler-go/src/token"
 )
 
@@ -15,10 +16,12 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		return nil
 	}
 
+	start := p.currentToken
 	leftExpression := prefix()
 	if leftExpression == nil {
 		return nil
 	}
+	p.setSpan(leftExpression, start)
 
 	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
@@ -28,6 +31,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 		p.nextToken()                          // Advance left and inspect operator
 		leftExpression = infix(leftExpression) // Bubble up left
+		p.setSpan(leftExpression, start)
 	}
 
 	// Returns left ONLY IF THE NEXT TOKEN IS A SEMICOLON
@@ -40,17 +44,49 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 **/
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{
-		Token: p.currentToken,
-		Value: p.currentToken.Literal,
-	}
+	return p.parseName()
+}
+
+// parseName is the identifier at the current token, for the places ---
+// that take a name rather than an expression ---
+func (p *Parser) parseName() *ast.Identifier {
+	ident := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	p.setSpan(ident, p.currentToken)
+	return ident
 }
 
 func (p *Parser) parseNumberExpression() ast.Expression {
-	// TODO: Errors are being ignored, this might cause a crash
-	// when the tokenzer invalidly tokenize a numerical token
-	value, _ := strconv.ParseFloat(p.currentToken.Literal, 64)
-	return &ast.NumberLiteral{Token: p.currentToken, Value: value}
+	// The lexer only lets valid literals through ---
+	literal := p.currentToken.Literal
+
+	var expr ast.Expression
+	var err error
+
+	switch {
+	case strings.HasSuffix(literal, lexer.BigIntSuffix):
+		node := &ast.BigIntLiteral{Token: p.currentToken}
+		node.Value, err = lexer.BigIntValue(literal)
+		expr = node
+	case strings.HasSuffix(literal, lexer.DecimalSuffix):
+		node := &ast.DecimalLiteral{Token: p.currentToken}
+		node.Unscaled, node.Scale, err = lexer.DecimalValue(literal)
+		expr = node
+	case lexer.IsInteger(literal):
+		node := &ast.IntegerLiteral{Token: p.currentToken}
+		node.Value, err = lexer.IntegerValue(literal)
+		expr = node
+	default:
+		node := &ast.NumberLiteral{Token: p.currentToken}
+		node.Value, err = lexer.NumberValue(literal)
+		expr = node
+	}
+
+	if err != nil {
+		p.throwError("[Ln %d:%d] -> %s", p.currentToken.Line, p.currentToken.Column, err)
+		return nil
+	}
+
+	return expr
 }
 
 func (p *Parser) parseUnaryExpression() ast.Expression {
@@ -66,14 +102,54 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
 }
 
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	// SYNTAX ---
+	//
+	// "<text>${<expr>}<text>${<expr>}<text>"
+	expr := &ast.InterpolatedString{Token: p.currentToken}
+
+	for {
+		text := &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+		p.setSpan(text, p.currentToken)
+		expr.Parts = append(expr.Parts, text)
+
+		if p.currentTokenIs(token.STRING_TAIL) {
+			return expr
+		}
+
+		p.nextToken() // Eat the text ---
+		part := p.parseExpression(LOWEST)
+		if part == nil {
+			return nil
+		}
+		expr.Parts = append(expr.Parts, part)
+
+		if !p.peekTokenIs(token.STRING_MIDDLE) && !p.peekTokenIs(token.STRING_TAIL) {
+			p.throwError(
+				"[Ln %d:%d] -> Expected '}' to close the ${ in the string, got '%s' instead",
+				p.peekToken.Line,
+				p.peekToken.Column,
+				p.peekToken.Literal,
+			)
+			return nil
+		}
+		p.nextToken() // Advance to the next text ---
+	}
+}
+
 func (p *Parser) parseGroupExpression() ast.Expression {
-	p.nextToken()                     // Eat ( token
+	p.nextToken() // Eat ( token
+
+	noSliceBrace := p.noSliceBrace
+	p.noSliceBrace = false
 	expr := p.parseExpression(LOWEST) // Use LOWEST, not CALL
+	p.noSliceBrace = noSliceBrace
 
 	if !p.expectPeek(token.RIGHT_PARENTHESIS) { // Consume the )
 		return nil
 	}
 
+	// There's no node for the parentheses, expr's span takes them in ---
 	return expr
 }
 
@@ -97,15 +173,60 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	expr.Parameters = p.parseFunctionParameters()
+	expr.Parameters, expr.Patterns, expr.ParameterTypes = p.parseFunctionParameters()
+	expr.ReturnType = p.parseOptionalType()
 	if !p.expectPeek(token.LEFT_BRACE) {
 		return nil
 	}
 
-	expr.Body = p.parseBlockStatement()
+	expr.Body, expr.IsGenerator = p.parseFunctionBody()
 	return expr
 }
 
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	// SYNTAX ---
+	//
+	// macro(<Identifier>, ...) { <body> }
+	expr := &ast.MacroLiteral{Token: p.currentToken}
+
+	if !p.expectPeek(token.LEFT_PARENTHESIS) {
+		return nil
+	}
+
+	var patterns []ast.Expression
+	expr.Parameters, patterns, _ = p.parseFunctionParameters()
+	if patterns != nil {
+		p.throwError(
+			"[Ln %d:%d] Macro parameters can't be patterns, they're quoted code",
+			expr.Token.Line,
+			expr.Token.Column,
+		)
+		return nil
+	}
+
+	if !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+
+	expr.Body, _ = p.parseFunctionBody()
+	return expr
+}
+
+// parseFunctionBody also reports whether the body yields, ---
+// yields inside nested functions belong to those functions ---
+func (p *Parser) parseFunctionBody() (*ast.BlockStatement, bool) {
+	outerSawYield, noSliceBrace := p.sawYield, p.noSliceBrace
+	p.sawYield, p.noSliceBrace = false, false
+	p.functionDepth++
+
+	body := p.parseBlockStatement()
+	isGenerator := p.sawYield
+
+	p.functionDepth--
+	p.sawYield, p.noSliceBrace = outerSawYield, noSliceBrace
+	return body, isGenerator
+}
+
 func (p *Parser) parseNilLiteral() ast.Expression {
 	return &ast.NilLiteral{Token: p.currentToken}
 }
@@ -184,9 +305,11 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 func (p *Parser) parseAbsoluteExpression() ast.Expression {
 	expr := &ast.AbsoluteExpression{Token: p.currentToken}
 
+	p.bars = append(p.bars, p.depth)
 	p.nextToken() // Advance |
 
 	expr.Value = p.parseExpression(LOWEST)
+	p.bars = p.bars[:len(p.bars)-1]
 
 	if !p.expectPeek(token.PIPE) {
 		return nil
@@ -195,6 +318,58 @@ func (p *Parser) parseAbsoluteExpression() ast.Expression {
 	return expr
 }
 
+func (p *Parser) parseSpawnExpression() ast.Expression {
+	expr := &ast.SpawnExpression{Token: p.currentToken}
+
+	p.nextToken() // Advance spawn
+
+	call, ok := p.parseExpression(UNARY).(*ast.CallExpression)
+	if !ok {
+		p.throwError(
+			"[Ln %d:%d] Expected a function call after 'spawn'",
+			expr.Token.Line,
+			expr.Token.Column,
+		)
+		return nil
+	}
+
+	expr.Call = call
+	return expr
+}
+
+func (p *Parser) parseAwaitExpression() ast.Expression {
+	expr := &ast.AwaitExpression{Token: p.currentToken}
+
+	p.nextToken() // Advance await
+
+	expr.Value = p.parseExpression(UNARY)
+	return expr
+}
+
+func (p *Parser) parseYieldExpression() ast.Expression {
+	expr := &ast.YieldExpression{Token: p.currentToken}
+
+	if p.functionDepth == 0 {
+		p.throwError(
+			"[Ln %d:%d] Cannot use 'yield' outside of a function",
+			expr.Token.Line,
+			expr.Token.Column,
+		)
+		return nil
+	}
+
+	p.sawYield = true
+
+	// Bare yield ---
+	if p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.RIGHT_BRACE) || p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+		return expr
+	}
+
+	p.nextToken() // Advance yield
+	expr.Value = p.parseExpression(ASSIGNMENT)
+	return expr
+}
+
 /*
 * [ INFIX EXPRESSIONS ]
 **/
@@ -262,41 +437,8 @@ func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
 	}
 
 	// Allow both identifiers AND index expressions
-	switch leftExpr := left.(type) {
-	case *ast.Identifier:
-		// Regular variable assignment: a = 10
-		expr := &ast.AssignmentExpression{
-			Token:    p.currentToken,
-			Assignee: leftExpr,
-		}
-		p.nextToken()
-		expr.NewValue = p.parseExpression(ASSIGNMENT + 1)
-
-		if expr.NewValue == nil {
-			p.errors = append(p.errors, fmt.Sprintf(
-				"[Ln %d:%d] Invalid right-hand side in assignment",
-				p.currentToken.Line, p.currentToken.Column))
-			return nil
-		}
-		return expr
-
-	case *ast.IndexExpression:
-		expr := &ast.IndexAssignmentExpression{
-			Token:  p.currentToken,
-			Target: leftExpr.Target, // Take Index Expr's array target
-			Index:  leftExpr.Index,
-		}
-		p.nextToken()
-		expr.NewValue = p.parseExpression(ASSIGNMENT + 1)
-
-		if expr.NewValue == nil {
-			p.errors = append(p.errors, fmt.Sprintf(
-				"[Ln %d:%d] Invalid right-hand side in assignment",
-				p.currentToken.Line, p.currentToken.Column))
-			return nil
-		}
-		return expr
-
+	switch left.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
 	default:
 		p.throwError(
 			"[Ln %d:%d] Cannot reassign to non-identifier/non-index expression '%s'",
@@ -306,6 +448,65 @@ func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
 		)
 		return nil
 	}
+
+	assign := p.currentToken
+	value := p.parseAssignedValue(ASSIGNMENT + 1)
+	if value == nil {
+		return nil
+	}
+
+	if ident, ok := left.(*ast.Identifier); ok {
+		// Regular variable assignment: a = 10
+		return &ast.AssignmentExpression{
+			Token:    assign,
+			Assignee: ident,
+			NewValue: value,
+			Operator: compoundOperator(assign),
+		}
+	}
+
+	index := left.(*ast.IndexExpression)
+	return &ast.IndexAssignmentExpression{
+		Token:    assign,
+		Target:   index.Target, // Take Index Expr's array target
+		Index:    index.Index,
+		NewValue: value,
+		Operator: compoundOperator(assign),
+	}
+}
+
+// parseAssignedValue parses what follows the =, += or ++ at the ---
+// current token. ++ and -- have none, they add or take 1 ---
+func (p *Parser) parseAssignedValue(precedence int) ast.Expression {
+	if ast.IsIncrement(p.currentToken.Type) {
+		one := &ast.IntegerLiteral{Token: p.currentToken, Value: 1}
+		one.Token.Type, one.Token.Literal = token.NUMBER, "1"
+		p.setSpan(one, p.currentToken)
+		return one
+	}
+
+	p.nextToken()
+	value := p.parseExpression(precedence)
+
+	if value == nil {
+		p.errors = append(p.errors, fmt.Sprintf(
+			"[Ln %d:%d] Invalid right-hand side in assignment",
+			p.currentToken.Line, p.currentToken.Column))
+		return nil
+	}
+	return value
+}
+
+// compoundOperator is the operator applied by assign, like the + of ---
+// += or ++. It's empty for a plain = ---
+func compoundOperator(assign token.Token) token.Token {
+	operator, ok := compoundOperators[assign.Type]
+	if !ok {
+		return token.Token{}
+	}
+
+	assign.Type, assign.Literal = operator, string(operator)
+	return assign
 }
 
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
@@ -371,36 +572,52 @@ func (p *Parser) parseIndexSliceExpression(left ast.Expression) ast.Expression {
 * [ HELPERS ]
 **/
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+// parseFunctionParameters also returns each parameter's annotation, ---
+// nil where there isn't one ---
+// parseFunctionParameters also returns the pattern of each parameter, ---
+// nil when none of them is one ---
+func (p *Parser) parseFunctionParameters() ([]*ast.Identifier, []ast.Expression, []*ast.TypeAnnotation) {
 	idents := make([]*ast.Identifier, 0)
+	types := make([]*ast.TypeAnnotation, 0)
+	patterns := make([]ast.Expression, 0)
+	destructures := false
 
 	// Check if no args passed
 	if p.peekTokenIs(token.RIGHT_PARENTHESIS) {
-		p.nextToken() // Eat ( ---
-		return idents // Return empty
+		p.nextToken()             // Eat ( ---
+		return idents, nil, types // Return empty
 	}
 
-	if !p.expectPeek(token.IDENTIFIER) {
-		return nil
+	firstParam, pattern := p.parseParameter()
+	if firstParam == nil {
+		return nil, nil, nil
 	}
-
-	firstParam := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
 	idents = append(idents, firstParam)
+	patterns = append(patterns, pattern)
+	types = append(types, p.parseOptionalType())
+	destructures = pattern != nil
 
 	// Will run every comma, and automatically
 	// jumps to it
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken() // Eat first param
-		if !p.expectPeek(token.IDENTIFIER) {
-			return nil
-		}
 
-		param := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		param, pattern := p.parseParameter()
+		if param == nil {
+			return nil, nil, nil
+		}
 		idents = append(idents, param)
+		patterns = append(patterns, pattern)
+		types = append(types, p.parseOptionalType())
+		destructures = destructures || pattern != nil
+	}
+
+	if !destructures {
+		patterns = nil
 	}
 
 	p.nextToken() // Eat Ident ---
-	return idents
+	return idents, patterns, types
 }
 
 func (p *Parser) parseCallArguments() []ast.Expression {
@@ -11,6 +11,10 @@ const (
 	AND
 	EQUALITY
 	COMPARISON
+	BITWISE_OR
+	BITWISE_XOR
+	BITWISE_AND
+	SHIFT
 	ADDITIVE
 	MULTIPLICATIVE
 	EXPONENTIATION
@@ -20,6 +24,11 @@ const (
 
 var precedence = map[token.TokenType]int{
 	token.ASSIGNMENT:       ASSIGNMENT,
+	token.PLUS_ASSIGN:      ASSIGNMENT,
+	token.MINUS_ASSIGN:     ASSIGNMENT,
+	token.STAR_ASSIGN:      ASSIGNMENT,
+	token.SLASH_ASSIGN:     ASSIGNMENT,
+	token.CARET_ASSIGN:     ASSIGNMENT,
 	token.IF:               TERNARY,
 	token.OR:               OR,
 	token.AND:              AND,
@@ -29,15 +38,50 @@ var precedence = map[token.TokenType]int{
 	token.LESS_EQUAL:       COMPARISON,
 	token.GREATER:          COMPARISON,
 	token.GREATER_EQUAL:    COMPARISON,
+	token.PIPE:             BITWISE_OR,
+	token.XOR:              BITWISE_XOR,
+	token.AMPERSAND:        BITWISE_AND,
+	token.SHIFT_LEFT:       SHIFT,
+	token.SHIFT_RIGHT:      SHIFT,
 	token.PLUS:             ADDITIVE,
 	token.MINUS:            ADDITIVE,
 	token.STAR:             MULTIPLICATIVE,
 	token.SLASH:            MULTIPLICATIVE,
+	token.PERCENT:          MULTIPLICATIVE,
+	token.FLOOR_DIVIDE:     MULTIPLICATIVE,
 	token.CARET:            EXPONENTIATION,
 	token.NOT:              UNARY,
 	token.LEFT_PARENTHESIS: CALL,
 	token.LEFT_BRACKET:     CALL,
 	token.LEFT_BRACE:       CALL,
+	token.INCREMENT:        CALL,
+	token.DECREMENT:        CALL,
+}
+
+// compoundOperators is the operator each compound assignment applies ---
+var compoundOperators = map[token.TokenType]token.TokenType{
+	token.PLUS_ASSIGN:  token.PLUS,
+	token.MINUS_ASSIGN: token.MINUS,
+	token.STAR_ASSIGN:  token.STAR,
+	token.SLASH_ASSIGN: token.SLASH,
+	token.CARET_ASSIGN: token.CARET,
+	token.INCREMENT:    token.PLUS,
+	token.DECREMENT:    token.MINUS,
+}
+
+// isAssignment reports whether tokenType is = or a compound assignment ---
+func isAssignment(tokenType token.TokenType) bool {
+	_, ok := compoundOperators[tokenType]
+	return ok || tokenType == token.ASSIGNMENT
+}
+
+// Precedence is the binding power of an infix operator, LOWEST for other tokens ---
+func Precedence(tokenType token.TokenType) int {
+	if p, ok := precedence[tokenType]; ok {
+		return p
+	}
+
+	return LOWEST
 }
 
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
@@ -50,6 +94,15 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 
 // Right 10(+)12
 func (p *Parser) peekPrecedence() int {
+	if p.noSliceBrace && p.peekToken.Type == token.LEFT_BRACE {
+		return LOWEST
+	}
+
+	// Directly inside |x| a | is the closing bar, not bitwise or ---
+	if p.peekToken.Type == token.PIPE && len(p.bars) != 0 && p.bars[len(p.bars)-1] == p.depth {
+		return LOWEST
+	}
+
 	if p, ok := precedence[p.peekToken.Type]; ok {
 		return p
 	}
@@ -70,6 +123,7 @@ func (p *Parser) createLookupTable() {
 	p.registerPrefix(token.IDENTIFIER, p.parseIdentifier)
 	p.registerPrefix(token.NUMBER, p.parseNumberExpression)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.STRING_HEAD, p.parseInterpolatedString)
 	p.registerPrefix(token.LEFT_BRACE, p.parseHashLiteral)
 
 	// Array
@@ -78,6 +132,7 @@ func (p *Parser) createLookupTable() {
 	p.registerInfix(token.LEFT_BRACE, p.parseIndexSliceExpression)
 
 	p.registerPrefix(token.MINUS, p.parseUnaryExpression)
+	p.registerPrefix(token.TILDE, p.parseUnaryExpression)
 	p.registerPrefix(token.PIPE, p.parseAbsoluteExpression)
 
 	p.registerPrefix(token.NIL, p.parseNilLiteral)
@@ -91,6 +146,14 @@ func (p *Parser) createLookupTable() {
 	p.registerInfix(token.SLASH, p.parseBinaryExpression)
 	p.registerInfix(token.STAR, p.parseBinaryExpression)
 	p.registerInfix(token.CARET, p.parseExponentExpression)
+	p.registerInfix(token.PERCENT, p.parseBinaryExpression)
+	p.registerInfix(token.FLOOR_DIVIDE, p.parseBinaryExpression)
+
+	p.registerInfix(token.AMPERSAND, p.parseBinaryExpression)
+	p.registerInfix(token.PIPE, p.parseBinaryExpression)
+	p.registerInfix(token.XOR, p.parseBinaryExpression)
+	p.registerInfix(token.SHIFT_LEFT, p.parseBinaryExpression)
+	p.registerInfix(token.SHIFT_RIGHT, p.parseBinaryExpression)
 
 	p.registerPrefix(token.NOT, p.parseUnaryExpression)
 	p.registerInfix(token.AND, p.parseBinaryExpression)
@@ -105,6 +168,13 @@ func (p *Parser) createLookupTable() {
 	p.registerPrefix(token.LEFT_PARENTHESIS, p.parseGroupExpression)
 	p.registerInfix(token.IF, p.parseTernaryExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
+	p.registerPrefix(token.SPAWN, p.parseSpawnExpression)
+	p.registerPrefix(token.AWAIT, p.parseAwaitExpression)
+	p.registerPrefix(token.YIELD, p.parseYieldExpression)
 	p.registerInfix(token.LEFT_PARENTHESIS, p.parseCallExpression)
 	p.registerInfix(token.ASSIGNMENT, p.parseAssignmentExpression)
+	for compound := range compoundOperators {
+		p.registerInfix(compound, p.parseAssignmentExpression)
+	}
 }
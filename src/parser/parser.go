@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/caelondev/monkey-compiler-go/src/ast"
 	"github.com/caelondev/monkey-compiler-go/src/lexer"
@@ -23,6 +24,27 @@ type Parser struct {
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	// A function whose own body yields is a generator ---
+	functionDepth int
+	sawYield      bool
+
+	// In `for x in items { ... }` the { opens the body, not a slice ---
+	noSliceBrace bool
+
+	// How many brackets the current token is inside of, and that ---
+	// depth for every |x| being parsed ---
+	depth int
+	bars  []int
+
+	// CST mode, every token read with the trivia before it ---
+	cst    bool
+	tokens []token.Token
+}
+
+// spanned is every node, through its embedded ast.Span ---
+type spanned interface {
+	SetSpan(ast.Span)
 }
 
 func New(l *lexer.Lexer) *Parser {
@@ -31,6 +53,7 @@ func New(l *lexer.Lexer) *Parser {
 		errors:         make([]string, 0),
 		prefixParseFns: make(map[token.TokenType]prefixParseFn),
 		infixParseFns:  make(map[token.TokenType]infixParseFn),
+		cst:            l.KeepsTrivia(),
 	}
 
 	p.createLookupTable()
@@ -42,9 +65,41 @@ func New(l *lexer.Lexer) *Parser {
 	return p
 }
 
+// NewCST parses in CST mode: the program keeps its source and every ---
+// token along with the whitespace and comments between them ---
+func NewCST(l *lexer.Lexer) *Parser {
+	l.KeepTrivia()
+	return New(l)
+}
+
 func (p *Parser) nextToken() {
 	p.currentToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+
+	switch p.currentToken.Type {
+	case token.LEFT_PARENTHESIS, token.LEFT_BRACKET, token.LEFT_BRACE, token.STRING_HEAD:
+		p.depth++
+	case token.RIGHT_PARENTHESIS, token.RIGHT_BRACKET, token.RIGHT_BRACE, token.STRING_TAIL:
+		p.depth--
+	}
+
+	// The lexer's own errors, like a bad escape in a string ---
+	if p.peekToken.Type == token.ERROR {
+		p.throwError("[Ln %d:%d] -> %s", p.peekToken.Line, p.peekToken.Column, p.peekToken.Literal)
+	}
+
+	// The lexer repeats EOF once it runs out ---
+	if p.cst && (len(p.tokens) == 0 || p.tokens[len(p.tokens)-1].Type != token.EOF) {
+		p.tokens = append(p.tokens, p.l.TakeTrivia()...)
+		p.tokens = append(p.tokens, p.peekToken)
+	}
+}
+
+// setSpan spans node from start through the current token ---
+func (p *Parser) setSpan(node ast.Node, start token.Token) {
+	if !ast.IsNil(node) {
+		node.(spanned).SetSpan(ast.SpanOf(start, p.currentToken))
+	}
 }
 
 func (p *Parser) ParseProgram() *ast.Program {
@@ -61,6 +116,22 @@ func (p *Parser) ParseProgram() *ast.Program {
 		p.nextToken()
 	}
 
+	program.Comments = p.l.Comments()
+	program.SetSpan(ast.Span{
+		Start: token.Position{Offset: 0, Line: 1, Column: 1},
+		End:   p.currentToken.End(),
+	})
+
+	if p.cst {
+		// The trivia inside an interpolated string arrives with its head ---
+		sort.SliceStable(p.tokens, func(i, j int) bool {
+			return p.tokens[i].Offset < p.tokens[j].Offset
+		})
+
+		program.Source = p.l.Source()
+		program.Tokens = p.tokens
+	}
+
 	return program
 }
 
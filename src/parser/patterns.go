@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// SYNTAX ---
+//
+// <name>
+// [<element>, <element>, ...<name>]
+// {<name>, <key>: <element>, "<key>": <element>}
+//
+// An element is a pattern, or one with a default after it: <pattern> = <expr>
+
+// parsePattern parses the name or pattern at the current token ---
+func (p *Parser) parsePattern() ast.Expression {
+	start := p.currentToken
+
+	var pattern ast.Expression
+	switch p.currentToken.Type {
+	case token.IDENTIFIER:
+		return p.parseName()
+	case token.LEFT_BRACKET:
+		pattern = p.parseArrayPattern()
+	case token.LEFT_BRACE:
+		pattern = p.parseHashPattern()
+	default:
+		p.throwError(
+			"[Ln %d:%d] Expected a name, [ or { to destructure into, got '%s' instead",
+			p.currentToken.Line,
+			p.currentToken.Column,
+			p.currentToken.Literal,
+		)
+		return nil
+	}
+
+	if pattern == nil {
+		return nil
+	}
+
+	p.setSpan(pattern, start)
+	return pattern
+}
+
+// isPatternStart reports whether the peek token opens an array or ---
+// hash pattern ---
+func (p *Parser) isPatternStart() bool {
+	return p.peekTokenIs(token.LEFT_BRACKET) || p.peekTokenIs(token.LEFT_BRACE)
+}
+
+func (p *Parser) parseArrayPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RIGHT_BRACKET) {
+		p.nextToken() // Eat [ or , ---
+
+		// ...rest takes the others, it has to be last ---
+		if p.currentTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENTIFIER) {
+				return nil
+			}
+			pattern.Rest = p.parseName()
+			break
+		}
+
+		element := p.parsePatternElement()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RIGHT_BRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (p *Parser) parseHashPattern() ast.Expression {
+	pattern := &ast.HashPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.nextToken() // Eat { or , ---
+
+		entry := p.parsePatternEntry()
+		if entry == nil {
+			return nil
+		}
+		pattern.Entries = append(pattern.Entries, entry)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RIGHT_BRACE) {
+		return nil
+	}
+
+	return pattern
+}
+
+// parsePatternEntry parses `key`, `key: <element>` or `"key": <element>` ---
+func (p *Parser) parsePatternEntry() *ast.PatternEntry {
+	start := p.currentToken
+	if !p.currentTokenIs(token.IDENTIFIER) && !p.currentTokenIs(token.STRING) {
+		p.throwError(
+			"[Ln %d:%d] Expected a name or a string as a hash pattern key, got '%s' instead",
+			p.currentToken.Line,
+			p.currentToken.Column,
+			p.currentToken.Literal,
+		)
+		return nil
+	}
+
+	key := &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+	p.setSpan(key, p.currentToken)
+	entry := &ast.PatternEntry{Key: key}
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken() // Eat key ---
+		p.nextToken() // Eat : ---
+		entry.Value = p.parsePatternElement()
+	} else if p.currentTokenIs(token.STRING) {
+		p.throwError(
+			"[Ln %d:%d] Key \"%s\" needs a name to go into, like {\"%s\": name}",
+			p.currentToken.Line,
+			p.currentToken.Column,
+			key.Value,
+			key.Value,
+		)
+		return nil
+	} else {
+		entry.Value = p.parseDefault(p.parseName(), start)
+	}
+
+	if entry.Value == nil {
+		return nil
+	}
+	return entry
+}
+
+// parsePatternElement parses a pattern and the default after it ---
+func (p *Parser) parsePatternElement() ast.Expression {
+	start := p.currentToken
+
+	pattern := p.parsePattern()
+	if pattern == nil {
+		return nil
+	}
+
+	return p.parseDefault(pattern, start)
+}
+
+// parseDefault wraps target in a DefaultPattern when `= <expr>` follows ---
+func (p *Parser) parseDefault(target ast.Expression, start token.Token) ast.Expression {
+	if !p.peekTokenIs(token.ASSIGNMENT) {
+		return target
+	}
+
+	p.nextToken() // Eat target ---
+	pattern := &ast.DefaultPattern{Token: p.currentToken, Target: target}
+	p.nextToken() // Eat = ---
+
+	pattern.Default = p.parseExpression(LOWEST)
+	if pattern.Default == nil {
+		return nil
+	}
+
+	p.setSpan(pattern, start)
+	return pattern
+}
+
+// parseParameter parses the name or pattern at the peek token. A ---
+// pattern's parameter is named after it, which no name can clash with ---
+func (p *Parser) parseParameter() (*ast.Identifier, ast.Expression) {
+	if !p.isPatternStart() {
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil, nil
+		}
+		return p.parseName(), nil
+	}
+
+	p.nextToken()
+	start := p.currentToken
+
+	pattern := p.parsePattern()
+	if pattern == nil {
+		return nil, nil
+	}
+
+	param := &ast.Identifier{Token: start, Value: pattern.String()}
+	param.SetSpan(pattern.NodeSpan())
+	return param, pattern
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+)
+
+func parse(t *testing.T, source string) *ast.Program {
+	t.Helper()
+
+	p := New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	return program
+}
+
+func TestEveryNodeHasASpan(t *testing.T) {
+	tests := []string{
+		`var x = 1 + 2 * 3;`,
+		`fn add(a, b) { return a + b; }`,
+		`if (x > 1) { print(x); } else { print(0); }`,
+		`if (x > 1) { print(x); } else if (x < 0) { print(-x); } else { print(0); }`,
+		`if (x) print(1); else if (y) print(2); else if (z) print(3);`,
+		`for item in [1, 2] { var [a, b] = [item, {"k": item}]; }`,
+	}
+
+	for _, source := range tests {
+		program := parse(t, source)
+
+		ast.Inspect(program, func(node ast.Node) bool {
+			if node != nil && !ast.IsNil(node) && node.NodeSpan().IsZero() {
+				t.Errorf("%q: %T %q has no span", source, node, node.String())
+			}
+			return true
+		})
+	}
+}
+
+func TestNodeAtInsideElseIf(t *testing.T) {
+	source := `if (x > 1) { print(x); } else if (x < 0) { print(target); }`
+	program := parse(t, source)
+
+	offset := strings.Index(source, "target")
+	node := ast.NodeAt(program, offset)
+
+	ident, ok := node.(*ast.Identifier)
+	if !ok || ident.Value != "target" {
+		t.Fatalf("expected the identifier target, got %T %v", node, node)
+	}
+
+	ifStmt := program.Statements[0].(*ast.IfStatement)
+	elseIf, ok := ifStmt.Alternative.(*ast.IfStatement)
+	if !ok {
+		t.Fatalf("expected an else if, got %T", ifStmt.Alternative)
+	}
+
+	span := elseIf.NodeSpan()
+	if source[span.Start.Offset:span.End.Offset] != `if (x < 0) { print(target); }` {
+		t.Errorf("wrong else if span %q", source[span.Start.Offset:span.End.Offset])
+	}
+}
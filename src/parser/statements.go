@@ -6,20 +6,32 @@ import (
 )
 
 func (p *Parser) parseStatement() ast.Statement {
+	var stmt ast.Statement
+	start := p.currentToken
+
 	switch p.currentToken.Type {
 	case token.VAR:
-		return p.parseVarStatement()
+		stmt = p.parseVarStatement()
 	case token.RETURN:
-		return p.parseReturnStatement()
+		stmt = p.parseReturnStatement()
 	case token.IF:
-		return p.parseIfStatements()
+		stmt = p.parseIfStatements()
 	case token.ASSIGN:
-		return p.parseBatchAssignStatement()
+		stmt = p.parseBatchAssignStatement()
 	case token.FUNCTION:
-		return p.parseFunctionStatement()
+		stmt = p.parseFunctionStatement()
+	case token.FOR:
+		stmt = p.parseForInStatement()
+	case token.TRY:
+		stmt = p.parseTryStatement()
+	case token.THROW:
+		stmt = p.parseThrowStatement()
 	default:
-		return p.parseExpressionStatement()
+		stmt = p.parseExpressionStatement()
 	}
+
+	p.setSpan(stmt, start)
+	return stmt
 }
 
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
@@ -45,33 +57,45 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 	// var <Identifier> = <expr>;
 	// var <Identifier>, <Identifier> = <expr>;
 	//
+	// Annotated, for all names at once
+	// var <Identifier>: <type> = <expr>;
+	//
+	// Destructured, see parsePattern
+	// var [<Identifier>, <Identifier>] = <expr>;
+	// var {<Identifier>, <Identifier>} = <expr>;
+	//
 
 	stmt := &ast.VarStatement{Token: p.currentToken}
 
-	if !p.expectPeek(token.IDENTIFIER) {
-		return nil
-	}
+	if p.isPatternStart() {
+		p.nextToken() // Eat var ---
+		if stmt.Pattern = p.parsePattern(); stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
 
-	// First var
-	stmt.Names = append(stmt.Names, &ast.Identifier{
-		Token: p.currentToken,
-		Value: p.currentToken.Literal,
-	})
+		// First var
+		stmt.Names = append(stmt.Names, p.parseName())
 
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken() // Eat Identifier
-		p.nextToken() // Eat comma
+		for p.peekTokenIs(token.COMMA) {
+			p.nextToken() // Eat Identifier
+			p.nextToken() // Eat comma
 
-		stmt.Names = append(stmt.Names, &ast.Identifier{
-			Token: p.currentToken,
-			Value: p.currentToken.Literal,
-		})
-	}
+			stmt.Names = append(stmt.Names, p.parseName())
+		}
 
-	if p.peekTokenIs(token.SEMICOLON) {
-		p.nextToken() // Eat last var name
-		stmt.Value = &ast.NilLiteral{Token: p.currentToken}
-		return stmt
+		stmt.Type = p.parseOptionalType()
+
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken() // Eat last var name
+			nilValue := &ast.NilLiteral{Token: p.currentToken}
+			nilValue.SetSpan(ast.Span{Start: p.currentToken.Start(), End: p.currentToken.Start()}) // Empty, it's implicit ---
+			stmt.Value = nilValue
+			return stmt
+		}
 	}
 
 	if !p.expectPeek(token.ASSIGNMENT) {
@@ -124,6 +148,7 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		p.nextToken() // Advance next statement
 	}
 
+	p.setSpan(block, block.Token)
 	return block
 }
 
@@ -162,8 +187,7 @@ func (p *Parser) parseIfStatements() *ast.IfStatement {
 			p.nextToken() // nextToken: advance to ELSE (eat whatever is after stmt)
 			// check for else-if (hybrid support)
 			if p.peekTokenIs(token.IF) {
-				p.nextToken() // nextToken: advance to IF
-				stmt.Alternative = p.parseIfStatements()
+				stmt.Alternative = p.parseElseIf()
 				return stmt
 			}
 
@@ -187,8 +211,7 @@ func (p *Parser) parseIfStatements() *ast.IfStatement {
 		p.nextToken() // nextToken: advance to ELSE (eat whatever is after block)
 		// else-if after a block
 		if p.peekTokenIs(token.IF) {
-			p.nextToken() // nextToken: advance to IF
-			stmt.Alternative = p.parseIfStatements()
+			stmt.Alternative = p.parseElseIf()
 			return stmt
 		}
 
@@ -205,34 +228,62 @@ func (p *Parser) parseIfStatements() *ast.IfStatement {
 	return stmt
 }
 
-func (p *Parser) parseBatchAssignStatement() *ast.BatchAssignmentStatement {
-	stmt := &ast.BatchAssignmentStatement{Token: p.currentToken}
-
-	if !p.expectPeek(token.IDENTIFIER) {
-		return nil
-	}
+// parseElseIf parses the if after an else (peek = IF), it doesn't go ---
+// through parseStatement so it's spanned here ---
+func (p *Parser) parseElseIf() *ast.IfStatement {
+	p.nextToken() // nextToken: advance to IF
+	start := p.currentToken
 
-	firstAssignee := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-	stmt.Assignees = append(stmt.Assignees, firstAssignee)
+	stmt := p.parseIfStatements()
+	p.setSpan(stmt, start)
+	return stmt
+}
 
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken() // Eat first assignee
+func (p *Parser) parseBatchAssignStatement() *ast.BatchAssignmentStatement {
+	stmt := &ast.BatchAssignmentStatement{Token: p.currentToken}
 
+	// assign [a, b] = <expr>; only takes a plain = ---
+	if p.isPatternStart() {
+		p.nextToken() // Eat assign ---
+		if stmt.Pattern = p.parsePattern(); stmt.Pattern == nil {
+			return nil
+		}
+		if !p.peekTokenIs(token.ASSIGNMENT) {
+			p.peekError(token.ASSIGNMENT)
+			return nil
+		}
+	} else {
 		if !p.expectPeek(token.IDENTIFIER) {
 			return nil
 		}
 
-		assignee := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-		stmt.Assignees = append(stmt.Assignees, assignee)
+		firstAssignee := p.parseName()
+		stmt.Assignees = append(stmt.Assignees, firstAssignee)
+
+		for p.peekTokenIs(token.COMMA) {
+			p.nextToken() // Eat first assignee
+
+			if !p.expectPeek(token.IDENTIFIER) {
+				return nil
+			}
+
+			assignee := p.parseName()
+			stmt.Assignees = append(stmt.Assignees, assignee)
+		}
 	}
 
-	if !p.expectPeek(token.ASSIGNMENT) {
+	if !isAssignment(p.peekToken.Type) {
+		p.peekError(token.ASSIGNMENT)
 		return nil
 	}
 
-	p.nextToken() // Advance past ASSIGNMENT TOKEN
+	p.nextToken() // Advance to the =, += or ++
+	stmt.Assign, stmt.Operator = p.currentToken, compoundOperator(p.currentToken)
 
-	stmt.NewValue = p.parseExpression(LOWEST)
+	stmt.NewValue = p.parseAssignedValue(LOWEST)
+	if stmt.NewValue == nil {
+		return nil
+	}
 
 	if !p.expectPeek(token.SEMICOLON) {
 		return nil
@@ -248,19 +299,134 @@ func (p *Parser) parseFunctionStatement() *ast.FunctionDeclarationStatement {
 		return nil
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	stmt.Name = p.parseName()
 
 	if !p.expectPeek(token.LEFT_PARENTHESIS) {
 		return nil
 	}
 
-	stmt.Parameters = p.parseFunctionParameters()
+	stmt.Parameters, stmt.Patterns, stmt.ParameterTypes = p.parseFunctionParameters()
+	stmt.ReturnType = p.parseOptionalType()
+
+	if !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+
+	stmt.Body, stmt.IsGenerator = p.parseFunctionBody()
+
+	return stmt
+}
+
+func (p *Parser) parseForInStatement() *ast.ForInStatement {
+	// Syntax ---
+	//
+	// for <Identifier> in <expr> { ... }
+	// for (<Identifier> in <expr>) { ... }
+	//
+	stmt := &ast.ForInStatement{Token: p.currentToken}
+
+	parenthesized := p.peekTokenIs(token.LEFT_PARENTHESIS)
+	if parenthesized {
+		p.nextToken() // Eat (
+	}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Variable = p.parseName()
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken() // Eat in
+	p.noSliceBrace = !parenthesized
+	stmt.Iterable = p.parseExpression(LOWEST)
+	p.noSliceBrace = false
+
+	if parenthesized && !p.expectPeek(token.RIGHT_PARENTHESIS) {
+		return nil
+	}
 
 	if !p.expectPeek(token.LEFT_BRACE) {
 		return nil
 	}
 
 	stmt.Body = p.parseBlockStatement()
+	return stmt
+}
+
+func (p *Parser) parseTryStatement() *ast.TryStatement {
+	// Syntax ---
+	//
+	// try { ... } catch (e) { ... }
+	// try { ... } catch { ... }
+	// try { ... } finally { ... }
+	// try { ... } catch (e) { ... } finally { ... }
+	//
+	stmt := &ast.TryStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+
+	stmt.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken() // Advance to catch
+
+		if p.peekTokenIs(token.LEFT_PARENTHESIS) {
+			p.nextToken() // Eat (
+
+			if !p.expectPeek(token.IDENTIFIER) {
+				return nil
+			}
+			stmt.CatchParam = p.parseName()
+
+			if !p.expectPeek(token.RIGHT_PARENTHESIS) {
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.LEFT_BRACE) {
+			return nil
+		}
+
+		stmt.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken() // Advance to finally
+
+		if !p.expectPeek(token.LEFT_BRACE) {
+			return nil
+		}
+
+		stmt.Finally = p.parseBlockStatement()
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil {
+		p.throwError(
+			"[Ln %d:%d] Expected 'catch' or 'finally' after try block",
+			stmt.Token.Line,
+			stmt.Token.Column,
+		)
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.currentToken}
+
+	p.nextToken() // Advance throw
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
 
 	return stmt
 }
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"github.com/caelondev/monkey-compiler-go/src/ast"
+	"github.com/caelondev/monkey-compiler-go/src/token"
+)
+
+// TypeNames are the names a type annotation may use ---
+var TypeNames = []string{"number", "integer", "bigint", "decimal", "string", "boolean", "nil", "function", "array", "hash", "any"}
+
+// parseOptionalType parses a `: <type>` following the current token, ---
+// returning nil when there isn't one ---
+func (p *Parser) parseOptionalType() *ast.TypeAnnotation {
+	if !p.peekTokenIs(token.COLON) {
+		return nil
+	}
+
+	p.nextToken() // Eat the annotated token ---
+	p.nextToken() // Eat : ---
+	return p.parseTypeAnnotation()
+}
+
+func (p *Parser) parseTypeAnnotation() *ast.TypeAnnotation {
+	// SYNTAX ---
+	//
+	// <name>
+	// [<type>]
+	// {<type>: <type>}
+	annotation := &ast.TypeAnnotation{Token: p.currentToken}
+
+	switch p.currentToken.Type {
+	case token.LEFT_BRACKET:
+		annotation.Name = "array"
+		p.nextToken() // Eat [ ---
+
+		if annotation.Element = p.parseTypeAnnotation(); annotation.Element == nil {
+			return nil
+		}
+		if !p.expectPeek(token.RIGHT_BRACKET) {
+			return nil
+		}
+
+	case token.LEFT_BRACE:
+		annotation.Name = "hash"
+		p.nextToken() // Eat { ---
+
+		if annotation.Key = p.parseTypeAnnotation(); annotation.Key == nil {
+			return nil
+		}
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.nextToken() // Eat : ---
+
+		if annotation.Element = p.parseTypeAnnotation(); annotation.Element == nil {
+			return nil
+		}
+		if !p.expectPeek(token.RIGHT_BRACE) {
+			return nil
+		}
+
+	case token.IDENTIFIER, token.NIL:
+		annotation.Name = p.currentToken.Literal
+		if !isTypeName(annotation.Name) {
+			p.throwError(
+				"[Ln %d:%d] -> Unknown type '%s'",
+				p.currentToken.Line,
+				p.currentToken.Column,
+				annotation.Name,
+			)
+			return nil
+		}
+
+	default:
+		p.throwError(
+			"[Ln %d:%d] -> Expected a type, got '%s' instead",
+			p.currentToken.Line,
+			p.currentToken.Column,
+			p.currentToken.Literal,
+		)
+		return nil
+	}
+
+	p.setSpan(annotation, annotation.Token)
+	return annotation
+}
+
+func isTypeName(name string) bool {
+	for _, known := range TypeNames {
+		if known == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/evaluation"
 	"github.com/caelondev/monkey-compiler-go/src/lexer"
 	"github.com/caelondev/monkey-compiler-go/src/object"
 	"github.com/caelondev/monkey-compiler-go/src/parser"
@@ -21,6 +22,10 @@ func Start(in io.Reader, out io.Writer) {
 	globals := make([]object.Object, vm.GLOBAL_SIZE)
 	symbolTable := compiler.NewSymbolTable()
 
+	// Macros defined on one line expand on the later ones ---
+	expander := evaluation.New()
+	macros := object.NewEnvironment(nil)
+
 	for {
 		fmt.Printf(">> ")
 		scanned := scanner.Scan()
@@ -40,6 +45,18 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
+		expander.DefineMacros(program, macros)
+		program, expandErr := expander.ExpandMacros(program, macros)
+		if expandErr != nil {
+			fmt.Fprintf(out, "Macro::Error: %s\n", expandErr.Message)
+			continue
+		}
+
+		// The line only defined macros ---
+		if len(program.Statements) == 0 {
+			continue
+		}
+
 		comp := compiler.NewWithState(symbolTable, constants)
 		err := comp.Compile(program)
 		if err != nil {
@@ -49,6 +66,9 @@ func Start(in io.Reader, out io.Writer) {
 
 		comp.Disassemble()
 
+		// Functions from earlier lines index into the same constant pool ---
+		constants = comp.Bytecode().Constants
+
 		vm := vm.NewWithGlobalStore(comp.Bytecode(), globals)
 		err = vm.Run()
 		if err != nil {
@@ -1,6 +1,7 @@
 package run
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,8 +15,6 @@ import (
 	"github.com/caelondev/monkey-compiler-go/src/vm"
 )
 
-var ENVIRONMENT *object.Environment = object.NewEnvironment(nil)
-
 func RunFile(filepath string) {
 	byte, err := os.ReadFile(filepath)
 	if err != nil {
@@ -30,15 +29,20 @@ func RunFile(filepath string) {
 	}
 
 	source := string(byte)
-	result := RunSource(source, os.Stdout)
+	result := RunSourceNamed(filepath, source, os.Stdout)
 
-	if result != nil && result.Type() == object.ERROR_OBJECT {
-		fmt.Printf("%v", result)
+	if errObj, ok := result.(*object.Error); ok {
+		fmt.Print(FormatError(errObj))
 		// formatFileError(result.(*object.Error), source, os.Stdout)
 	}
 }
 
 func RunSource(source string, out io.Writer) object.Object {
+	return RunSourceNamed("", source, out)
+}
+
+// RunSourceNamed is RunSource with name recorded in stack traces ---
+func RunSourceNamed(name, source string, out io.Writer) object.Object {
 	l := lexer.New(source)
 	p := parser.New(l)
 	program := p.ParseProgram()
@@ -50,9 +54,20 @@ func RunSource(source string, out io.Writer) object.Object {
 		return nil
 	}
 
+	// Every call gets its own evaluator and globals, ---
+	// so RunSource is safe to call from several goroutines ---
 	evaluator := evaluation.New()
+	evaluator.Stdout = out
+	evaluator.File = name
+
+	macros := object.NewEnvironment(nil)
+	evaluator.DefineMacros(program, macros)
+	program, expandErr := evaluator.ExpandMacros(program, macros)
+	if expandErr != nil {
+		return expandErr
+	}
 
-	result := evaluator.Evaluate(program, ENVIRONMENT)
+	result := evaluator.Evaluate(program, object.NewEnvironment(nil))
 
 	return result
 }
@@ -68,7 +83,8 @@ func RunBytecode(path string) {
 	duration := time.Since(start)
 
 	if err != nil {
-		panic(err)
+		fmt.Print(FormatVMError(err))
+		os.Exit(1)
 	}
 
 	// DEBUGS
@@ -82,6 +98,20 @@ func RunBytecode(path string) {
 	}
 }
 
+// FormatError renders a runtime error followed by its stack trace ---
+func FormatError(err *object.Error) string {
+	return err.Inspect() + "\n" + err.StackTrace()
+}
+
+func FormatVMError(err error) string {
+	var thrown *vm.ThrownError
+	if errors.As(err, &thrown) {
+		return FormatError(thrown.Err)
+	}
+
+	return err.Error() + "\n"
+}
+
 // func formatFileError(err *object.Error, source string, out io.Writer) {
 // 	lines := strings.Split(source, "\n")
 //
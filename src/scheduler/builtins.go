@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// Builtins returns the channel functions bound to this scheduler.
+func (s *Scheduler) Builtins() map[string]object.HostFunctionFn {
+	return map[string]object.HostFunctionFn{
+		"channel": s.builtinChannel,
+		"send":    s.builtinSend,
+		"recv":    s.builtinRecv,
+		"close":   s.builtinClose,
+		"select":  s.builtinSelect,
+	}
+}
+
+// AwaitValue awaits a task, or every task of an array (in order).
+func (s *Scheduler) AwaitValue(value object.Object) (object.Object, error) {
+	switch value := value.(type) {
+	case *Task:
+		return s.Await(value)
+	case *object.Array:
+		results := make([]object.Object, len(value.Elements))
+		for i, element := range value.Elements {
+			task, ok := element.(*Task)
+			if !ok {
+				return nil, fmt.Errorf("Cannot await type '%s'", element.Type())
+			}
+
+			result, err := s.Await(task)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+		}
+		return &object.Array{Elements: results}, nil
+
+	default:
+		return nil, fmt.Errorf("Cannot await type '%s'", value.Type())
+	}
+}
+
+func (s *Scheduler) builtinChannel(args ...object.Object) (object.Object, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("Expected at most 1 argument, got %d", len(args))
+	}
+
+	capacity := 0
+	if len(args) == 1 {
+		number, ok := object.ToFloat(args[0])
+		if !ok || number < 0 || number != float64(int(number)) {
+			return nil, fmt.Errorf("Channel capacity must be a non-negative whole number, got %s", args[0].Inspect())
+		}
+		capacity = int(number)
+	}
+
+	return s.NewChannel(capacity), nil
+}
+
+func (s *Scheduler) builtinSend(args ...object.Object) (object.Object, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("Expected 2 arguments, got %d", len(args))
+	}
+
+	ch, err := toChannel(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NIL, s.Send(ch, args[1])
+}
+
+func (s *Scheduler) builtinRecv(args ...object.Object) (object.Object, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Expected 1 argument, got %d", len(args))
+	}
+
+	ch, err := toChannel(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	value, _, err := s.Receive(ch)
+	return value, err
+}
+
+func (s *Scheduler) builtinClose(args ...object.Object) (object.Object, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Expected 1 argument, got %d", len(args))
+	}
+
+	ch, err := toChannel(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NIL, s.Close(ch)
+}
+
+// select(a, b, ...) returns [index, value, ok] ---
+func (s *Scheduler) builtinSelect(args ...object.Object) (object.Object, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Expected at least 1 channel")
+	}
+
+	channels := make([]*Channel, len(args))
+	for i, arg := range args {
+		ch, err := toChannel(arg)
+		if err != nil {
+			return nil, err
+		}
+		channels[i] = ch
+	}
+
+	index, value, ok, err := s.Select(channels)
+	if err != nil {
+		return nil, err
+	}
+
+	okObj := object.FALSE
+	if ok {
+		okObj = object.TRUE
+	}
+
+	return &object.Array{Elements: []object.Object{&object.Integer{Value: int64(index)}, value, okObj}}, nil
+}
+
+func toChannel(obj object.Object) (*Channel, error) {
+	ch, ok := obj.(*Channel)
+	if !ok {
+		return nil, fmt.Errorf("Expected a channel, got '%s'", obj.Type())
+	}
+
+	return ch, nil
+}
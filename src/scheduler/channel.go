@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// Channel follows Go's semantics: unbuffered channels hand values ---
+// directly from sender to receiver, receiving from a closed and ---
+// drained channel gives nil, sending to a closed one is an error ---
+type Channel struct {
+	ID       int
+	Capacity int
+
+	buffer    []object.Object
+	closed    bool
+	receivers []*receiver
+	senders   []*sender
+}
+
+type receiver struct {
+	task  *Task
+	index int // Position in the select() call ---
+}
+
+type sender struct {
+	task  *Task
+	value object.Object
+}
+
+func (c *Channel) Type() object.ObjectType {
+	return object.CHANNEL_OBJECT
+}
+
+func (c *Channel) Inspect() string {
+	return fmt.Sprintf("[ Channel #%d ]", c.ID)
+}
+
+func (s *Scheduler) NewChannel(capacity int) *Channel {
+	ch := &Channel{ID: s.nextChannelID, Capacity: capacity}
+	s.nextChannelID++
+	return ch
+}
+
+func (s *Scheduler) Send(ch *Channel, value object.Object) error {
+	if ch.closed {
+		return fmt.Errorf("Cannot send to closed channel #%d", ch.ID)
+	}
+
+	if r := ch.nextReceiver(); r != nil {
+		s.deliver(r, value, true)
+		return nil
+	}
+
+	if len(ch.buffer) < ch.Capacity {
+		ch.buffer = append(ch.buffer, value)
+		return nil
+	}
+
+	task := s.current
+	task.sendErr = nil
+	ch.senders = append(ch.senders, &sender{task: task, value: value})
+
+	err := s.park(fmt.Sprintf("sending to channel #%d", ch.ID))
+	if err != nil {
+		ch.removeSender(task)
+		return err
+	}
+
+	return task.sendErr
+}
+
+// Receive returns the next value, ok is false once ch is closed and drained.
+func (s *Scheduler) Receive(ch *Channel) (object.Object, bool, error) {
+	if value, ok, ready := s.tryReceive(ch); ready {
+		return value, ok, nil
+	}
+
+	_, value, ok, err := s.waitReceive([]*Channel{ch}, fmt.Sprintf("receiving from channel #%d", ch.ID))
+	return value, ok, err
+}
+
+// Select receives from whichever channel is ready first, earlier ---
+// channels win ties so the choice is deterministic ---
+func (s *Scheduler) Select(channels []*Channel) (int, object.Object, bool, error) {
+	for i, ch := range channels {
+		if value, ok, ready := s.tryReceive(ch); ready {
+			return i, value, ok, nil
+		}
+	}
+
+	ids := make([]any, len(channels))
+	for i, ch := range channels {
+		ids[i] = fmt.Sprintf("#%d", ch.ID)
+	}
+
+	return s.waitReceive(channels, fmt.Sprintf("selecting on channels %v", ids))
+}
+
+func (s *Scheduler) Close(ch *Channel) error {
+	if ch.closed {
+		return fmt.Errorf("Channel #%d is already closed", ch.ID)
+	}
+
+	ch.closed = true
+
+	for r := ch.nextReceiver(); r != nil; r = ch.nextReceiver() {
+		s.deliver(r, object.NIL, false)
+	}
+
+	for _, snd := range ch.senders {
+		snd.task.sendErr = fmt.Errorf("Cannot send to closed channel #%d", ch.ID)
+		s.ready(snd.task)
+	}
+	ch.senders = nil
+
+	return nil
+}
+
+func (s *Scheduler) tryReceive(ch *Channel) (object.Object, bool, bool) {
+	if len(ch.buffer) > 0 {
+		value := ch.buffer[0]
+		ch.buffer = ch.buffer[1:]
+
+		// A blocked sender can now fill the freed slot ---
+		if snd := ch.nextSender(); snd != nil {
+			ch.buffer = append(ch.buffer, snd.value)
+			s.ready(snd.task)
+		}
+
+		return value, true, true
+	}
+
+	if snd := ch.nextSender(); snd != nil {
+		s.ready(snd.task)
+		return snd.value, true, true
+	}
+
+	if ch.closed {
+		return object.NIL, false, true
+	}
+
+	return nil, false, false
+}
+
+func (s *Scheduler) waitReceive(channels []*Channel, reason string) (int, object.Object, bool, error) {
+	task := s.current
+	for i, ch := range channels {
+		ch.receivers = append(ch.receivers, &receiver{task: task, index: i})
+	}
+
+	err := s.park(reason)
+
+	for _, ch := range channels {
+		ch.removeReceiver(task)
+	}
+
+	if err != nil {
+		return 0, nil, false, err
+	}
+
+	return task.selected, task.received, task.receivedOk, nil
+}
+
+func (s *Scheduler) deliver(r *receiver, value object.Object, ok bool) {
+	r.task.received = value
+	r.task.receivedOk = ok
+	r.task.selected = r.index
+	s.ready(r.task)
+}
+
+// Skips receivers that were already woken through another channel of a select ---
+func (c *Channel) nextReceiver() *receiver {
+	for len(c.receivers) > 0 {
+		r := c.receivers[0]
+		c.receivers = c.receivers[1:]
+
+		if r.task.waiting {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (c *Channel) removeReceiver(task *Task) {
+	kept := c.receivers[:0]
+	for _, r := range c.receivers {
+		if r.task != task {
+			kept = append(kept, r)
+		}
+	}
+
+	c.receivers = kept
+}
+
+func (c *Channel) nextSender() *sender {
+	for len(c.senders) > 0 {
+		snd := c.senders[0]
+		c.senders = c.senders[1:]
+
+		if snd.task.waiting {
+			return snd
+		}
+	}
+
+	return nil
+}
+
+func (c *Channel) removeSender(task *Task) {
+	kept := c.senders[:0]
+	for _, snd := range c.senders {
+		if snd.task != task {
+			kept = append(kept, snd)
+		}
+	}
+
+	c.senders = kept
+}
@@ -0,0 +1,206 @@
+// Package scheduler runs script tasks (spawn) as cooperative green threads.
+//
+// Every task gets its own goroutine, but only the task holding the run token
+// executes; the others wait on their resume channel. Tasks only switch when
+// the running one blocks (channel operations, await) or finishes, and ready
+// tasks run in FIFO order, so a program always interleaves the same way.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// ErrAborted is returned to tasks that are still alive when the program ends.
+var ErrAborted = errors.New("Task was aborted, the program has finished")
+
+type Scheduler struct {
+	main     *Task
+	current  *Task
+	tasks    []*Task // Spawned tasks that haven't finished ---
+	runQueue []*Task
+
+	nextTaskID    int
+	nextChannelID int
+	closed        bool
+}
+
+func New() *Scheduler {
+	main := newTask(0, "main")
+	return &Scheduler{main: main, current: main, nextTaskID: 1, nextChannelID: 1}
+}
+
+// Spawn queues run as a new task. It starts once the current task blocks.
+func (s *Scheduler) Spawn(name string, run func() (object.Object, error)) *Task {
+	task := newTask(s.nextTaskID, name)
+	s.nextTaskID++
+
+	if s.closed {
+		task.done = true
+		task.err = ErrAborted
+		return task
+	}
+
+	s.tasks = append(s.tasks, task)
+	s.runQueue = append(s.runQueue, task)
+
+	go func() {
+		defer close(task.exited)
+
+		if err := <-task.resume; err != nil {
+			return
+		}
+
+		result, err := safeRun(run)
+		if s.closed {
+			return
+		}
+
+		s.finish(task, result, err)
+	}()
+
+	return task
+}
+
+func safeRun(run func() (object.Object, error)) (result object.Object, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+
+	return run()
+}
+
+// Await blocks the current task until task has finished.
+func (s *Scheduler) Await(task *Task) (object.Object, error) {
+	if task == s.current {
+		return nil, fmt.Errorf("Task #%d cannot await itself", task.ID)
+	}
+
+	if !task.done {
+		task.waiters = append(task.waiters, s.current)
+		err := s.park(fmt.Sprintf("awaiting task #%d", task.ID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if task.result == nil && task.err == nil {
+		return object.NIL, nil
+	}
+
+	return task.result, task.err
+}
+
+// Shutdown aborts every task that is still alive, waiting for their ---
+// goroutines to unwind, then resets the scheduler for the next program ---
+func (s *Scheduler) Shutdown() {
+	if s.current != s.main {
+		return
+	}
+
+	s.closed = true
+	for _, task := range s.tasks {
+		task.waiting = false
+		task.resume <- ErrAborted
+		<-task.exited
+	}
+
+	s.tasks = nil
+	s.runQueue = nil
+	s.closed = false
+}
+
+// park suspends the current task until another task makes it ready again. ---
+// If nothing else can run, every task is blocked and main gets the deadlock ---
+func (s *Scheduler) park(reason string) error {
+	if s.closed {
+		return ErrAborted
+	}
+
+	task := s.current
+	task.blockedOn = reason
+	task.waiting = true
+
+	next := s.dequeue()
+	if next == nil {
+		deadlock := s.deadlock()
+		if task == s.main {
+			task.waiting = false
+			task.blockedOn = ""
+			return deadlock
+		}
+
+		s.switchTo(s.main, deadlock)
+	} else {
+		s.switchTo(next, nil)
+	}
+
+	return <-task.resume
+}
+
+func (s *Scheduler) finish(task *Task, result object.Object, err error) {
+	task.done = true
+	task.result, task.err = result, err
+
+	for _, waiter := range task.waiters {
+		s.ready(waiter)
+	}
+	task.waiters = nil
+
+	for i, t := range s.tasks {
+		if t == task {
+			s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
+			break
+		}
+	}
+
+	next := s.dequeue()
+	if next == nil {
+		// Main can't be done while tasks still run, so it's blocked ---
+		s.switchTo(s.main, s.deadlock())
+		return
+	}
+
+	s.switchTo(next, nil)
+}
+
+func (s *Scheduler) ready(task *Task) {
+	if !task.waiting {
+		return
+	}
+
+	task.waiting = false
+	task.blockedOn = ""
+	s.runQueue = append(s.runQueue, task)
+}
+
+func (s *Scheduler) dequeue() *Task {
+	if len(s.runQueue) == 0 {
+		return nil
+	}
+
+	next := s.runQueue[0]
+	s.runQueue = s.runQueue[1:]
+	return next
+}
+
+func (s *Scheduler) switchTo(task *Task, err error) {
+	s.current = task
+	task.resume <- err
+}
+
+func (s *Scheduler) deadlock() error {
+	blocked := make([]string, 0, len(s.tasks)+1)
+	for _, task := range append([]*Task{s.main}, s.tasks...) {
+		if task.waiting {
+			blocked = append(blocked, fmt.Sprintf("%s (%s)", task.describe(), task.blockedOn))
+		}
+	}
+
+	return fmt.Errorf("Deadlock, every task is blocked: %s", strings.Join(blocked, ", "))
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+type Task struct {
+	ID   int
+	Name string
+
+	resume chan error
+	exited chan struct{}
+
+	done    bool
+	result  object.Object
+	err     error
+	waiters []*Task
+
+	waiting   bool
+	blockedOn string
+
+	// Set by whoever wakes a receiving/selecting task ---
+	received   object.Object
+	receivedOk bool
+	selected   int
+	sendErr    error
+}
+
+func newTask(id int, name string) *Task {
+	return &Task{
+		ID:     id,
+		Name:   name,
+		resume: make(chan error, 1),
+		exited: make(chan struct{}),
+	}
+}
+
+func (t *Task) Type() object.ObjectType {
+	return object.TASK_OBJECT
+}
+
+func (t *Task) Inspect() string {
+	return "[ " + t.describe() + " ]"
+}
+
+func (t *Task) Done() bool {
+	return t.done
+}
+
+func (t *Task) describe() string {
+	if t.ID == 0 {
+		return "Task main"
+	}
+
+	if t.Name == "" {
+		return fmt.Sprintf("Task #%d", t.ID)
+	}
+
+	return fmt.Sprintf("Task #%d '%s'", t.ID, t.Name)
+}
@@ -1,5 +1,7 @@
 package token
 
+import "sort"
+
 type TokenType string
 
 type Token struct {
@@ -7,6 +9,28 @@ type Token struct {
 	Literal string
 	Line    uint
 	Column  uint
+
+	// Byte offsets into the source, EndOffset and the end line and ---
+	// column are just past the token's last byte ---
+	Offset    int
+	EndOffset int
+	EndLine   uint
+	EndColumn uint
+}
+
+// Position is a place in the source, Offset counts bytes ---
+type Position struct {
+	Offset int
+	Line   uint
+	Column uint
+}
+
+func (t Token) Start() Position {
+	return Position{Offset: t.Offset, Line: t.Line, Column: t.Column}
+}
+
+func (t Token) End() Position {
+	return Position{Offset: t.EndOffset, Line: t.EndLine, Column: t.EndColumn}
 }
 
 const (
@@ -19,6 +43,13 @@ const (
 	NUMBER     = "NUMBER"
 	STRING     = "STRING"
 
+	// An interpolated string is split around its ${...} parts, ---
+	// "a ${x} b ${y} c" lexes as STRING_HEAD `"a ${`, x, ---
+	// STRING_MIDDLE `} b ${`, y, STRING_TAIL `} c"` ---
+	STRING_HEAD   = "STRING_HEAD"
+	STRING_MIDDLE = "STRING_MIDDLE"
+	STRING_TAIL   = "STRING_TAIL"
+
 	// Operators
 	ASSIGNMENT = "="
 	PLUS       = "+"
@@ -27,8 +58,10 @@ const (
 	STAR       = "*"
 	SLASH      = "/"
 	CARET      = "^"
-	PIPE       = "|"
-	TILDE      = "~"
+	PIPE       = "|" // |x| outside of an expression, bitwise or inside one ---
+	TILDE      = "~" // Slicing, and bitwise not in front of a value ---
+	PERCENT    = "%"
+	AMPERSAND  = "&"
 
 	LESS          = "<"
 	GREATER       = ">"
@@ -37,6 +70,7 @@ const (
 
 	// Delimiters
 	COMMA     = ","
+	ELLIPSIS  = "..." // The rest of an array pattern ---
 	COLON     = ":"
 	SEMICOLON = ";"
 
@@ -48,11 +82,24 @@ const (
 	RIGHT_BRACKET     = "]"
 
 	// Two chars
-	EQUAL     = "=="
-	NOT_EQUAL = "!="
+	EQUAL        = "=="
+	NOT_EQUAL    = "!="
+	SHIFT_LEFT   = "<<"
+	SHIFT_RIGHT  = ">>"
+	FLOOR_DIVIDE = "~/"
+
+	// Compound assignment, x op= v is x = x op v ---
+	PLUS_ASSIGN  = "+="
+	MINUS_ASSIGN = "-="
+	STAR_ASSIGN  = "*="
+	SLASH_ASSIGN = "/="
+	CARET_ASSIGN = "^="
+	INCREMENT    = "++"
+	DECREMENT    = "--" // Only before ; ) ] } , a line end, a comment or the end, see lexer.endsOperand ---
 
 	// Reserved keywords
 	FUNCTION = "FUNCTION"
+	MACRO    = "MACRO"
 	VAR      = "VAR"
 	ASSIGN   = "ASSIGN"
 
@@ -67,6 +114,23 @@ const (
 	AND = "AND"
 	OR  = "OR"
 	NOT = "NOT"
+	XOR = "XOR"
+
+	SPAWN = "SPAWN"
+	AWAIT = "AWAIT"
+
+	FOR   = "FOR"
+	IN    = "IN"
+	YIELD = "YIELD"
+
+	TRY     = "TRY"
+	CATCH   = "CATCH"
+	FINALLY = "FINALLY"
+	THROW   = "THROW"
+
+	// Trivia, NextToken skips over these ---
+	COMMENT    = "COMMENT"
+	WHITESPACE = "WHITESPACE"
 
 	NIL          = "NIL"
 	INFINITY     = "INFINITY"
@@ -75,6 +139,7 @@ const (
 
 var reservedKeywords = map[string]TokenType{
 	"fn":     FUNCTION,
+	"macro":  MACRO,
 	"var":    VAR,
 	"true":   TRUE,
 	"false":  FALSE,
@@ -83,15 +148,37 @@ var reservedKeywords = map[string]TokenType{
 	"return": RETURN,
 	"nil":    NIL,
 	"assign": ASSIGN,
+	"spawn":  SPAWN,
+	"await":  AWAIT,
+	"for":    FOR,
+	"in":     IN,
+	"yield":  YIELD,
+
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
 
 	"and": AND,
 	"or":  OR,
 	"not": NOT,
+	"xor": XOR,
 
 	"Inf": INFINITY,
 	"NaN": NOT_A_NUMBER,
 }
 
+// Keywords lists the reserved words in alphabetical order ---
+func Keywords() []string {
+	keywords := make([]string, 0, len(reservedKeywords))
+	for keyword := range reservedKeywords {
+		keywords = append(keywords, keyword)
+	}
+
+	sort.Strings(keywords)
+	return keywords
+}
+
 func LookupIdentifier(ident string) TokenType {
 	if tok, ok := reservedKeywords[ident]; ok {
 		return tok
@@ -2,7 +2,6 @@ package vm
 
 import (
 	"fmt"
-	"math"
 	"strings"
 
 	"github.com/caelondev/monkey-compiler-go/src/code"
@@ -14,7 +13,36 @@ func (vm *VM) executeBinop(opcode code.OpCode) error {
 	l := vm.peekStackAddr(1)
 
 	switch {
-	case l.Type() == object.NUMBER_OBJECT && r.Type() == object.NUMBER_OBJECT:
+	// NaN swallows every operand, like in the evaluator ---
+	case l.Type() == object.NAN_OBJECT || r.Type() == object.NAN_OBJECT:
+		right := vm.pop()
+		left := vm.pop()
+		return vm.push(object.FloatOperation(opcodeToOperator(opcode), left, right))
+
+	case (object.IsBig(l) || object.IsBig(r)) && object.IsNumber(l) && object.IsNumber(r):
+		right := vm.pop()
+		left := vm.pop()
+
+		result, err := object.BigArithmetic(opcodeToOperator(opcode), left, right)
+		if err != nil {
+			return err
+		}
+		return vm.push(result)
+
+	case l.Type() == object.INTEGER_OBJECT && r.Type() == object.INTEGER_OBJECT:
+		right := vm.pop().(*object.Integer)
+		left := vm.pop().(*object.Integer)
+
+		result, err := object.IntegerArithmetic(opcodeToOperator(opcode), left.Value, right.Value)
+		if err != nil {
+			return err
+		}
+		if result != nil {
+			return vm.push(result)
+		}
+		return vm.executeNumericBinop(left, right, opcode)
+
+	case isNumeric(l) && isNumeric(r):
 		right := vm.pop()
 		left := vm.pop()
 		return vm.executeNumericBinop(left, right, opcode)
@@ -28,13 +56,13 @@ func (vm *VM) executeBinop(opcode code.OpCode) error {
 			return vm.push(&object.String{Value: left.Value + right.Value})
 		}
 
-	case l.Type() == object.STRING_OBJECT && r.Type() == object.NUMBER_OBJECT:
-		right, _ := vm.pop().(*object.Number)
+	case l.Type() == object.STRING_OBJECT && isNumeric(r):
+		right, _ := object.ToInt(vm.pop())
 		left, _ := vm.pop().(*object.String)
 
 		// repeat
 		if opcode == code.OpMultiply {
-			return vm.push(&object.String{Value: strings.Repeat(left.Value, int(right.Value))})
+			return vm.push(&object.String{Value: strings.Repeat(left.Value, right)})
 		}
 
 	default:
@@ -44,27 +72,30 @@ func (vm *VM) executeBinop(opcode code.OpCode) error {
 	return fmt.Errorf("Invalid operator type '%s' for operand type %s and %s", opcodeToOperator(opcode), l.Type(), r.Type())
 }
 
+// executeNumericBinop works with floats, an Integer is promoted, ---
+// Infinity and NaN results are the same objects the evaluator makes ---
 func (vm *VM) executeNumericBinop(left, right object.Object, opcode code.OpCode) error {
-	l := left.(*object.Number).Value
-	r := right.(*object.Number).Value
-
-	var err error
-
-	switch opcode {
-	case code.OpAdd:
-		err = vm.push(&object.Number{Value: l + r})
-	case code.OpSubtract:
-		err = vm.push(&object.Number{Value: l - r})
-	case code.OpMultiply:
-		err = vm.push(&object.Number{Value: l * r})
-	case code.OpDivide:
-		err = vm.push(&object.Number{Value: l / r})
-	case code.OpExponent:
-		err = vm.push(&object.Number{Value: math.Pow(l, r)})
+	result := object.FloatOperation(opcodeToOperator(opcode), promoteInteger(left), promoteInteger(right))
+	if result == nil {
+		return fmt.Errorf("Invalid operator type '%s' for operand type %s and %s", opcodeToOperator(opcode), left.Type(), right.Type())
+	}
+
+	return vm.push(result)
+}
+
+func promoteInteger(obj object.Object) object.Object {
+	if integer, ok := obj.(*object.Integer); ok {
+		return &object.Number{Value: float64(integer.Value)}
 	}
 
-	if err != nil {
-		return err
+	return obj
+}
+
+func isNumeric(obj object.Object) bool {
+	switch obj.Type() {
+	case object.NUMBER_OBJECT, object.INTEGER_OBJECT, object.INFINITY_OBJECT, object.NAN_OBJECT:
+		return true
 	}
-	return nil
+
+	return false
 }
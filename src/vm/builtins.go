@@ -0,0 +1,207 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// Builtins are plain host functions bound to this VM, so ---
+// print/prompt follow vm.Stdout/vm.Stdin ---
+func (vm *VM) newBuiltins() []object.Object {
+	implementations := map[string]object.HostFunctionFn{
+		"len":        builtinLen,
+		"print":      vm.builtinPrint,
+		"prompt":     vm.builtinPrompt,
+		"time":       builtinTime,
+		"to_string":  builtinToString,
+		"to_number":  builtinToNumber,
+		"to_integer": builtinToInteger,
+		"bigint":     builtinConversion(object.ToBigInt),
+		"decimal":    builtinConversion(object.ToDecimal),
+		"type":       builtinType,
+		"is_NaN":     builtinIsType(object.NAN_OBJECT),
+		"is_Inf":     builtinIsType(object.INFINITY_OBJECT),
+		"is_nil":     builtinIsType(object.NIL_OBJECT),
+		"random":     builtinRandom,
+		"next":       builtinNext,
+		"is_done":    builtinIsDone,
+		"div":        builtinDivision(object.FloorDivide),
+		"mod":        builtinDivision(object.Modulo),
+	}
+
+	for name, fn := range vm.tasks.Builtins() {
+		implementations[name] = fn
+	}
+
+	builtins := make([]object.Object, len(object.BuiltinNames))
+	for i, name := range object.BuiltinNames {
+		builtins[i] = &object.HostFunction{Name: name, Fn: implementations[name]}
+	}
+
+	return builtins
+}
+
+func expectArgs(args []object.Object, count int) error {
+	if len(args) != count {
+		return fmt.Errorf("Expected %d argument, got %d", count, len(args))
+	}
+
+	return nil
+}
+
+func builtinLen(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.String:
+		return &object.Integer{Value: int64(len(arg.Value))}, nil
+	case *object.Array:
+		return &object.Integer{Value: int64(len(arg.Elements))}, nil
+
+	default:
+		return nil, fmt.Errorf("Cannot get length of type '%s'", arg.Type())
+	}
+}
+
+func (vm *VM) builtinPrint(args ...object.Object) (object.Object, error) {
+	for i, arg := range args {
+		fmt.Fprintf(vm.Stdout, "%s", object.Display(arg))
+		if i != len(args)-1 {
+			fmt.Fprintf(vm.Stdout, ", ")
+		}
+	}
+	fmt.Fprintln(vm.Stdout)
+	return object.NIL, nil
+}
+
+func (vm *VM) builtinPrompt(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	message, ok := args[0].(*object.String)
+	if !ok {
+		return nil, fmt.Errorf("Cannot use prompt message type '%s' as prompt message", args[0].Type())
+	}
+
+	fmt.Fprint(vm.Stdout, message.Value)
+
+	if vm.stdinScanner == nil || vm.scannerInput != vm.Stdin {
+		vm.stdinScanner = bufio.NewScanner(vm.Stdin)
+		vm.scannerInput = vm.Stdin
+	}
+
+	if vm.stdinScanner.Scan() {
+		return &object.String{Value: vm.stdinScanner.Text()}, nil
+	}
+
+	return nil, fmt.Errorf("I/O error")
+}
+
+func builtinTime(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return nil, err
+	}
+
+	return &object.Number{Value: float64(time.Now().UnixNano()) / 1e6}, nil // milliseconds
+}
+
+func builtinToString(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	return &object.String{Value: args[0].Inspect()}, nil
+}
+
+func builtinToNumber(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	switch obj := args[0].(type) {
+	case *object.Number, *object.NaN, *object.Infinity:
+		return obj, nil
+	case *object.Integer:
+		return &object.Number{Value: float64(obj.Value)}, nil
+	case *object.BigInt, *object.Decimal:
+		value, _ := object.BigToFloat(obj)
+		return &object.Number{Value: value}, nil
+	case *object.String:
+		v, err := strconv.ParseFloat(obj.Value, 64)
+		if err != nil {
+			return object.NAN, nil
+		}
+		return &object.Number{Value: v}, nil
+	case *object.Boolean:
+		if obj.Value {
+			return &object.Number{Value: 1}, nil
+		}
+		return &object.Number{Value: 0}, nil
+	default:
+		return object.NAN, nil
+	}
+}
+
+func builtinToInteger(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	return object.ToInteger(args[0])
+}
+
+// builtinConversion backs bigint() and decimal() ---
+func builtinConversion(convert func(obj object.Object) (object.Object, error)) object.HostFunctionFn {
+	return func(args ...object.Object) (object.Object, error) {
+		if err := expectArgs(args, 1); err != nil {
+			return nil, err
+		}
+
+		return convert(args[0])
+	}
+}
+
+// builtinDivision backs div() and mod() ---
+func builtinDivision(divide func(l, r object.Object) (object.Object, error)) object.HostFunctionFn {
+	return func(args ...object.Object) (object.Object, error) {
+		if err := expectArgs(args, 2); err != nil {
+			return nil, err
+		}
+
+		return divide(args[0], args[1])
+	}
+}
+
+func builtinType(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	return &object.String{Value: string(args[0].Type())}, nil
+}
+
+func builtinIsType(objectType object.ObjectType) object.HostFunctionFn {
+	return func(args ...object.Object) (object.Object, error) {
+		if err := expectArgs(args, 1); err != nil {
+			return nil, err
+		}
+
+		return nativeBoolToBooleanObject(args[0].Type() == objectType), nil
+	}
+}
+
+func builtinRandom(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return nil, err
+	}
+
+	return &object.Number{Value: rand.Float64()}, nil
+}
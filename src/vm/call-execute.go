@@ -0,0 +1,158 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+func (vm *VM) executeCall(numArgs int) error {
+	callee := vm.stack[vm.stackPointer-1-numArgs]
+
+	switch callee := callee.(type) {
+	case *object.Closure:
+		return vm.callClosure(callee, numArgs)
+	case *object.HostFunction:
+		return vm.callHostFunction(callee, numArgs)
+
+	default:
+		return fmt.Errorf("Attempted to call %s", callee.Type())
+	}
+}
+
+func (vm *VM) callClosure(cl *object.Closure, numArgs int) error {
+	if numArgs != cl.Fn.NumParameters {
+		return fmt.Errorf("Expected %d arguments, got %d", cl.Fn.NumParameters, numArgs)
+	}
+
+	// The body only runs once the generator is resumed ---
+	if cl.Fn.IsGenerator {
+		args := make([]object.Object, numArgs)
+		copy(args, vm.stack[vm.stackPointer-numArgs:vm.stackPointer])
+		vm.stackPointer = vm.stackPointer - numArgs - 1
+
+		return vm.push(vm.newGenerator(cl, args))
+	}
+
+	return vm.enterClosure(cl, numArgs)
+}
+
+func (vm *VM) enterClosure(cl *object.Closure, numArgs int) error {
+	if vm.framesIndex >= MAX_FRAMES {
+		return fmt.Errorf("Maximum call stack depth exceeded (%d calls)", vm.framesIndex)
+	}
+
+	// Arguments are already in place, they become the first locals ---
+	frame := NewFrame(cl, vm.stackPointer-numArgs)
+	if frame.basePointer+cl.Fn.NumLocals >= STACK_SIZE {
+		return fmt.Errorf("Stack overflow")
+	}
+
+	// Slots past the arguments may still hold cells of an earlier call ---
+	clear(vm.stack[vm.stackPointer : frame.basePointer+cl.Fn.NumLocals])
+
+	vm.pushFrame(frame)
+	vm.stackPointer = frame.basePointer + cl.Fn.NumLocals
+
+	return nil
+}
+
+func (vm *VM) callHostFunction(fn *object.HostFunction, numArgs int) error {
+	args := make([]object.Object, numArgs)
+	copy(args, vm.stack[vm.stackPointer-numArgs:vm.stackPointer])
+
+	result, err := fn.Call(args...)
+	if err != nil {
+		// Builtins word their errors like the evaluator's, only ---
+		// functions from the host are named ---
+		if vm.isBuiltin(fn) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", fn.Name, err)
+	}
+
+	vm.stackPointer = vm.stackPointer - numArgs - 1
+	return vm.push(result)
+}
+
+func (vm *VM) isBuiltin(fn *object.HostFunction) bool {
+	for _, builtin := range vm.builtins {
+		if builtin == fn {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (vm *VM) pushClosure(constIndex int, numFree int) error {
+	constant := vm.constants[constIndex]
+	function, ok := constant.(*object.CompiledFunction)
+	if !ok {
+		return fmt.Errorf("Cannot create a closure from non-function constant %+v", constant)
+	}
+
+	free := make([]object.Object, numFree)
+	for i := 0; i < numFree; i++ {
+		free[i] = vm.stack[vm.stackPointer-numFree+i]
+	}
+	vm.stackPointer -= numFree
+
+	return vm.push(&object.Closure{Fn: function, Free: free})
+}
+
+// CallFunction calls a script function (or host function) from Go,
+// using this VM's globals. It may be called after Run has finished,
+// or from inside a host function while Run is still executing.
+func (vm *VM) CallFunction(fn object.Object, args ...object.Object) (object.Object, error) {
+	floor := vm.framesIndex
+	base := vm.stackPointer
+
+	// A panic unwinds past the caller's frames too, leave them as ---
+	// they were so the VM stays usable ---
+	defer func() {
+		if r := recover(); r != nil {
+			vm.framesIndex = floor
+			vm.stackPointer = base
+			panic(r)
+		}
+	}()
+
+	err := vm.push(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arg := range args {
+		err := vm.push(arg)
+		if err != nil {
+			vm.stackPointer = base
+			return nil, err
+		}
+	}
+
+	err = vm.executeCall(len(args))
+	if err == nil {
+		err = vm.execute(floor)
+	}
+
+	if err != nil {
+		vm.framesIndex = floor
+		vm.stackPointer = base
+		return nil, err
+	}
+
+	result := vm.pop()
+	vm.stackPointer = base
+	return result, nil
+}
+
+// GetGlobal/SetGlobal read and write a global slot, the index comes ---
+// from the compiler's symbol table ---
+func (vm *VM) GetGlobal(index int) object.Object {
+	return vm.globals[index]
+}
+
+func (vm *VM) SetGlobal(index int, value object.Object) {
+	vm.globals[index] = value
+}
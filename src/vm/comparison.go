@@ -11,7 +11,11 @@ func (vm *VM) executeComparison(op code.OpCode) error {
 	right := vm.pop()
 	left := vm.pop()
 
-	if right.Type() == object.NUMBER_OBJECT && left.Type() == object.NUMBER_OBJECT {
+	if isNumeric(left) && isNumeric(right) && (isInfOrNaN(left) || isInfOrNaN(right)) {
+		return vm.push(object.FloatOperation(opcodeToOperator(op), promoteInteger(left), promoteInteger(right)))
+	}
+
+	if object.IsNumber(right) && object.IsNumber(left) {
 		return vm.executeNumberComparison(op, left, right)
 	}
 
@@ -25,28 +29,36 @@ func (vm *VM) executeComparison(op code.OpCode) error {
 	return fmt.Errorf("Unknown comparison operator: '%d'\n", op)
 }
 
+// executeNumberComparison compares any two numbers exactly, ---
+// against NaN only != holds ---
 func (vm *VM) executeNumberComparison(op code.OpCode, left, right object.Object) error {
-	l := left.(*object.Number).Value
-	r := right.(*object.Number).Value
+	order, ok := object.CompareNumbers(left, right)
+	if !ok {
+		return vm.push(nativeBoolToBooleanObject(op == code.OpNotEqual))
+	}
 
 	switch op {
 	case code.OpEqual:
-		return vm.push(nativeBoolToBooleanObject(l == r))
+		return vm.push(nativeBoolToBooleanObject(order == 0))
 	case code.OpNotEqual:
-		return vm.push(nativeBoolToBooleanObject(l != r))
+		return vm.push(nativeBoolToBooleanObject(order != 0))
 	case code.OpLess:
-		return vm.push(nativeBoolToBooleanObject(l < r))
+		return vm.push(nativeBoolToBooleanObject(order < 0))
 	case code.OpGreater:
-		return vm.push(nativeBoolToBooleanObject(l > r))
+		return vm.push(nativeBoolToBooleanObject(order > 0))
 	case code.OpLessEqual:
-		return vm.push(nativeBoolToBooleanObject(l <= r))
+		return vm.push(nativeBoolToBooleanObject(order <= 0))
 	case code.OpGreaterEqual:
-		return vm.push(nativeBoolToBooleanObject(l >= r))
+		return vm.push(nativeBoolToBooleanObject(order >= 0))
 	}
 
 	return fmt.Errorf("Unknown comparison operator: '%d'\n", op)
 }
 
+func isInfOrNaN(obj object.Object) bool {
+	return obj.Type() == object.INFINITY_OBJECT || obj.Type() == object.NAN_OBJECT
+}
+
 func nativeBoolToBooleanObject(b bool) *object.Boolean {
 	if b {
 		return object.TRUE
@@ -0,0 +1,90 @@
+package vm
+
+import (
+	"errors"
+
+	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/scheduler"
+)
+
+// Hook runs before every instruction while it's set, ---
+// non-debug runs only pay for the nil check ---
+type Hook func(vm *VM) error
+
+// ErrStopped is what a Hook returns to end the run, it can't be caught ---
+var ErrStopped = errors.New("Execution stopped by the debugger")
+
+// SetHook installs (or with nil, removes) the debug hook. ---
+// Tasks and generators spawned afterwards share it ---
+func (vm *VM) SetHook(hook Hook) {
+	vm.hook = hook
+}
+
+// DebugFrame is a snapshot of one active call ---
+type DebugFrame struct {
+	Function *object.CompiledFunction
+	IsMain   bool
+	Line     int
+	Column   int
+
+	Locals []object.Object // By slot, see compiler.FunctionSymbols ---
+	Free   []object.Object
+}
+
+// DebugFrames lists the active calls, innermost first. When called ---
+// from a Hook the innermost position is the instruction about to run ---
+func (vm *VM) DebugFrames() []DebugFrame {
+	frames := make([]DebugFrame, 0, vm.framesIndex)
+
+	for i := vm.framesIndex - 1; i >= 0; i-- {
+		frame := vm.frames[i]
+		fn := frame.closure.Fn
+
+		ip := frame.ip
+		if i == vm.framesIndex-1 {
+			ip++
+		}
+		line, column := code.LineAt(fn.Lines, ip)
+
+		locals := make([]object.Object, 0, fn.NumLocals)
+		if frame != vm.mainFrame {
+			for _, local := range vm.stack[frame.basePointer : frame.basePointer+fn.NumLocals] {
+				locals = append(locals, deref(local))
+			}
+		}
+
+		free := make([]object.Object, len(frame.closure.Free))
+		for i, value := range frame.closure.Free {
+			free[i] = deref(value)
+		}
+
+		frames = append(frames, DebugFrame{
+			Function: fn,
+			IsMain:   frame == vm.mainFrame,
+			Line:     line,
+			Column:   column,
+			Locals:   locals,
+			Free:     free,
+		})
+	}
+
+	return frames
+}
+
+// Depth is the number of active calls, the main frame included ---
+func (vm *VM) Depth() int {
+	return vm.framesIndex
+}
+
+// Line is the source line of the next instruction, for hooks ---
+func (vm *VM) Line() int {
+	frame := vm.currentFrame()
+	line, _ := code.LineAt(frame.closure.Fn.Lines, frame.ip+1)
+	return line
+}
+
+// Errors a script can't catch and that don't get a trace ---
+func isUncatchable(err error) bool {
+	return errors.Is(err, scheduler.ErrAborted) || errors.Is(err, ErrStopped)
+}
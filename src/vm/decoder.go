@@ -3,13 +3,24 @@ package vm
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
 
+	"github.com/caelondev/monkey-compiler-go/src/ast"
 	"github.com/caelondev/monkey-compiler-go/src/code"
 	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
 	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
 )
 
+// BYTECODE_VERSION is bumped whenever the encoding changes, files ---
+// built for another version are rejected instead of misread. ---
+// Version 2 gave handlers their finally target ---
+const BYTECODE_VERSION = 2
+
 func readUint32(buf *bytes.Reader) (uint32, error) {
 	var val uint32
 	err := binary.Read(buf, binary.BigEndian, &val)
@@ -26,8 +37,8 @@ func readString(buf *bytes.Reader) (string, error) {
 	// Allocate bytes based on string length
 	strBytes := make([]byte, length)
 
-	// Set allocated bytes to char bytes
-	if _, err := buf.Read(strBytes); err != nil {
+	// Set allocated bytes to char bytes, ReadFull allows an empty string at the end ---
+	if _, err := io.ReadFull(buf, strBytes); err != nil {
 		return "", err
 	}
 
@@ -40,7 +51,153 @@ func readFloat64(buf *bytes.Reader) (float64, error) {
 	return val, err
 }
 
+func readInt64(buf *bytes.Reader) (int64, error) {
+	var val int64
+	err := binary.Read(buf, binary.BigEndian, &val)
+	return val, err
+}
+
+func readBigInt(buf *bytes.Reader) (*big.Int, error) {
+	digits, err := readString(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid big integer constant: %q", digits)
+	}
+
+	return value, nil
+}
+
+func readInstructions(buf *bytes.Reader) ([]byte, error) {
+	instLen, err := readUint32(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := make([]byte, instLen)
+	if _, err := io.ReadFull(buf, instructions); err != nil {
+		return nil, err
+	}
+
+	return instructions, nil
+}
+
+func readHandlers(buf *bytes.Reader) ([]code.Handler, error) {
+	count, err := readUint32(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := make([]code.Handler, count)
+	for i := range handlers {
+		fields := make([]uint32, 5)
+		for j := range fields {
+			fields[j], err = readUint32(buf)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		handlers[i] = code.Handler{
+			Start:      int(fields[0]),
+			End:        int(fields[1]),
+			Target:     int(fields[2]),
+			StackDepth: int(fields[3]),
+			Finally:    int(fields[4]),
+		}
+	}
+
+	return handlers, nil
+}
+
+func readLines(buf *bytes.Reader) ([]code.SourcePos, error) {
+	count, err := readUint32(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]code.SourcePos, count)
+	for i := range lines {
+		fields := make([]uint32, 3)
+		for j := range fields {
+			fields[j], err = readUint32(buf)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		lines[i] = code.SourcePos{
+			Offset: int(fields[0]),
+			Line:   int(fields[1]),
+			Column: int(fields[2]),
+		}
+	}
+
+	return lines, nil
+}
+
+func readCompiledFunction(buf *bytes.Reader) (*object.CompiledFunction, error) {
+	name, err := readString(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	numLocals, err := readUint32(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	numParameters, err := readUint32(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	isGenerator, err := buf.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := readInstructions(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers, err := readHandlers(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := readLines(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &object.CompiledFunction{
+		Instructions:  instructions,
+		NumLocals:     int(numLocals),
+		NumParameters: int(numParameters),
+		Name:          name,
+		IsGenerator:   isGenerator == 1,
+		Handlers:      handlers,
+		Lines:         lines,
+	}, nil
+}
+
+// DecodeBytecode reads a file written by build.EncodeBytecode, one that ---
+// ends early is an error rather than a program missing its sections ---
 func DecodeBytecode(data []byte) (*compiler.Bytecode, error) {
+	bytecode, err := decodeBytecode(data)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("truncated bytecode file")
+	}
+
+	return bytecode, err
+}
+
+func decodeBytecode(data []byte) (*compiler.Bytecode, error) {
 	buf := bytes.NewReader(data)
 
 	// Check magic
@@ -57,8 +214,8 @@ func DecodeBytecode(data []byte) (*compiler.Bytecode, error) {
 	if err != nil {
 		return nil, err
 	}
-	if versionByte != 1 {
-		return nil, fmt.Errorf("unsupported bytecode version: %d", versionByte)
+	if versionByte != BYTECODE_VERSION {
+		return nil, fmt.Errorf("unsupported bytecode version: %d (expected %d), rebuild the file", versionByte, BYTECODE_VERSION)
 	}
 
 	// Read constants
@@ -82,30 +239,96 @@ func DecodeBytecode(data []byte) (*compiler.Bytecode, error) {
 				return nil, err
 			}
 			constants = append(constants, &object.Number{Value: num})
+		case byte(code.CONSTANT_INTEGER):
+			integer, err := readInt64(buf)
+			if err != nil {
+				return nil, err
+			}
+			constants = append(constants, &object.Integer{Value: integer})
+		case byte(code.CONSTANT_BIGINT):
+			value, err := readBigInt(buf)
+			if err != nil {
+				return nil, err
+			}
+			constants = append(constants, &object.BigInt{Value: value})
+		case byte(code.CONSTANT_DECIMAL):
+			unscaled, err := readBigInt(buf)
+			if err != nil {
+				return nil, err
+			}
+			scale, err := readUint32(buf)
+			if err != nil {
+				return nil, err
+			}
+			constants = append(constants, &object.Decimal{Unscaled: unscaled, Scale: int32(scale)})
 		case byte(code.CONSTANT_STRING):
 			str, err := readString(buf)
 			if err != nil {
 				return nil, err
 			}
 			constants = append(constants, &object.String{Value: str})
+		case byte(code.CONSTANT_FUNCTION):
+			fn, err := readCompiledFunction(buf)
+			if err != nil {
+				return nil, err
+			}
+			constants = append(constants, fn)
+		case byte(code.CONSTANT_QUOTE):
+			quote, err := readQuote(buf)
+			if err != nil {
+				return nil, err
+			}
+			constants = append(constants, quote)
 
 		default:
 			return nil, fmt.Errorf("unknown constant tag: %d", tag)
 		}
 	}
 
-	instLen, err := readUint32(buf)
+	instructions, err := readInstructions(buf)
 	if err != nil {
 		return nil, err
 	}
 
-	instructions := make([]byte, instLen)
-	if _, err := buf.Read(instructions); err != nil {
+	bytecode := &compiler.Bytecode{
+		Constants:    constants,
+		Instructions: instructions,
+	}
+
+	bytecode.Handlers, err = readHandlers(buf)
+	if err != nil {
 		return nil, err
 	}
 
-	return &compiler.Bytecode{
-		Constants:    constants,
-		Instructions: instructions,
-	}, nil
+	bytecode.Lines, err = readLines(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	bytecode.File, err = readString(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytecode, nil
+}
+
+func readQuote(buf *bytes.Reader) (*object.Quote, error) {
+	source, err := readString(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 || len(program.Statements) != 1 {
+		return nil, fmt.Errorf("invalid quote constant: %s", source)
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		return nil, fmt.Errorf("invalid quote constant: %s", source)
+	}
+
+	return &object.Quote{Node: stmt.Expression}, nil
 }
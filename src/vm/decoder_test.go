@@ -0,0 +1,29 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeRejectsOtherVersions(t *testing.T) {
+	for _, version := range []byte{1, BYTECODE_VERSION + 1} {
+		data := append([]byte("MCGO"), version, 0, 0, 0, 0)
+
+		_, err := DecodeBytecode(data)
+		if err == nil || !strings.Contains(err.Error(), "unsupported bytecode version") {
+			t.Errorf("version %d: expected an unsupported bytecode version error, got %v", version, err)
+		}
+	}
+}
+
+func TestDecodeRejectsTruncatedFiles(t *testing.T) {
+	// No constants and no instructions, then the handlers are missing ---
+	data := []byte{'M', 'C', 'G', 'O', BYTECODE_VERSION, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	for end := 5; end <= len(data); end++ {
+		_, err := DecodeBytecode(data[:end])
+		if err == nil || err.Error() != "truncated bytecode file" {
+			t.Errorf("%d bytes: expected a truncated bytecode file error, got %v", end, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package vm
+
+import (
+	"errors"
+
+	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// ThrownError carries a script error through Go code (host functions, ---
+// CallFunction) so a handler further down still catches the original ---
+type ThrownError struct {
+	Err *object.Error
+}
+
+func (e *ThrownError) Error() string {
+	return e.Err.Message
+}
+
+func toErrorObject(err error) *object.Error {
+	var thrown *ThrownError
+	if errors.As(err, &thrown) {
+		return thrown.Err
+	}
+
+	return &object.Error{Message: err.Error()}
+}
+
+// traceError attaches the position and the active calls to an error ---
+// leaving run, errors coming back out of a nested execute keep theirs ---
+func (vm *VM) traceError(err error) error {
+	if isUncatchable(err) {
+		return err
+	}
+
+	var thrown *ThrownError
+	if errors.As(err, &thrown) && thrown.Err.Trace != nil {
+		return err
+	}
+
+	errObj := toErrorObject(err)
+	errObj.Trace = make([]object.TraceFrame, 0, vm.framesIndex)
+
+	for i := vm.framesIndex - 1; i >= 0; i-- {
+		errObj.Trace = append(errObj.Trace, vm.traceFrame(vm.frames[i]))
+	}
+
+	if errObj.Line == 0 && len(errObj.Trace) > 0 {
+		errObj.Line, errObj.Column = errObj.Trace[0].Line, errObj.Trace[0].Column
+	}
+
+	return &ThrownError{Err: errObj}
+}
+
+func (vm *VM) traceFrame(frame *Frame) object.TraceFrame {
+	fn := frame.closure.Fn
+	line, column := code.LineAt(fn.Lines, frame.ip)
+
+	name := fn.Name
+	if frame == vm.mainFrame {
+		name = "<main>"
+	} else if name == "" {
+		name = "<anonymous>"
+	}
+
+	return object.TraceFrame{Function: name, File: vm.program.file, Line: uint(line), Column: uint(column)}
+}
+
+// handleError unwinds to the innermost handler covering the failing ---
+// instruction, without crossing floor (frames of an outer execute) ---
+func (vm *VM) handleError(err error, floor int) bool {
+	if isUncatchable(err) {
+		return false
+	}
+
+	fatal := toErrorObject(err).Fatal
+
+	for i := vm.framesIndex - 1; i >= floor; i-- {
+		frame := vm.frames[i]
+
+		handlers := frame.closure.Fn.Handlers
+		for h := len(handlers) - 1; h >= 0; h-- {
+			handler := handlers[h]
+			if frame.ip < handler.Start || frame.ip >= handler.End {
+				continue
+			}
+
+			target := handler.Target
+			if fatal {
+				target = handler.Finally
+			}
+			if target == 0 {
+				continue
+			}
+
+			clear(vm.frames[i+1 : vm.framesIndex])
+			vm.framesIndex = i + 1
+
+			// Loops the error leaves close their iterators ---
+			depth := frame.basePointer + frame.closure.Fn.NumLocals + handler.StackDepth
+			vm.closeIterators(depth)
+			vm.stackPointer = depth
+			frame.ip = target - 1
+
+			return vm.push(&object.Exception{Err: toErrorObject(err)}) == nil
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,26 @@
+package vm
+
+import (
+	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+const MAX_FRAMES = 1024
+
+type Frame struct {
+	closure     *object.Closure
+	ip          int
+	basePointer int
+}
+
+func NewFrame(closure *object.Closure, basePointer int) *Frame {
+	return &Frame{
+		closure:     closure,
+		ip:          -1,
+		basePointer: basePointer,
+	}
+}
+
+func (f *Frame) Instructions() code.Instructions {
+	return f.closure.Fn.Instructions
+}
@@ -0,0 +1,174 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/scheduler"
+)
+
+// A generator gets a VM of its own whose bottom frame is the generator ---
+// function. OpYield returns from execute leaving the frames in place, ---
+// so resuming is just executing that VM again ---
+func (vm *VM) newGenerator(cl *object.Closure, args []object.Object) *object.Generator {
+	child := vm.newTaskVM()
+	started := false
+
+	resume := func(sent object.Object) (object.Object, bool, error) {
+		if !started {
+			started = true
+
+			// Same layout as a regular call: callee, then arguments ---
+			child.push(cl)
+			for _, arg := range args {
+				child.push(arg)
+			}
+
+			err := child.enterClosure(cl, len(args))
+			if err != nil {
+				return nil, true, err
+			}
+		} else {
+			err := child.push(sent)
+			if err != nil {
+				return nil, true, err
+			}
+		}
+
+		err := child.execute(0)
+		if err != nil {
+			return nil, true, err
+		}
+
+		if child.yielded {
+			child.yielded = false
+			return child.pop(), false, nil
+		}
+
+		// Ran off the generator function, its return value is left on the stack ---
+		return child.pop(), true, nil
+	}
+
+	// Raises an error at the paused yield that only finally blocks ---
+	// stop at, the same as the evaluator's ---
+	stop := func() {
+		if !started {
+			return
+		}
+
+		err := child.traceError(errGeneratorClosed())
+		if child.handleError(err, 0) {
+			child.execute(0)
+		} else {
+			child.closeIterators(0)
+		}
+		child.yielded = false
+	}
+
+	return object.NewGenerator(cl.Fn.Name, resume, stop)
+}
+
+func errGeneratorClosed() error {
+	return &ThrownError{Err: &object.Error{Message: "Generator was closed", Fatal: true}}
+}
+
+func (vm *VM) executeIter() error {
+	iterable := vm.pop()
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		elements := append([]object.Object{}, iterable.Elements...)
+		return vm.push(&iterator{next: func() (object.Object, bool, error) {
+			if len(elements) == 0 {
+				return nil, false, nil
+			}
+			element := elements[0]
+			elements = elements[1:]
+			return element, true, nil
+		}})
+
+	case *object.String:
+		chars := []rune(iterable.Value)
+		return vm.push(&iterator{next: func() (object.Object, bool, error) {
+			if len(chars) == 0 {
+				return nil, false, nil
+			}
+			char := chars[0]
+			chars = chars[1:]
+			return &object.String{Value: string(char)}, true, nil
+		}})
+
+	case *object.Generator:
+		return vm.push(&iterator{next: func() (object.Object, bool, error) {
+			value, err := iterable.Next(object.NIL)
+			if err != nil || iterable.Done {
+				return nil, false, err
+			}
+			return value, true, nil
+		}, close: iterable.Close})
+
+	case *scheduler.Channel:
+		return vm.push(&iterator{next: func() (object.Object, bool, error) {
+			return vm.tasks.Receive(iterable)
+		}})
+
+	default:
+		return fmt.Errorf("Cannot iterate over type '%s'", iterable.Type())
+	}
+}
+
+// iterator only ever lives on the stack during a for ... in loop, ---
+// close (if any) runs when the loop is left before it's exhausted ---
+type iterator struct {
+	next  func() (object.Object, bool, error)
+	close func()
+}
+
+func (o *iterator) Type() object.ObjectType {
+	return "ITERATOR"
+}
+
+func (o *iterator) Inspect() string {
+	return "[ Iterator ]"
+}
+
+// closeIterators closes the iterators in stack[from:stackPointer], ---
+// innermost loop first ---
+func (vm *VM) closeIterators(from int) {
+	for i := vm.stackPointer - 1; i >= from; i-- {
+		if iter, ok := vm.stack[i].(*iterator); ok && iter.close != nil {
+			iter.close()
+		}
+	}
+}
+
+func builtinIsDone(args ...object.Object) (object.Object, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	generator, ok := args[0].(*object.Generator)
+	if !ok {
+		return nil, fmt.Errorf("Cannot check type '%s'", args[0].Type())
+	}
+
+	return nativeBoolToBooleanObject(generator.Done), nil
+}
+
+func builtinNext(args ...object.Object) (object.Object, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, fmt.Errorf("Expected 1 or 2 arguments, got %d", len(args))
+	}
+
+	generator, ok := args[0].(*object.Generator)
+	if !ok {
+		return nil, fmt.Errorf("Cannot resume type '%s'", args[0].Type())
+	}
+
+	var sent object.Object = object.NIL
+	if len(args) == 2 {
+		sent = args[1]
+	}
+
+	return generator.Next(sent)
+}
@@ -18,6 +18,32 @@ func opcodeToOperator(opcode code.OpCode) token.TokenType {
 		return token.SLASH
 	case code.OpExponent:
 		return token.CARET
+	case code.OpModulo:
+		return token.PERCENT
+	case code.OpFloorDivide:
+		return token.FLOOR_DIVIDE
+	case code.OpBitAnd:
+		return token.AMPERSAND
+	case code.OpBitOr:
+		return token.PIPE
+	case code.OpBitXor:
+		return token.XOR
+	case code.OpShiftLeft:
+		return token.SHIFT_LEFT
+	case code.OpShiftRight:
+		return token.SHIFT_RIGHT
+	case code.OpEqual:
+		return token.EQUAL
+	case code.OpNotEqual:
+		return token.NOT_EQUAL
+	case code.OpLess:
+		return token.LESS
+	case code.OpLessEqual:
+		return token.LESS_EQUAL
+	case code.OpGreater:
+		return token.GREATER
+	case code.OpGreaterEqual:
+		return token.GREATER_EQUAL
 
 	default:
 		return token.ILLEGAL
@@ -35,6 +61,12 @@ func isTruthy(obj object.Object) bool {
 	case *object.Number:
 		return obj.Value != 0
 
+	case *object.Integer:
+		return obj.Value != 0
+
+	case *object.BigInt, *object.Decimal:
+		return object.SignOf(obj) != 0
+
 	case *object.NaN:
 		return false
 
@@ -42,3 +74,22 @@ func isTruthy(obj object.Object) bool {
 		return true
 	}
 }
+
+// deref reads a variable slot, through its cell once it's captured ---
+func deref(obj object.Object) object.Object {
+	if cell, ok := obj.(*object.Cell); ok {
+		return cell.Value
+	}
+
+	return obj
+}
+
+// store writes a variable slot, through its cell once it's captured ---
+func store(slot *object.Object, value object.Object) {
+	if cell, ok := (*slot).(*object.Cell); ok {
+		cell.Value = value
+		return
+	}
+
+	*slot = value
+}
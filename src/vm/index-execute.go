@@ -0,0 +1,92 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+func (vm *VM) buildHash(startIndex, endIndex int) (object.Object, error) {
+	pairs := make(map[object.HashKey]object.HashPair)
+
+	for i := startIndex; i < endIndex; i += 2 {
+		key := vm.stack[i]
+		value := vm.stack[i+1]
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return nil, fmt.Errorf("Cannot access hash with key type '%s'", key.Type())
+		}
+
+		pairs[hashKey.HashKey()] = object.HashPair{Key: key, Value: value}
+	}
+
+	return &object.Hash{Pairs: pairs}, nil
+}
+
+func (vm *VM) executeIndexExpression(target, index object.Object) error {
+	switch {
+	case target.Type() == object.ARRAY_OBJECT && isNumeric(index):
+		elements := target.(*object.Array).Elements
+		i, _ := object.ToInt(index)
+
+		if i < 0 || i > len(elements)-1 {
+			return fmt.Errorf("Array index '%d' out-of-bounds", i)
+		}
+
+		return vm.push(elements[i])
+
+	case target.Type() == object.HASH_OBJECT:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return fmt.Errorf("Cannot use key type '%s' for accessing a hash", index.Type())
+		}
+
+		pair, ok := target.(*object.Hash).Pairs[key.HashKey()]
+		if !ok {
+			return vm.push(object.NIL)
+		}
+
+		return vm.push(pair.Value)
+
+	case target.Type() == object.EXCEPTION_OBJECT && index.Type() == object.STRING_OBJECT:
+		field, ok := target.(*object.Exception).Err.Field(index.(*object.String).Value)
+		if !ok {
+			return fmt.Errorf("Exception has no field %s", index.Inspect())
+		}
+
+		return vm.push(field)
+
+	default:
+		return fmt.Errorf("Cannot index expression type '%s' with index type of '%s'", target.Type(), index.Type())
+	}
+}
+
+func (vm *VM) executeSetIndex(target, index, value object.Object) error {
+	switch target := target.(type) {
+	case *object.Array:
+		i, ok := object.ToInt(index)
+		if !ok {
+			return fmt.Errorf("Cannot index an array with index type '%s'", index.Type())
+		}
+
+		if i < 0 || i > len(target.Elements)-1 {
+			return fmt.Errorf("Array index '%d' out-of-bounds", i)
+		}
+
+		target.Elements[i] = value
+
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return fmt.Errorf("Cannot use key type '%s' for accessing a hash", index.Type())
+		}
+
+		target.Pairs[key.HashKey()] = object.HashPair{Key: index, Value: value}
+
+	default:
+		return fmt.Errorf("Cannot re-assign non-indexable expression type '%s'", target.Type())
+	}
+
+	return vm.push(value)
+}
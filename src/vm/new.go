@@ -7,15 +7,12 @@ import (
 	"github.com/caelondev/monkey-compiler-go/src/object"
 )
 
+// New gives the VM a full size global store, for the REPL and
+// callers that seed globals by index ---
 func New(bytecode *compiler.Bytecode) *VM {
-	return &VM{
-		instructions: bytecode.Instructions,
-		constants:    bytecode.Constants,
-
-		stack:        make([]object.Object, STACK_SIZE),
-		globals:      make([]object.Object, GLOBAL_SIZE),
-		stackPointer: 0,
-	}
+	vm := NewFromProgram(NewProgram(bytecode))
+	vm.globals = make([]object.Object, GLOBAL_SIZE)
+	return vm
 }
 
 func NewWithGlobalStore(bytecode *compiler.Bytecode, global []object.Object) *VM {
@@ -35,12 +32,5 @@ func NewFromFile(path string) (*VM, error) {
 		return nil, err
 	}
 
-	return &VM{
-		constants:    bytecode.Constants,
-		instructions: bytecode.Instructions,
-		stack:        make([]object.Object, STACK_SIZE),
-		globals:      make([]object.Object, GLOBAL_SIZE),
-		stackPointer: 0,
-	}, nil
+	return New(bytecode), nil
 }
-
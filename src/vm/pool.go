@@ -0,0 +1,117 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/scheduler"
+)
+
+// Pool hands out VMs for one Program. Every VM is reset before it's ---
+// returned by Get, so executions never observe each other's globals ---
+// Pool is safe for concurrent use ---
+type Pool struct {
+	program *Program
+	vms     sync.Pool
+}
+
+func NewPool(program *Program) *Pool {
+	pool := &Pool{program: program}
+	pool.vms.New = func() any {
+		return NewFromProgram(program)
+	}
+
+	return pool
+}
+
+func (p *Pool) Get() *VM {
+	vm := p.vms.Get().(*VM)
+	vm.reset()
+	return vm
+}
+
+// Put returns vm to the pool, it must not be used afterwards.
+func (p *Pool) Put(vm *VM) {
+	if vm.program != p.program {
+		return
+	}
+
+	p.vms.Put(vm)
+}
+
+// Run executes the program on a pooled VM. seed, when not nil, fills ---
+// the first global slots before running (e.g. host globals) ---
+func (p *Pool) Run(seed []object.Object, stdin io.Reader, stdout io.Writer) (result object.Object, err error) {
+	vm := p.Get()
+
+	// A VM panic (an operand it doesn't expect) is returned like the ---
+	// engine's recoverVM does, that VM isn't put back ---
+	defer func() {
+		if r := recover(); r != nil {
+			vm.tasks.Shutdown()
+			result, err = nil, errors.New(fmt.Sprint(r))
+			return
+		}
+		p.Put(vm)
+	}()
+
+	copy(vm.globals, seed)
+	if stdin != nil {
+		vm.Stdin = stdin
+	}
+	if stdout != nil {
+		vm.Stdout = stdout
+	}
+
+	err = vm.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	result = vm.LastPoppedElement()
+	if result == nil {
+		return object.NIL, nil
+	}
+
+	return result, nil
+}
+
+// NewFromProgram creates a VM with its own stack, frames and globals.
+func NewFromProgram(program *Program) *VM {
+	vm := &VM{
+		program:   program,
+		constants: program.constants,
+
+		stack:   make([]object.Object, STACK_SIZE),
+		globals: make([]object.Object, max(program.numGlobals, 1)),
+		frames:  make([]*Frame, MAX_FRAMES),
+		tasks:   scheduler.New(),
+	}
+
+	vm.builtins = vm.newBuiltins()
+	vm.reset()
+	return vm
+}
+
+func (vm *VM) reset() {
+	clear(vm.stack)
+	clear(vm.globals)
+	clear(vm.frames)
+
+	vm.stackPointer = 0
+	vm.yielded = false
+	vm.hook = nil
+
+	vm.mainFrame = NewFrame(vm.program.mainClosure, 0)
+	vm.frames[0] = vm.mainFrame
+	vm.framesIndex = 1
+
+	vm.Stdout = os.Stdout
+	vm.Stdin = os.Stdin
+	vm.stdinScanner = nil
+	vm.scannerInput = nil
+}
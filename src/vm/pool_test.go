@@ -0,0 +1,133 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/lexer"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/parser"
+)
+
+// Globals, closures (and their cells), hashes and loops all write to ---
+// per-VM state, the result depends on the seeded `input` global ---
+const poolSource = `
+fn counter() { var n = 0; return fn(step) { n += step; return n; }; }
+var c = counter();
+var total = 0;
+for x in [1, 2, 3, 4] { total = c(x * input); }
+var h = {"total": total, "name": "run" + to_string(input)};
+print(h["name"]);
+h["total"] + len(h["name"]);
+`
+
+func compileProgram(t *testing.T, source string, globals ...string) *Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	symbols := compiler.NewSymbolTable()
+	for _, name := range globals {
+		symbols.Define(name)
+	}
+
+	comp := compiler.NewWithState(symbols, make([]object.Object, 0))
+	err := comp.Compile(program)
+	if err != nil {
+		t.Fatalf("compiler error: %s", err)
+	}
+
+	return NewProgram(comp.Bytecode())
+}
+
+func TestPoolConcurrentRuns(t *testing.T) {
+	program := compileProgram(t, poolSource, "input")
+	pool := NewPool(program)
+
+	const goroutines = 2000
+	const runsEach = 3
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for run := 0; run < runsEach; run++ {
+				input := g*runsEach + run
+				name := fmt.Sprintf("run%d", input)
+
+				var out bytes.Buffer
+				result, err := pool.Run([]object.Object{&object.Integer{Value: int64(input)}}, nil, &out)
+				if err != nil {
+					errs <- fmt.Errorf("input %d: %s", input, err)
+					return
+				}
+
+				expected := fmt.Sprint(10*input + len(name))
+				if result.Inspect() != expected {
+					errs <- fmt.Errorf("input %d: expected result %s, got %s", input, expected, result.Inspect())
+					return
+				}
+
+				if out.String() != name+"\n" {
+					errs <- fmt.Errorf("input %d: expected output %q, got %q", input, name+"\n", out.String())
+					return
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestPoolRecoversPanics(t *testing.T) {
+	// Slicing an array isn't supported by the VM and panics ---
+	program := compileProgram(t, `if (input == 0) { [1, 2, 3]{0~1}; } input * 2;`, "input")
+	pool := NewPool(program)
+
+	_, err := pool.Run([]object.Object{&object.Integer{Value: 0}}, nil, &bytes.Buffer{})
+	if err == nil {
+		t.Fatalf("expected the panicking run to return an error")
+	}
+
+	result, err := pool.Run([]object.Object{&object.Integer{Value: 21}}, nil, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("clean run after a panic: %s", err)
+	}
+	if result.Inspect() != "42" {
+		t.Errorf("expected 42, got %s", result.Inspect())
+	}
+}
+
+func TestPoolResetsHooks(t *testing.T) {
+	program := compileProgram(t, `var x = 1; x + 1;`)
+	pool := NewPool(program)
+
+	calls := 0
+	vm := pool.Get()
+	vm.SetHook(func(*VM) error { calls++; return nil })
+	pool.Put(vm)
+
+	_, err := pool.Run(nil, nil, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("run error: %s", err)
+	}
+	if calls != 0 {
+		t.Errorf("a hook set before Put ran %d times on the next run", calls)
+	}
+}
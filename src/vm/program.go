@@ -0,0 +1,78 @@
+package vm
+
+import (
+	"github.com/caelondev/monkey-compiler-go/src/code"
+	"github.com/caelondev/monkey-compiler-go/src/compiler"
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// Program is compiled bytecode that is never written to after NewProgram, ---
+// so any number of VMs (and goroutines) can execute it at once. All ---
+// mutable state (stack, frames, globals) lives in the VM ---
+type Program struct {
+	constants   []object.Object
+	mainClosure *object.Closure
+	numGlobals  int
+	file        string // Recorded in stack traces ---
+}
+
+func NewProgram(bytecode *compiler.Bytecode) *Program {
+	// Copy, so later writes to the compiler's slices can't leak in ---
+	instructions := make(code.Instructions, len(bytecode.Instructions))
+	copy(instructions, bytecode.Instructions)
+
+	constants := make([]object.Object, len(bytecode.Constants))
+	copy(constants, bytecode.Constants)
+
+	mainFn := &object.CompiledFunction{
+		Instructions: instructions,
+		Handlers:     bytecode.Handlers,
+		Lines:        bytecode.Lines,
+	}
+
+	return &Program{
+		constants:   constants,
+		mainClosure: &object.Closure{Fn: mainFn},
+		numGlobals:  countGlobals(instructions, constants),
+		file:        bytecode.File,
+	}
+}
+
+// NumGlobals is the number of global slots the program touches.
+func (p *Program) NumGlobals() int {
+	return p.numGlobals
+}
+
+// Scans every function body for the highest global slot used ---
+func countGlobals(instructions code.Instructions, constants []object.Object) int {
+	highest := globalsIn(instructions)
+
+	for _, constant := range constants {
+		if fn, ok := constant.(*object.CompiledFunction); ok {
+			highest = max(highest, globalsIn(fn.Instructions))
+		}
+	}
+
+	return highest
+}
+
+func globalsIn(ins code.Instructions) int {
+	highest := 0
+
+	for i := 0; i < len(ins); {
+		def, err := code.Lookup(code.OpCode(ins[i]))
+		if err != nil {
+			i++
+			continue
+		}
+
+		operands, read := code.ReadOperands(def, ins[i+1:])
+		if code.OpCode(ins[i]) == code.OpSetGlobal || code.OpCode(ins[i]) == code.OpGetGlobal {
+			highest = max(highest, operands[0]+1)
+		}
+
+		i += 1 + read
+	}
+
+	return highest
+}
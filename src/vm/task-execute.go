@@ -0,0 +1,50 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey-compiler-go/src/object"
+)
+
+// The task gets its own stack and frames but shares globals, ---
+// builtins and the scheduler with the VM that spawned it ---
+func (vm *VM) executeSpawn(numArgs int) error {
+	callee := vm.stack[vm.stackPointer-1-numArgs]
+	if callee.Type() != object.FUNCTION_OBJECT {
+		return fmt.Errorf("Attempted to spawn %s", callee.Type())
+	}
+
+	args := make([]object.Object, numArgs)
+	copy(args, vm.stack[vm.stackPointer-numArgs:vm.stackPointer])
+	vm.stackPointer = vm.stackPointer - numArgs - 1
+
+	name := ""
+	if closure, ok := callee.(*object.Closure); ok {
+		name = closure.Fn.Name
+	}
+
+	child := vm.newTaskVM()
+	task := vm.tasks.Spawn(name, func() (object.Object, error) {
+		return child.CallFunction(callee, args...)
+	})
+
+	return vm.push(task)
+}
+
+func (vm *VM) newTaskVM() *VM {
+	return &VM{
+		program:   vm.program,
+		constants: vm.constants,
+
+		stack:   make([]object.Object, STACK_SIZE),
+		globals: vm.globals,
+		frames:  make([]*Frame, MAX_FRAMES),
+
+		builtins: vm.builtins,
+		tasks:    vm.tasks,
+		hook:     vm.hook,
+
+		Stdout: vm.Stdout,
+		Stdin:  vm.Stdin,
+	}
+}
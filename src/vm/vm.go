@@ -1,32 +1,106 @@
 package vm
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/caelondev/monkey-compiler-go/src/code"
 	"github.com/caelondev/monkey-compiler-go/src/object"
+	"github.com/caelondev/monkey-compiler-go/src/scheduler"
 )
 
 const STACK_SIZE = 2048
 const GLOBAL_SIZE = 65536
 
+// A VM runs on one goroutine at a time, to execute the same ---
+// bytecode concurrently share a Program and draw VMs from a Pool ---
 type VM struct {
-	instructions code.Instructions
-	constants    []object.Object
+	program   *Program
+	constants []object.Object
 
 	stack        []object.Object
 	globals      []object.Object
 	stackPointer int
+
+	frames      []*Frame
+	framesIndex int
+	mainFrame   *Frame
+
+	builtins []object.Object
+
+	// Shared by the VM and every task it spawns ---
+	tasks *scheduler.Scheduler
+
+	// Set by OpYield when this VM runs a generator ---
+	yielded bool
+
+	hook Hook // See SetHook ---
+
+	// Where the print/prompt builtins write and read ---
+	Stdout io.Writer
+	Stdin  io.Reader
+
+	stdinScanner *bufio.Scanner
+	scannerInput io.Reader
 }
 
 func (vm *VM) Run() error {
-	for instPointer := 0; instPointer < len(vm.instructions); instPointer++ {
-		op := code.OpCode(vm.instructions[instPointer])
+	err := vm.execute(0)
+	// Tasks still alive when the program ends are aborted ---
+	vm.tasks.Shutdown()
+	return err
+}
+
+// execute runs instructions until the frame count drops back to floor, ---
+// Run uses 0 (the main frame finishing), CallFunction uses the depth ---
+// it was called at so host calls can re-enter a running VM ---
+func (vm *VM) execute(floor int) error {
+	for {
+		err := vm.run(floor)
+		if err == nil {
+			return nil
+		}
+
+		err = vm.traceError(err)
+		if vm.handleError(err, floor) {
+			continue
+		}
+
+		if !isUncatchable(err) && vm.framesIndex > floor {
+			vm.closeIterators(vm.frames[floor].basePointer)
+		}
+		return err
+	}
+}
+
+func (vm *VM) run(floor int) error {
+	for vm.framesIndex > floor {
+		frame := vm.currentFrame()
+		ins := frame.Instructions()
+
+		// Only the main frame runs off its end, functions always return ---
+		if frame.ip >= len(ins)-1 {
+			vm.popFrame()
+			continue
+		}
+
+		if vm.hook != nil {
+			err := vm.hook(vm)
+			if err != nil {
+				return err
+			}
+		}
+
+		frame.ip++
+		ip := frame.ip
+		op := code.OpCode(ins[ip])
 
 		switch op {
 		case code.OpConstant:
-			constIndex := code.ReadUint16(vm.instructions[instPointer+1:])
-			instPointer += 2
+			constIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2
 
 			err := vm.push(vm.constants[constIndex])
 			if err != nil {
@@ -65,6 +139,20 @@ func (vm *VM) Run() error {
 				return err
 			}
 
+		case code.OpModulo, code.OpFloorDivide, code.OpBitAnd, code.OpBitOr, code.OpBitXor, code.OpShiftLeft, code.OpShiftRight:
+			right := vm.pop()
+			left := vm.pop()
+
+			result, err := object.IntegerOperation(opcodeToOperator(op), left, right)
+			if err != nil {
+				return err
+			}
+
+			err = vm.push(result)
+			if err != nil {
+				return err
+			}
+
 		case code.OpEqual, code.OpNotEqual, code.OpLess, code.OpLessEqual, code.OpGreater, code.OpGreaterEqual:
 			err := vm.executeComparison(op)
 			if err != nil {
@@ -85,6 +173,27 @@ func (vm *VM) Run() error {
 
 		case code.OpNegate:
 			prev := vm.peekStackAddr(0)
+			if integer, ok := prev.(*object.Integer); ok {
+				negated, err := object.Negate(integer.Value)
+				if err != nil {
+					return err
+				}
+
+				vm.stack[vm.stackPointer-1] = negated
+				break
+			}
+			if object.IsBig(prev) {
+				vm.stack[vm.stackPointer-1] = object.NegateBig(prev)
+				break
+			}
+			if infinity, ok := prev.(*object.Infinity); ok {
+				vm.stack[vm.stackPointer-1] = object.InfinityWithSign(-infinity.Sign)
+				break
+			}
+			if _, ok := prev.(*object.NaN); ok {
+				break
+			}
+
 			num, ok := prev.(*object.Number)
 			if !ok {
 				return fmt.Errorf("Cannot negate non-numeric value type '%s'\n", prev.Type())
@@ -94,6 +203,24 @@ func (vm *VM) Run() error {
 
 		case code.OpAbsolute:
 			prev := vm.peekStackAddr(0)
+			if integer, ok := prev.(*object.Integer); ok {
+				if integer.Value < 0 {
+					absolute, err := object.Negate(integer.Value)
+					if err != nil {
+						return err
+					}
+
+					vm.stack[vm.stackPointer-1] = absolute
+				}
+				break
+			}
+			if object.IsBig(prev) {
+				if object.SignOf(prev) < 0 {
+					vm.stack[vm.stackPointer-1] = object.NegateBig(prev)
+				}
+				break
+			}
+
 			num, ok := prev.(*object.Number)
 			if !ok {
 				return fmt.Errorf("Cannot take the absolute value of a non-numeric value type '%s'\n", prev.Type())
@@ -104,6 +231,86 @@ func (vm *VM) Run() error {
 				vm.stack[vm.stackPointer-1] = &object.Number{Value: -num.Value}
 			}
 
+		case code.OpPick:
+			depth := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.push(vm.peekStackAddr(int(depth)))
+			if err != nil {
+				return err
+			}
+
+		case code.OpBury:
+			depth := int(code.ReadUint8(ins[ip+1:]))
+			frame.ip += 1
+
+			top := vm.stack[vm.stackPointer-1]
+			copy(vm.stack[vm.stackPointer-depth:vm.stackPointer], vm.stack[vm.stackPointer-depth-1:vm.stackPointer-1])
+			vm.stack[vm.stackPointer-depth-1] = top
+
+		case code.OpUnpackArray:
+			required := int(code.ReadUint16(ins[ip+1:]))
+			count := int(code.ReadUint16(ins[ip+3:]))
+			hasRest := code.ReadUint8(ins[ip+5:]) == 1
+			frame.ip += 5
+
+			_, err := object.UnpackArray(vm.peekStackAddr(0), required, count, hasRest)
+			if err != nil {
+				return err
+			}
+
+		case code.OpUnpackElement:
+			index := int(code.ReadUint16(ins[ip+1:]))
+			rest := code.ReadUint8(ins[ip+3:]) == 1
+			frame.ip += 3
+
+			// OpUnpackArray checked it's an array ---
+			array := vm.peekStackAddr(0).(*object.Array)
+			err := vm.push(object.UnpackElement(array, index, rest))
+			if err != nil {
+				return err
+			}
+
+		case code.OpUnpackHash:
+			_, err := object.UnpackHash(vm.peekStackAddr(0))
+			if err != nil {
+				return err
+			}
+
+		case code.OpUnpackKey:
+			key := vm.constants[code.ReadUint16(ins[ip+1:])].(*object.String)
+			hasDefault := code.ReadUint8(ins[ip+3:]) == 1
+			frame.ip += 3
+
+			// OpUnpackHash checked it's a hash ---
+			value, err := object.UnpackKey(vm.peekStackAddr(0).(*object.Hash), key, hasDefault)
+			if err != nil {
+				return err
+			}
+
+			err = vm.push(value)
+			if err != nil {
+				return err
+			}
+
+		case code.OpJumpNotNil:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			if vm.peekStackAddr(0).Type() != object.NIL_OBJECT {
+				frame.ip = pos - 1
+			} else {
+				vm.pop()
+			}
+
+		case code.OpBitNot:
+			inverted, err := object.BitwiseNot(vm.peekStackAddr(0))
+			if err != nil {
+				return err
+			}
+
+			vm.stack[vm.stackPointer-1] = inverted
+
 		case code.OpNot:
 			prev := vm.peekStackAddr(0)
 			var boolObj *object.Boolean
@@ -118,15 +325,15 @@ func (vm *VM) Run() error {
 			vm.stack[vm.stackPointer-1] = boolObj
 
 		case code.OpJump:
-			pos := int(code.ReadUint16(vm.instructions[instPointer+1:]))
-			instPointer = pos - 1
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip = pos - 1
 		case code.OpJumpNotTruthy:
-			pos := int(code.ReadUint16(vm.instructions[instPointer+1:]))
-			instPointer += 2
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
 
 			condition := vm.pop()
 			if !isTruthy(condition) {
-				instPointer = pos - 1
+				frame.ip = pos - 1
 			}
 
 		case code.OpNil:
@@ -136,14 +343,14 @@ func (vm *VM) Run() error {
 			}
 
 		case code.OpSetGlobal:
-			globalIndex := code.ReadUint16(vm.instructions[instPointer+1:])
-			instPointer += 2
+			globalIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2
 
 			vm.globals[globalIndex] = vm.pop()
 
 		case code.OpGetGlobal:
-			globalIndex := code.ReadUint16(vm.instructions[instPointer+1:])
-			instPointer += 2
+			globalIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2
 
 			err := vm.push(vm.globals[globalIndex])
 			if err != nil {
@@ -156,19 +363,19 @@ func (vm *VM) Run() error {
 			target := vm.pop()
 
 			if start.Type() == object.NIL_OBJECT {
-				start = &object.Number{Value: 0}
+				start = &object.Integer{Value: 0}
 			}
 			if end.Type() == object.NIL_OBJECT {
-				end = &object.Number{Value: float64(len(target.Inspect()) - 2)}
+				end = &object.Integer{Value: int64(len(target.Inspect()) - 2)}
 				// NOTE: -2 is for the "" trim
 			}
 
-			if start.Type() != object.NUMBER_OBJECT || end.Type() != object.NUMBER_OBJECT {
+			if !isNumeric(start) || !isNumeric(end) {
 				return fmt.Errorf("Cannot slice expression with invalid index slicing types ('%s' and '%s')", start.Type(), end.Type())
 			}
 
-			endVal := int(end.(*object.Number).Value)
-			startVal := int(start.(*object.Number).Value)
+			endVal, _ := object.ToInt(end)
+			startVal, _ := object.ToInt(start)
 
 			// Check over/under slice
 			// NOTE: -2 is for the "" trim
@@ -187,8 +394,8 @@ func (vm *VM) Run() error {
 			}
 
 		case code.OpArray:
-			arrayLength := code.ReadUint16(vm.instructions[instPointer+1:])
-			instPointer += 2 // Skip length bytes
+			arrayLength := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2 // Skip length bytes
 
 			elements := make([]object.Object, arrayLength)
 			for i := int(arrayLength) - 1; i >= 0; i-- {
@@ -202,6 +409,232 @@ func (vm *VM) Run() error {
 
 		case code.OpPop:
 			vm.pop()
+
+		case code.OpConcat:
+			count := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			var out strings.Builder
+			for _, part := range vm.stack[vm.stackPointer-count : vm.stackPointer] {
+				out.WriteString(object.Display(part))
+			}
+			vm.stackPointer -= count
+
+			err := vm.push(&object.String{Value: out.String()})
+			if err != nil {
+				return err
+			}
+
+		case code.OpHash:
+			numElements := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			hash, err := vm.buildHash(vm.stackPointer-numElements, vm.stackPointer)
+			if err != nil {
+				return err
+			}
+			vm.stackPointer -= numElements
+
+			err = vm.push(hash)
+			if err != nil {
+				return err
+			}
+
+		case code.OpIndex:
+			index := vm.pop()
+			target := vm.pop()
+
+			err := vm.executeIndexExpression(target, index)
+			if err != nil {
+				return err
+			}
+
+		case code.OpSetIndex:
+			value := vm.pop()
+			index := vm.pop()
+			target := vm.pop()
+
+			err := vm.executeSetIndex(target, index, value)
+			if err != nil {
+				return err
+			}
+
+		case code.OpCall:
+			numArgs := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.executeCall(int(numArgs))
+			if err != nil {
+				return err
+			}
+
+		case code.OpReturnValue:
+			returnValue := vm.pop()
+
+			// Loops the return leaves close their iterators ---
+			vm.closeIterators(frame.basePointer + frame.closure.Fn.NumLocals)
+
+			// Top-level return ends the program ---
+			if frame == vm.mainFrame {
+				vm.stackPointer = 0
+				vm.stack[0] = returnValue
+				frame.ip = len(ins) - 1
+				continue
+			}
+
+			frame := vm.popFrame()
+			vm.stackPointer = frame.basePointer - 1
+
+			err := vm.push(returnValue)
+			if err != nil {
+				return err
+			}
+
+		case code.OpReturn:
+			vm.closeIterators(frame.basePointer + frame.closure.Fn.NumLocals)
+
+			frame := vm.popFrame()
+			vm.stackPointer = frame.basePointer - 1
+
+			err := vm.push(object.NIL)
+			if err != nil {
+				return err
+			}
+
+		case code.OpGetLocal:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.push(deref(vm.stack[frame.basePointer+int(localIndex)]))
+			if err != nil {
+				return err
+			}
+
+		case code.OpSetLocal:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			store(&vm.stack[frame.basePointer+int(localIndex)], vm.pop())
+
+		case code.OpGetBuiltin:
+			builtinIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.push(vm.builtins[builtinIndex])
+			if err != nil {
+				return err
+			}
+
+		case code.OpClosure:
+			constIndex := code.ReadUint16(ins[ip+1:])
+			numFree := code.ReadUint8(ins[ip+3:])
+			frame.ip += 3
+
+			err := vm.pushClosure(int(constIndex), int(numFree))
+			if err != nil {
+				return err
+			}
+
+		case code.OpGetFree:
+			freeIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.push(deref(frame.closure.Free[freeIndex]))
+			if err != nil {
+				return err
+			}
+
+		case code.OpSetFree:
+			freeIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			store(&frame.closure.Free[freeIndex], vm.pop())
+
+		case code.OpCaptureLocal:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			slot := &vm.stack[frame.basePointer+int(localIndex)]
+			cell, ok := (*slot).(*object.Cell)
+			if !ok {
+				cell = &object.Cell{Value: *slot}
+				*slot = cell
+			}
+
+			err := vm.push(cell)
+			if err != nil {
+				return err
+			}
+
+		case code.OpCaptureFree:
+			freeIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.push(frame.closure.Free[freeIndex])
+			if err != nil {
+				return err
+			}
+
+		case code.OpCurrentClosure:
+			err := vm.push(frame.closure)
+			if err != nil {
+				return err
+			}
+
+		case code.OpSpawn:
+			numArgs := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.executeSpawn(int(numArgs))
+			if err != nil {
+				return err
+			}
+
+		case code.OpThrow:
+			return &ThrownError{Err: object.NewThrownError(vm.pop(), 0, 0)}
+
+		case code.OpYield:
+			// The value stays on the stack for the generator to pick up ---
+			vm.yielded = true
+			return nil
+
+		case code.OpIter:
+			err := vm.executeIter()
+			if err != nil {
+				return err
+			}
+
+		case code.OpIterNext:
+			target := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			iter := vm.StackTop().(*iterator)
+			value, ok, err := iter.next()
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				vm.pop()
+				frame.ip = target - 1
+				continue
+			}
+
+			err = vm.push(value)
+			if err != nil {
+				return err
+			}
+
+		case code.OpAwait:
+			result, err := vm.tasks.AwaitValue(vm.pop())
+			if err != nil {
+				return err
+			}
+
+			err = vm.push(result)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -242,3 +675,17 @@ func (vm *VM) GetStackPointer() int {
 func (vm *VM) peekStackAddr(n int) object.Object {
 	return vm.stack[vm.stackPointer-n-1]
 }
+
+func (vm *VM) currentFrame() *Frame {
+	return vm.frames[vm.framesIndex-1]
+}
+
+func (vm *VM) pushFrame(f *Frame) {
+	vm.frames[vm.framesIndex] = f
+	vm.framesIndex++
+}
+
+func (vm *VM) popFrame() *Frame {
+	vm.framesIndex--
+	return vm.frames[vm.framesIndex]
+}